@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/config"
+	"searxng-mcp/pkg/searxng"
+)
+
+// defaultEngineStatsPath returns the default engine statistics file, or "" if unavailable
+func defaultEngineStatsPath() string {
+	path, err := config.Path("engine_stats.json")
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// runEnginesCommand prints the engine reliability statistics recorded by the server
+func runEnginesCommand(args []string) error {
+	fs := flag.NewFlagSet("engines", flag.ExitOnError)
+	statsPath := fs.String("stats", defaultEngineStatsPath(), "Engine statistics file")
+	excludedOnly := fs.Bool("excluded", false, "Only show engines that are currently excluded")
+	asJSON := fs.Bool("json", false, "Print statistics as JSON (same as -format json)")
+	format := fs.String("format", "table", "Output format: table, markdown, text, json or citation")
+	reset := fs.Bool("reset", false, "Clear all recorded statistics. Stop the server first: a running server writes its statistics back")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *statsPath == "" {
+		return fmt.Errorf("no engine statistics file configured")
+	}
+
+	if *reset {
+		if err := searxng.SaveEngineStats(*statsPath, nil); err != nil {
+			return fmt.Errorf("failed to reset engine statistics: %w", err)
+		}
+		fmt.Println("Engine statistics cleared")
+		return nil
+	}
+
+	stats, err := searxng.LoadEngineStats(*statsPath)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		*format = string(tools.FormatJSON)
+	}
+
+	// Every format but the table uses the renderers of the engine_stats tool
+	if *format != "table" {
+		f, err := tools.ParseFormat(*format)
+		if err != nil {
+			return err
+		}
+		output := tools.NewEngineStatsOutput(stats, time.Now(), *excludedOnly)
+		fmt.Println(strings.TrimRight(tools.RendererFor(f).Render(output), "\n"))
+		return nil
+	}
+
+	reports := tools.BuildEngineReports(stats, time.Now(), *excludedOnly)
+	if len(reports) == 0 {
+		fmt.Println("No engine statistics recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENGINE\tQUERIES\tTIMEOUTS\tERRORS\tTOP RESULTS\tFAILURE RATE\tSTATUS")
+	for _, r := range reports {
+		status := "ok"
+		if r.Excluded {
+			status = "excluded until " + *r.ExcludedUntil
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.0f%%\t%s\n",
+			r.Engine, r.Queries, r.Timeouts, r.Errors, r.TopResults, r.FailureRate*100, status)
+	}
+	return w.Flush()
+}
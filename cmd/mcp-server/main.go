@@ -5,15 +5,22 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
+	"searxng-mcp/internal/mcp/logging"
 	"searxng-mcp/internal/mcp/server"
+	"searxng-mcp/internal/mcp/tools"
 	"searxng-mcp/pkg/config"
+	"searxng-mcp/pkg/domains"
+	"searxng-mcp/pkg/history"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -68,12 +75,11 @@ func ensureSearXNGContainer(ctx context.Context) error {
 		// Container doesn't exist, create and run it
 		log.Println("Creating new SearXNG container...")
 
-		// Get user home directory for config path
-		homeDir, err := os.UserHomeDir()
+		// Get config directory path
+		configPath, err := config.Dir()
 		if err != nil {
-			return fmt.Errorf("failed to get user home directory: %w", err)
+			return err
 		}
-		configPath := filepath.Join(homeDir, ".config", "searxng-mcp")
 
 		// Ensure config directory exists, create if not
 		if err := os.MkdirAll(configPath, 0755); err != nil {
@@ -117,14 +123,98 @@ func ensureSearXNGContainer(ctx context.Context) error {
 	return nil
 }
 
+// instanceFlag collects repeated -instance name=url flags
+type instanceFlag map[string]string
+
+// String lists the instances as name=url pairs
+func (f instanceFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, instanceURL := range f {
+		pairs = append(pairs, name+"="+instanceURL)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set adds an instance given as name=url
+func (f instanceFlag) Set(value string) error {
+	name, rawURL, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=url, got '%s'", value)
+	}
+	if name == tools.DefaultInstance {
+		return fmt.Errorf("instance name '%s' is reserved for -url", name)
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL '%s' for instance '%s'", rawURL, name)
+	}
+	f[name] = strings.TrimRight(u.String(), "/")
+	return nil
+}
+
+// defaultConfigPath returns the path of a file or directory in the config directory
+func defaultConfigPath(name string) string {
+	path, err := config.Path(name)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
 func main() {
+	// Dispatch subcommands
+	if len(os.Args) > 1 && os.Args[1] == "engines" {
+		if err := runEnginesCommand(os.Args[2:]); err != nil {
+			log.Fatalf("engines: %v", err)
+		}
+		return
+	}
+
 	// Define command line flags
 	var searxngURL string
 	var autoLaunch bool
+	var opts server.Options
 	flag.StringVar(&searxngURL, "url", "http://localhost:8888", "SearXNG server URL")
 	flag.BoolVar(&autoLaunch, "auto-launch", false, "Automatically start SearXNG container with Docker if needed")
+	flag.StringVar(&opts.Reliability.Path, "engine-stats", defaultEngineStatsPath(), "File used to persist engine reliability statistics (empty disables persistence)")
+	flag.IntVar(&opts.Reliability.FailureThreshold, "engine-failure-threshold", 3, "Consecutive failures before an engine is excluded")
+	flag.DurationVar(&opts.Reliability.ProbeInterval, "engine-probe-interval", 10*time.Minute, "How long an excluded engine waits before being re-probed")
+	format := flag.String("format", string(tools.FormatMarkdown), "Default rendering of tool text content: markdown, text, json or citation")
+	allowlist := flag.String("allowlist", defaultConfigPath("allowlist.txt"), "File of domains search results are restricted to, one per line (missing means all)")
+	blocklist := flag.String("blocklist", defaultConfigPath("blocklist.txt"), "File of domains removed from search results, one per line")
+	flag.StringVar(&opts.PromptsDir, "prompts", defaultConfigPath("prompts"), "Directory of custom prompt templates, one YAML file per prompt")
+	flag.StringVar(&opts.WatchesPath, "watches", defaultConfigPath("watches.json"), "File used to persist watched queries (empty disables persistence)")
+	flag.Float64Var(&opts.Logging.Rate, "log-rate", logging.DefaultRate, "Log notifications per second sent to a client session")
+	flag.IntVar(&opts.Logging.Burst, "log-burst", logging.DefaultBurst, "Log notifications a client session may receive at once")
+	flag.BoolVar(&opts.AllowPrivateFetch, "allow-private-fetch", false, "Let fetch_url and the other page and image fetches reach loopback, private and link-local addresses")
+	opts.Instances = make(map[string]string)
+	flag.Var(instanceFlag(opts.Instances), "instance", "Further SearXNG instance compare_searches can query, as name=url (repeatable)")
+	var historyOpts history.Options
+	enableHistory := flag.Bool("history", false, "Record searches in a local history for search_history and rerun_search")
+	flag.StringVar(&historyOpts.Path, "history-file", defaultConfigPath("history.jsonl"), "File used to persist the search history (empty keeps it in memory)")
+	flag.DurationVar(&historyOpts.MaxAge, "history-retention", history.DefaultMaxAge, "How long searches are kept in the history (0 keeps them until -history-max-entries)")
+	flag.IntVar(&historyOpts.MaxEntries, "history-max-entries", history.DefaultMaxEntries, "Number of most recent searches kept in the history (0 keeps all)")
 	flag.Parse()
 
+	defaultFormat, err := tools.ParseFormat(*format)
+	if err != nil {
+		log.Fatalf("Invalid -format: %v", err)
+	}
+	opts.Format = defaultFormat
+	if *enableHistory {
+		opts.History = &historyOpts
+	}
+
+	// Load the server-wide domain lists
+	if opts.Domains.Allow, err = domains.LoadList(*allowlist); err != nil {
+		log.Fatalf("Invalid -allowlist: %v", err)
+	}
+	if opts.Domains.Block, err = domains.LoadList(*blocklist); err != nil {
+		log.Fatalf("Invalid -blocklist: %v", err)
+	}
+
 	// Create context that cancels on interrupt signal
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
@@ -137,7 +227,7 @@ func main() {
 	}
 
 	// Initialize our MCP server with custom URL
-	mcpServer, err := server.NewSearXNGServer(searxngURL)
+	mcpServer, err := server.NewSearXNGServer(searxngURL, &opts)
 	if err != nil {
 		log.Fatalf("Failed to create SearXNG MCP server: %v", err)
 	}
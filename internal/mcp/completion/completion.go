@@ -0,0 +1,265 @@
+// Package completion completes tool, prompt and resource template arguments
+// from the catalog and autocompleter of a SearXNG instance
+package completion
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+)
+
+const (
+	// maxValues is the most completion values a response may carry
+	maxValues = 100
+	// catalogTTL is how long a fetched catalog is reused
+	catalogTTL = 10 * time.Minute
+	// catalogTimeout bounds a fetch of the catalog
+	catalogTimeout = 5 * time.Second
+	// catalogRetryDelay is how long completions go without fetching the catalog after a fetch fails
+	catalogRetryDelay = 30 * time.Second
+	// autocompleteTimeout bounds a call to the autocompleter, which runs on every keystroke
+	autocompleteTimeout = 3 * time.Second
+)
+
+// Source provides the catalog and query completions of a SearXNG instance
+type Source interface {
+	Catalog(ctx context.Context) (*searxng.Catalog, error)
+	Autocomplete(ctx context.Context, query string) ([]string, error)
+}
+
+// kind is the kind of value an argument holds
+type kind int
+
+const (
+	kindNone kind = iota
+	kindCategory
+	kindTimeRange
+	kindLanguage
+	kindEngine
+	kindQuery
+)
+
+// argumentKinds maps the argument names of the tools, prompts and resource
+// templates to the kind of value they hold
+var argumentKinds = map[string]kind{
+	"categories": kindCategory,
+	"category":   kindCategory,
+	"time_range": kindTimeRange,
+	"language":   kindLanguage,
+	"lang":       kindLanguage,
+	"engines":    kindEngine,
+	"engine":     kindEngine,
+	"query":      kindQuery,
+	"q":          kindQuery,
+	"question":   kindQuery,
+	"topic":      kindQuery,
+}
+
+// Completer answers completion requests. Completions depend only on the
+// argument name, so arguments shared by tools, prompts and resource templates
+// complete alike.
+type Completer struct {
+	source Source
+	now    func() time.Time
+
+	mu        sync.Mutex
+	catalog   *searxng.Catalog
+	fetchedAt time.Time
+	// fetching is set while a catalog fetch is in flight
+	fetching bool
+	// retryAt delays the next fetch after a failed one
+	retryAt time.Time
+}
+
+// New creates a Completer reading from source
+func New(source Source) *Completer {
+	return &Completer{source: source, now: time.Now}
+}
+
+// Complete is the completion handler of the MCP server
+func (c *Completer) Complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	var contextArgs map[string]string
+	if req.Params.Context != nil {
+		contextArgs = req.Params.Context.Arguments
+	}
+	values := c.values(ctx, req.Params.Argument.Name, req.Params.Argument.Value, contextArgs)
+
+	result := &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{}}}
+	if len(values) > maxValues {
+		result.Completion.HasMore = true
+		result.Completion.Total = len(values)
+		values = values[:maxValues]
+	}
+	if values != nil {
+		result.Completion.Values = values
+	}
+	return result, nil
+}
+
+// values returns the completions of an argument value
+func (c *Completer) values(ctx context.Context, name, value string, contextArgs map[string]string) []string {
+	switch argumentKinds[name] {
+	case kindCategory:
+		// Category lists are comma-separated; complete the last entry
+		head, last := splitList(value)
+		return prefixed(head, matching(c.categories(ctx), last))
+	case kindTimeRange:
+		var names []string
+		for _, tr := range searxng.GetAllTimeRanges() {
+			names = append(names, string(tr))
+		}
+		return matching(names, value)
+	case kindLanguage:
+		return c.languages(ctx, value)
+	case kindEngine:
+		head, last := splitList(value)
+		return prefixed(head, c.engines(ctx, last, contextArgs))
+	case kindQuery:
+		ctx, cancel := context.WithTimeout(ctx, autocompleteTimeout)
+		defer cancel()
+		completions, err := c.source.Autocomplete(ctx, strings.TrimSpace(value))
+		if err != nil {
+			return nil
+		}
+		return completions
+	}
+	return nil
+}
+
+// categories returns the categories of the instance, or the built-in
+// categories when the catalog is unavailable
+func (c *Completer) categories(ctx context.Context) []string {
+	if catalog := c.getCatalog(ctx); catalog != nil && len(catalog.Categories) > 0 {
+		return catalog.Categories
+	}
+	var names []string
+	for _, category := range searxng.GetAllCategories() {
+		names = append(names, string(category))
+	}
+	return names
+}
+
+// languages returns the language codes of the instance whose code or name starts with prefix
+func (c *Completer) languages(ctx context.Context, prefix string) []string {
+	codes := []string{"all", "auto"}
+	names := map[string]string{}
+	if catalog := c.getCatalog(ctx); catalog != nil {
+		for code, name := range catalog.Locales {
+			codes = append(codes, code)
+			names[code] = name
+		}
+	}
+	sort.Strings(codes[2:])
+
+	var values []string
+	for _, code := range codes {
+		if hasPrefixFold(code, prefix) || hasPrefixFold(names[code], prefix) {
+			values = append(values, code)
+		}
+	}
+	return values
+}
+
+// engines returns the enabled engines whose name or shortcut starts with
+// prefix, restricted to the categories given in contextArgs
+func (c *Completer) engines(ctx context.Context, prefix string, contextArgs map[string]string) []string {
+	catalog := c.getCatalog(ctx)
+	if catalog == nil {
+		return nil
+	}
+	var wanted []string
+	for _, category := range strings.Split(contextArgs["categories"]+","+contextArgs["category"], ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			wanted = append(wanted, category)
+		}
+	}
+
+	var values []string
+	for _, engine := range catalog.Engines {
+		if !engine.Enabled || slices.Contains(values, engine.Name) {
+			continue
+		}
+		if len(wanted) > 0 && !slices.ContainsFunc(engine.Categories, func(c string) bool { return slices.Contains(wanted, c) }) {
+			continue
+		}
+		if hasPrefixFold(engine.Name, prefix) || hasPrefixFold(engine.Shortcut, prefix) {
+			values = append(values, engine.Name)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// getCatalog returns the catalog of the instance, fetching it when the cached
+// one is missing or stale. It returns the stale catalog, or nil, when the fetch
+// fails, while another fetch is in flight, and for a while after a failed fetch.
+func (c *Completer) getCatalog(ctx context.Context) *searxng.Catalog {
+	c.mu.Lock()
+	now := c.now()
+	if c.catalog != nil && now.Sub(c.fetchedAt) < catalogTTL || c.fetching || now.Before(c.retryAt) {
+		catalog := c.catalog
+		c.mu.Unlock()
+		return catalog
+	}
+	c.fetching = true
+	c.mu.Unlock()
+
+	// Fetch outside the lock so a slow instance does not hold up other completions
+	ctx, cancel := context.WithTimeout(ctx, catalogTimeout)
+	defer cancel()
+	catalog, err := c.source.Catalog(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetching = false
+	if err != nil {
+		c.retryAt = c.now().Add(catalogRetryDelay)
+		return c.catalog
+	}
+	c.catalog = catalog
+	c.fetchedAt = c.now()
+	return catalog
+}
+
+// matching returns the values starting with prefix, ignoring case
+func matching(values []string, prefix string) []string {
+	var matches []string
+	for _, v := range values {
+		if hasPrefixFold(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+// splitList splits a comma-separated value before its last entry, returning
+// the entries before it, with their trailing comma, and the trimmed last entry
+func splitList(value string) (string, string) {
+	i := strings.LastIndex(value, ",")
+	if i < 0 {
+		return "", strings.TrimSpace(value)
+	}
+	return value[:i+1], strings.TrimSpace(value[i+1:])
+}
+
+// prefixed prepends head to each value
+func prefixed(head string, values []string) []string {
+	if head == "" {
+		return values
+	}
+	for i, v := range values {
+		values[i] = head + v
+	}
+	return values
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,130 @@
+package completion_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/completion"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Completion", func() {
+	var (
+		server       *httptest.Server
+		completer    *completion.Completer
+		ctx          context.Context
+		configCalls  int
+		configFails  bool
+		autocomplete []string
+	)
+
+	// complete requests the completions of an argument of the search resource template.
+	// The handler is called directly: ClientSession.Complete panics in go-sdk v0.3.0.
+	complete := func(name, value string, contextArgs map[string]string) mcp.CompletionResultDetails {
+		params := &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: "searxng://search/{?q,category,lang}"},
+			Argument: mcp.CompleteParamsArgument{Name: name, Value: value},
+		}
+		if contextArgs != nil {
+			params.Context = &mcp.CompleteContext{Arguments: contextArgs}
+		}
+		result, err := completer.Complete(ctx, &mcp.CompleteRequest{Params: params})
+		Expect(err).NotTo(HaveOccurred())
+		return result.Completion
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		configCalls = 0
+		configFails = false
+		autocomplete = []string{"raft consensus", "raft protocol"}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/config":
+				configCalls++
+				if configFails {
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				Expect(json.NewEncoder(w).Encode(map[string]any{
+					"categories": []string{"general", "images", "it", "science"},
+					"engines": []map[string]any{
+						{"name": "github", "categories": []string{"it"}, "shortcut": "gh", "enabled": true},
+						{"name": "google", "categories": []string{"general"}, "shortcut": "go", "enabled": true},
+						{"name": "gitlab", "categories": []string{"it"}, "shortcut": "gl", "enabled": false},
+						{"name": "arxiv", "categories": []string{"science"}, "shortcut": "arx", "enabled": true},
+					},
+					"locales":      map[string]string{"de": "Deutsch", "en": "English", "fr": "Français"},
+					"autocomplete": "duckduckgo",
+				})).To(Succeed())
+			case "/autocompleter":
+				Expect(json.NewEncoder(w).Encode([]any{r.URL.Query().Get("q"), autocomplete})).To(Succeed())
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		completer = completion.New(searxng.NewClient(server.URL))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should complete categories from the instance catalog", func() {
+		Expect(complete("category", "i", nil).Values).To(Equal([]string{"images", "it"}))
+		Expect(complete("categories", "general,S", nil).Values).To(Equal([]string{"general,science"}))
+	})
+
+	It("should complete time ranges", func() {
+		Expect(complete("time_range", "y", nil).Values).To(Equal([]string{"year"}))
+	})
+
+	It("should complete languages by code or name", func() {
+		Expect(complete("lang", "fr", nil).Values).To(Equal([]string{"fr"}))
+		Expect(complete("language", "deu", nil).Values).To(Equal([]string{"de"}))
+		Expect(complete("language", "a", nil).Values).To(Equal([]string{"all", "auto"}))
+	})
+
+	It("should complete enabled engines by name or shortcut, within the chosen categories", func() {
+		Expect(complete("engines", "g", nil).Values).To(Equal([]string{"github", "google"}))
+		Expect(complete("engines", "g", map[string]string{"categories": "it"}).Values).To(Equal([]string{"github"}))
+		Expect(complete("engines", "arxiv,gh", nil).Values).To(Equal([]string{"arxiv,github"}))
+	})
+
+	It("should cache the catalog", func() {
+		complete("category", "", nil)
+		complete("engines", "", nil)
+		Expect(configCalls).To(Equal(1))
+	})
+
+	It("should fall back to the built-in categories and not refetch right after a failed fetch", func() {
+		configFails = true
+
+		Expect(complete("category", "sci", nil).Values).To(Equal([]string{"science"}))
+		Expect(complete("engines", "g", nil).Values).To(BeEmpty())
+		Expect(configCalls).To(Equal(1))
+	})
+
+	It("should complete queries with the autocompleter", func() {
+		Expect(complete("q", "raft", nil).Values).To(Equal([]string{"raft consensus", "raft protocol"}))
+
+		result, err := completer.Complete(ctx, &mcp.CompleteRequest{Params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "research_topic"},
+			Argument: mcp.CompleteParamsArgument{Name: "topic", Value: "raft"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Completion.Values).To(HaveLen(2))
+	})
+
+	It("should return no values for other arguments", func() {
+		Expect(complete("format", "m", nil).Values).To(BeEmpty())
+	})
+})
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Client wraps a searxng.Client, logging the latency, result count and failed
+// engines of every search to the session of the request
+type Client struct {
+	client searxng.Client
+}
+
+// NewClient creates a Client logging the searches of client
+func NewClient(client searxng.Client) *Client {
+	return &Client{client: client}
+}
+
+// Search performs a search and logs its outcome
+func (c *Client) Search(ctx context.Context, req searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	logger := FromContext(ctx)
+	start := time.Now()
+	resp, err := c.client.Search(ctx, req)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		logger.ErrorContext(ctx, "searxng search failed", "query", req.Query, "page", max(req.PageNo, 1), "latency_ms", latency, "error", err.Error())
+		return nil, err
+	}
+
+	logger.DebugContext(ctx, "searxng search", "query", req.Query, "categories", req.Category, "page", max(req.PageNo, 1),
+		"latency_ms", latency, "results", len(resp.Results), "disabled_engines", req.DisabledEngines)
+	if len(resp.UnresponsiveEngines) > 0 {
+		failed := make(map[string]string, len(resp.UnresponsiveEngines))
+		for _, engine := range resp.UnresponsiveEngines {
+			failed[engine.Engine] = engine.Error
+		}
+		logger.WarnContext(ctx, "searxng engines failed", "query", req.Query, "engines", failed)
+	}
+	return resp, nil
+}
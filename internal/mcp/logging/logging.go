@@ -0,0 +1,212 @@
+// Package logging sends structured log messages to MCP clients as logging
+// notifications, at the level each client sets with logging/setLevel
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LoggerName is the logger reported in the notifications of the server
+const LoggerName = "searxng-mcp"
+
+// Default rate limits of a session
+const (
+	DefaultRate  = 10
+	DefaultBurst = 50
+)
+
+// Options configures the log volume sent to each session
+type Options struct {
+	// Rate is the sustained number of messages per second a session receives;
+	// zero means DefaultRate
+	Rate float64
+	// Burst is the number of messages a session may receive at once; zero means DefaultBurst
+	Burst int
+}
+
+// loggerKey is the context key of the logger of a request
+type loggerKey struct{}
+
+// Sessions keeps a rate-limited logger per MCP session
+type Sessions struct {
+	opts Options
+
+	mu      sync.Mutex
+	loggers map[*mcp.ServerSession]*slog.Logger
+}
+
+// NewSessions creates the loggers of the sessions of a server
+func NewSessions(opts Options) *Sessions {
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultRate
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultBurst
+	}
+	return &Sessions{opts: opts, loggers: map[*mcp.ServerSession]*slog.Logger{}}
+}
+
+// Logger returns the logger of session, creating it on first use. The logger
+// is forgotten when the session ends.
+func (s *Sessions) Logger(session *mcp.ServerSession) *slog.Logger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if logger, ok := s.loggers[session]; ok {
+		return logger
+	}
+
+	logger := slog.New(&handler{
+		inner:   mcp.NewLoggingHandler(session, &mcp.LoggingHandlerOptions{LoggerName: LoggerName}),
+		limiter: newLimiter(s.opts.Rate, s.opts.Burst),
+	})
+	s.loggers[session] = logger
+	go func() {
+		session.Wait()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.loggers, session)
+	}()
+	return logger
+}
+
+// Middleware gives every request the logger of its session and logs the
+// latency and outcome of tool calls
+func (s *Sessions) Middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		session, ok := req.GetSession().(*mcp.ServerSession)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		logger := s.Logger(session)
+		ctx = WithLogger(ctx, logger)
+
+		params, ok := req.GetParams().(*mcp.CallToolParams)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		start := time.Now()
+		result, err := next(ctx, method, req)
+		attrs := []any{"tool", params.Name, "latency_ms", time.Since(start).Milliseconds()}
+		switch res, _ := result.(*mcp.CallToolResult); {
+		case err != nil:
+			logger.ErrorContext(ctx, "tool call failed", append(attrs, "error", err.Error())...)
+		case res != nil && res.IsError:
+			logger.WarnContext(ctx, "tool call returned an error", attrs...)
+		default:
+			logger.DebugContext(ctx, "tool call completed", attrs...)
+		}
+		return result, err
+	}
+}
+
+// WithLogger returns ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger of ctx, or a logger discarding every message
+// when ctx carries none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return discard
+}
+
+// discard is the logger of requests without a session
+var discard = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that is never enabled
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// handler redacts records and rate-limits them before sending them to a session
+type handler struct {
+	inner   slog.Handler
+	limiter *limiter
+}
+
+// Enabled reports whether the session's log level lets level through
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle sends the redacted record unless the session is over its rate. The
+// first message after a dropped run reports how many messages were dropped.
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	ok, dropped := h.limiter.take()
+	if !ok {
+		return nil
+	}
+	if dropped > 0 {
+		notice := slog.NewRecord(r.Time, mcp.LevelWarning, "log messages dropped by rate limit", 0)
+		notice.AddAttrs(slog.Int("dropped", dropped))
+		if err := h.inner.Handle(ctx, notice); err != nil {
+			return err
+		}
+	}
+
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(Redact(a))
+		return true
+	})
+	return h.inner.Handle(ctx, redacted)
+}
+
+// WithAttrs returns a handler adding the redacted attrs to every record
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = Redact(a)
+	}
+	return &handler{inner: h.inner.WithAttrs(redacted), limiter: h.limiter}
+}
+
+// WithGroup returns a handler nesting later attributes under name
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{inner: h.inner.WithGroup(name), limiter: h.limiter}
+}
+
+// limiter is a token bucket counting the messages it turns away
+type limiter struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+// newLimiter creates a full bucket of burst tokens refilled at rate per second
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// take reports whether a message may be sent and, if so, how many messages
+// were turned away since the last one sent
+func (l *limiter) take() (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		l.dropped++
+		return false, 0
+	}
+	l.tokens--
+	dropped := l.dropped
+	l.dropped = 0
+	return true, dropped
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the values of sensitive fields
+const redacted = "REDACTED"
+
+// sensitiveNames are the substrings marking an attribute or query parameter
+// name as sensitive
+var sensitiveNames = []string{
+	"password", "passwd", "secret", "token", "apikey", "api_key", "access_key",
+	"authorization", "cookie", "credential", "session", "signature",
+}
+
+// isSensitive reports whether name holds a secret
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return name == "key" || name == "sig" || name == "auth"
+}
+
+// Redact hides the value of a sensitive attribute and the credentials of the
+// URLs a string attribute holds, recursing into groups
+func Redact(a slog.Attr) slog.Attr {
+	if isSensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	switch v := a.Value.Resolve(); v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		clean := make([]any, len(attrs))
+		for i, attr := range attrs {
+			clean[i] = Redact(attr)
+		}
+		return slog.Group(a.Key, clean...)
+	case slog.KindString:
+		return slog.String(a.Key, RedactURL(v.String()))
+	case slog.KindAny:
+		if values, ok := v.Any().([]string); ok {
+			clean := make([]string, len(values))
+			for i, s := range values {
+				clean[i] = RedactURL(s)
+			}
+			return slog.Any(a.Key, clean)
+		}
+		return slog.Attr{Key: a.Key, Value: v}
+	default:
+		return slog.Attr{Key: a.Key, Value: v}
+	}
+}
+
+// RedactURL hides the password and the sensitive query parameters of an
+// absolute URL. Other strings are returned unchanged.
+func RedactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return s
+	}
+
+	changed := false
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+		changed = true
+	}
+	query := u.Query()
+	for name := range query {
+		if isSensitive(name) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return s
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,98 @@
+package prompts
+
+import "searxng-mcp/pkg/searxng"
+
+// timeRanges returns the time_range values accepted by the search tools
+func timeRanges() []string {
+	var names []string
+	for _, tr := range searxng.GetAllTimeRanges() {
+		names = append(names, string(tr))
+	}
+	return names
+}
+
+// Builtin returns the built-in research workflow prompts
+func Builtin() []*Template {
+	return []*Template{
+		{
+			Name:        "research_topic",
+			Title:       "Research a topic",
+			Description: "Research a topic in depth and write a cited summary",
+			Arguments: []Argument{
+				{Name: "topic", Description: "The topic or question to research.", Required: true},
+				{Name: "depth", Description: "How thorough the research should be.", Default: "standard", Enum: []string{"quick", "standard", "thorough"}},
+				{Name: "time_range", Description: "Only consider sources from this period.", Enum: timeRanges()},
+				{Name: "language", Description: "Language code for search results, such as en or de."},
+			},
+			Text: `Research the following topic and write a well-sourced summary: {{.topic}}
+
+Work through these steps with the SearXNG tools:
+1. Call the research tool with question "{{.topic}}"{{if eq .depth "quick"}}, max_queries 3 and max_sources 5{{else if eq .depth "thorough"}}, max_queries 12 and max_sources 20{{end}}{{if .time_range}}, time_range "{{.time_range}}"{{end}}{{if .language}}, language "{{.language}}"{{end}} and format "citation" to collect a numbered source ledger.
+2. For the sub-questions the ledger leaves open, call search_and_read with a focused query to read the most relevant passages of the top pages.
+{{- if ne .depth "quick"}}
+3. Call fetch_url on the two or three most important sources to check the details you rely on against the full page.
+{{- end}}
+
+Then write the summary. Cite every claim with the [n] numbers of the source ledger, say where sources disagree, and list what remains uncertain.`,
+		},
+		{
+			Name:        "fact_check",
+			Title:       "Fact-check a claim",
+			Description: "Check a claim against independent sources and give a verdict",
+			Arguments: []Argument{
+				{Name: "claim", Description: "The claim to check.", Required: true},
+				{Name: "context", Description: "Where the claim was made or what it refers to."},
+			},
+			Text: `Fact-check this claim: "{{.claim}}"
+{{- if .context}}
+Context: {{.context}}
+{{- end}}
+
+Work through these steps with the SearXNG tools:
+1. Call search_advanced with the key terms of the claim to find where it comes from.
+2. Call verify_claim with the claim to collect passages that likely support or contradict it. Its stances are lexical guesses: read every passage before relying on it.
+3. Call search_and_read on queries the evidence table leaves open to read the relevant passages, preferring primary sources: official statistics, papers (search_papers for scientific claims) and original reporting.
+4. Call fetch_url on any source whose passage is ambiguous.
+
+Then give a verdict of supported, refuted, partly true or unverifiable. Quote the passages the verdict rests on with their URLs, and note when sources are outdated, partisan or only repeat each other.`,
+		},
+		{
+			Name:        "recent_news",
+			Title:       "Find recent news",
+			Description: "Summarize the recent news about a subject",
+			Arguments: []Argument{
+				{Name: "topic", Description: "The subject to find news about.", Required: true},
+				{Name: "time_range", Description: "How far back to look.", Default: "month", Enum: timeRanges()},
+				{Name: "language", Description: "Language code for news results, such as en or de."},
+			},
+			Text: `Find the recent news about: {{.topic}}
+
+Work through these steps with the SearXNG tools:
+1. Call news_digest with query "{{.topic}}", time_range "{{.time_range}}"{{if .language}} and language "{{.language}}"{{end}} to group the coverage into stories.
+2. For the most significant stories, call fetch_url on the article of a reputable outlet to read the details.
+3. If a story is covered by only one outlet, call search_category with categories ["news"] to look for confirmation.
+
+Then summarize each story in a few sentences, newest first, with its date, the outlets covering it and links to the articles. Flag stories that are still developing or only reported by one outlet.`,
+		},
+		{
+			Name:        "compare_sources",
+			Title:       "Compare sources",
+			Description: "Compare how different sources cover a topic",
+			Arguments: []Argument{
+				{Name: "topic", Description: "The topic to compare coverage of.", Required: true},
+				{Name: "sources", Description: "Comma-separated domains to compare, such as bbc.co.uk, reuters.com. When empty, the most prominent sources found are compared."},
+			},
+			Text: `Compare how different sources cover: {{.topic}}
+
+Work through these steps with the SearXNG tools:
+{{- if .sources}}
+1. For each of these domains: {{.sources}}, call search with query "{{.topic}}" and include_domains set to that domain.
+{{- else}}
+1. Call search with query "{{.topic}}" and limit 20, and pick four or five sources of different kinds: news outlets, reference works, official bodies, blogs or forums.
+{{- end}}
+2. Call fetch_url on the most relevant page of each source to read its coverage.
+
+Then compare the sources side by side: the claims each makes, the evidence it cites, its framing and tone, and what it leaves out. End with where the sources agree, where they conflict, and which are the most reliable on this topic and why.`,
+		},
+	}
+}
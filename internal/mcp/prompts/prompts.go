@@ -0,0 +1,178 @@
+// Package prompts registers MCP prompt templates that steer a model through
+// the research workflows of the SearXNG tools
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.yaml.in/yaml/v3"
+)
+
+// Argument is a typed argument of a prompt
+type Argument struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	// Default is used when the argument is not given
+	Default string `yaml:"default"`
+	// Enum lists the accepted values, when not empty
+	Enum []string `yaml:"enum"`
+}
+
+// Template is a prompt whose text is a Go text/template over its arguments
+type Template struct {
+	Name        string     `yaml:"name"`
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Arguments   []Argument `yaml:"arguments"`
+	// Text is executed with the argument values, keyed by argument name
+	Text string `yaml:"template"`
+
+	tmpl *template.Template
+}
+
+// parse validates the template and compiles its text
+func (t *Template) parse() error {
+	if t.Name == "" {
+		return errors.New("prompt needs a name")
+	}
+	if strings.TrimSpace(t.Text) == "" {
+		return fmt.Errorf("prompt '%s' needs a template", t.Name)
+	}
+	for _, arg := range t.Arguments {
+		if arg.Name == "" {
+			return fmt.Errorf("prompt '%s' has an argument without a name", t.Name)
+		}
+	}
+	tmpl, err := template.New(t.Name).Option("missingkey=zero").Parse(t.Text)
+	if err != nil {
+		return fmt.Errorf("prompt '%s': %w", t.Name, err)
+	}
+	t.tmpl = tmpl
+	return nil
+}
+
+// Render fills the template with args, applying defaults and checking
+// required and enumerated arguments
+func (t *Template) Render(args map[string]string) (string, error) {
+	values := make(map[string]string, len(t.Arguments))
+	for _, arg := range t.Arguments {
+		value := strings.TrimSpace(args[arg.Name])
+		if value == "" {
+			value = arg.Default
+		}
+		if value == "" && arg.Required {
+			return "", fmt.Errorf("missing required argument '%s'", arg.Name)
+		}
+		if value != "" && len(arg.Enum) > 0 && !slices.Contains(arg.Enum, value) {
+			return "", fmt.Errorf("invalid %s '%s'. Valid options are: %s", arg.Name, value, strings.Join(arg.Enum, ", "))
+		}
+		values[arg.Name] = value
+	}
+
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, values); err != nil {
+		return "", fmt.Errorf("failed to render prompt '%s': %w", t.Name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Prompt returns the MCP description of the template
+func (t *Template) Prompt() *mcp.Prompt {
+	prompt := &mcp.Prompt{Name: t.Name, Title: t.Title, Description: t.Description}
+	for _, arg := range t.Arguments {
+		description := arg.Description
+		if len(arg.Enum) > 0 {
+			description += " One of: " + strings.Join(arg.Enum, ", ") + "."
+		}
+		if arg.Default != "" {
+			description += " Default: " + arg.Default + "."
+		}
+		prompt.Arguments = append(prompt.Arguments, &mcp.PromptArgument{
+			Name:        arg.Name,
+			Description: strings.TrimSpace(description),
+			Required:    arg.Required,
+		})
+	}
+	return prompt
+}
+
+// Register adds the templates to server as prompts. A template replaces an
+// earlier one with the same name.
+func Register(server *mcp.Server, templates []*Template) error {
+	for _, t := range templates {
+		if t.tmpl == nil {
+			if err := t.parse(); err != nil {
+				return err
+			}
+		}
+		server.AddPrompt(t.Prompt(), handler(t))
+	}
+	return nil
+}
+
+// handler renders a template as a single user message
+func handler(t *Template) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text, err := t.Render(req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: t.Description,
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: text}},
+			},
+		}, nil
+	}
+}
+
+// LoadDir reads the custom prompts of dir, one YAML template per *.yaml or
+// *.yml file, in file name order. A missing directory holds no prompts.
+func LoadDir(dir string) ([]*Template, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompts directory: %w", err)
+	}
+
+	var templates []*Template
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt: %w", err)
+		}
+
+		var t Template
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if t.Name == "" {
+			t.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if err := t.parse(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		templates = append(templates, &t)
+	}
+	return templates, nil
+}
@@ -0,0 +1,148 @@
+package prompts_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/prompts"
+)
+
+var _ = Describe("Prompts", func() {
+	var (
+		ctx     context.Context
+		session *mcp.ClientSession
+	)
+
+	// connect registers templates on a new MCP server and connects a client to it
+	connect := func(templates []*prompts.Template) {
+		server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+		Expect(prompts.Register(server, templates)).To(Succeed())
+
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err := server.Connect(ctx, serverTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+		session, err = mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// getText renders a prompt and returns the text of its only message
+	getText := func(name string, args map[string]string) string {
+		result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: name, Arguments: args})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Messages).To(HaveLen(1))
+		Expect(result.Messages[0].Role).To(BeEquivalentTo("user"))
+		return result.Messages[0].Content.(*mcp.TextContent).Text
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		if session != nil {
+			session.Close()
+		}
+	})
+
+	It("should list the built-in prompts with typed arguments", func() {
+		connect(prompts.Builtin())
+		result, err := session.ListPrompts(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		byName := map[string]*mcp.Prompt{}
+		for _, p := range result.Prompts {
+			byName[p.Name] = p
+		}
+		Expect(byName).To(HaveKey("research_topic"))
+		Expect(byName).To(HaveKey("fact_check"))
+		Expect(byName).To(HaveKey("recent_news"))
+		Expect(byName).To(HaveKey("compare_sources"))
+
+		topic := byName["research_topic"].Arguments[0]
+		Expect(topic.Name).To(Equal("topic"))
+		Expect(topic.Required).To(BeTrue())
+		Expect(byName["research_topic"].Arguments[1].Description).To(ContainSubstring("One of: quick, standard, thorough"))
+	})
+
+	It("should steer the model through the server's tools", func() {
+		connect(prompts.Builtin())
+
+		text := getText("research_topic", map[string]string{"topic": "raft consensus", "depth": "quick", "time_range": "year"})
+		Expect(text).To(ContainSubstring(`question "raft consensus", max_queries 3 and max_sources 5, time_range "year"`))
+		Expect(text).To(ContainSubstring("search_and_read"))
+		Expect(text).NotTo(ContainSubstring("fetch_url"))
+
+		text = getText("recent_news", map[string]string{"topic": "fusion power"})
+		Expect(text).To(ContainSubstring(`news_digest with query "fusion power", time_range "month"`))
+
+		text = getText("compare_sources", map[string]string{"topic": "rent control", "sources": "bbc.co.uk, reuters.com"})
+		Expect(text).To(ContainSubstring("include_domains"))
+		Expect(text).To(ContainSubstring("bbc.co.uk, reuters.com"))
+
+		text = getText("fact_check", map[string]string{"claim": "Bats are blind"})
+		Expect(text).To(HavePrefix(`Fact-check this claim: "Bats are blind"`))
+	})
+
+	It("should reject missing and invalid arguments", func() {
+		connect(prompts.Builtin())
+
+		_, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "fact_check"})
+		Expect(err).To(MatchError(ContainSubstring("missing required argument 'claim'")))
+
+		_, err = session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "recent_news", Arguments: map[string]string{"topic": "x", "time_range": "decade"}})
+		Expect(err).To(MatchError(ContainSubstring("invalid time_range 'decade'")))
+	})
+
+	Describe("LoadDir", func() {
+		var dir string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+		})
+
+		It("should add custom prompts and let them replace built-in ones", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "cve.yaml"), []byte(`title: Look up a CVE
+description: Summarize a vulnerability
+arguments:
+  - name: id
+    description: The CVE identifier.
+    required: true
+template: |
+  Call search_code with query "{{.id}}" and summarize the advisories.
+`), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "news.yml"), []byte(`name: recent_news
+arguments:
+  - name: topic
+    required: true
+template: Call news_digest for "{{.topic}}" in our house style.
+`), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a prompt"), 0644)).To(Succeed())
+
+			custom, err := prompts.LoadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(custom).To(HaveLen(2))
+			connect(append(prompts.Builtin(), custom...))
+
+			Expect(getText("cve", map[string]string{"id": "CVE-2024-3094"})).To(Equal(`Call search_code with query "CVE-2024-3094" and summarize the advisories.`))
+			Expect(getText("recent_news", map[string]string{"topic": "fusion"})).To(ContainSubstring("house style"))
+		})
+
+		It("should treat a missing directory as no prompts", func() {
+			custom, err := prompts.LoadDir(filepath.Join(dir, "missing"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(custom).To(BeEmpty())
+		})
+
+		It("should report an invalid template with its file", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("template: \"{{.topic\"\n"), 0644)).To(Succeed())
+
+			_, err := prompts.LoadDir(dir)
+			Expect(err).To(MatchError(ContainSubstring("broken.yaml")))
+		})
+	})
+})
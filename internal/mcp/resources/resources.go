@@ -0,0 +1,400 @@
+// Package resources exposes SearXNG searches and their results as MCP resources
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+)
+
+const (
+	// SearchTemplate is the URI template of search resources. The path slash is
+	// required: the SDK rejects a template whose host holds the "{" of the query expansion.
+	SearchTemplate = "searxng://search/{?q,category,lang,time_range,page,disabled_engines}"
+	// ResultTemplate is the URI template of result resources
+	ResultTemplate = "searxng://result/{id}"
+	// DefaultCapacity is the number of recent results kept as resources
+	DefaultCapacity = 100
+
+	// resultPrefix is the URI of a result resource without its id
+	resultPrefix = "searxng://result/"
+	// jsonMIMEType is the MIME type of every resource
+	jsonMIMEType = "application/json"
+	// listChanged is the notification sent when the resource list changes
+	listChanged = "notifications/resources/list_changed"
+)
+
+// Result is the content of a result resource
+type Result struct {
+	// ID identifies the result; it is derived from the normalized result URL
+	ID string `json:"id"`
+	// URI is the resource URI of the result
+	URI string `json:"uri"`
+	// Search is the resource URI of the search that returned the result
+	Search string `json:"search"`
+	// Query is the query of that search
+	Query string `json:"query"`
+	// Page is the search results page that returned the result
+	Page int `json:"page"`
+	// Rank is the position of the result on that page, from 1
+	Rank int `json:"rank"`
+	// RetrievedAt is when the search returned the result
+	RetrievedAt time.Time `json:"retrieved_at"`
+	// Result holds the full SearXNG result metadata
+	Result searxng.SearchResult `json:"result"`
+}
+
+// Search is the content of a search resource
+type Search struct {
+	URI         string         `json:"uri"`
+	Query       string         `json:"query"`
+	Category    string         `json:"category,omitempty"`
+	Language    string         `json:"language,omitempty"`
+	TimeRange   string         `json:"time_range,omitempty"`
+	Page        int            `json:"page"`
+	Total       int            `json:"total,omitempty"`
+	Results     []SearchResult `json:"results"`
+	Suggestions []string       `json:"suggestions,omitempty"`
+}
+
+// SearchResult is a result listed by a search resource, linking to its result resource
+type SearchResult struct {
+	Rank    int    `json:"rank"`
+	URI     string `json:"uri"`
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// Results keeps the most recently returned search results as resources
+type Results struct {
+	server   *mcp.Server
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   []string
+	results map[string]Result
+
+	// registerMu serializes the registration of stored results on the server,
+	// in the order the store changed
+	registerMu sync.Mutex
+	// quiet holds back resource list notifications while a search registers its results
+	quiet atomic.Bool
+}
+
+// NewResults registers the result resource template on server and returns an
+// empty store keeping up to capacity results, or DefaultCapacity when capacity is not positive
+func NewResults(server *mcp.Server, capacity int) *Results {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	r := &Results{
+		server:   server,
+		capacity: capacity,
+		now:      time.Now,
+		results:  make(map[string]Result),
+	}
+	server.AddSendingMiddleware(r.coalesce)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "result",
+		Title:       "Search result",
+		Description: "A result returned by a recent search, with its full SearXNG metadata and a link to the search resource",
+		MIMEType:    jsonMIMEType,
+		URITemplate: ResultTemplate,
+	}, r.read)
+	return r
+}
+
+// Record stores the results of a search, listing each as a resource. Results
+// already stored move to the front; the oldest ones beyond capacity are removed.
+// Sessions are notified of the new resource list once per search.
+func (r *Results) Record(req searxng.SearchRequest, results []searxng.SearchResult) {
+	if len(results) == 0 {
+		return
+	}
+	search := SearchURI(req)
+	page := max(req.PageNo, 1)
+	now := r.now()
+
+	r.mu.Lock()
+	var added []Result
+	for i, result := range results {
+		if result.URL == "" {
+			continue
+		}
+		id := ResultID(result.URL)
+		entry := Result{
+			ID:          id,
+			URI:         resultPrefix + id,
+			Search:      search,
+			Query:       req.Query,
+			Page:        page,
+			Rank:        i + 1,
+			RetrievedAt: now,
+			Result:      result,
+		}
+		r.remember(entry)
+		added = append(added, entry)
+	}
+
+	var evicted []string
+	if n := len(r.order) - r.capacity; n > 0 {
+		for _, id := range r.order[:n] {
+			delete(r.results, id)
+			evicted = append(evicted, resultPrefix+id)
+		}
+		r.order = r.order[n:]
+	}
+
+	// Register outside the store lock, which resource reads take, but before
+	// a later search can change the store again
+	r.registerMu.Lock()
+	r.mu.Unlock()
+	defer r.registerMu.Unlock()
+
+	var changes []func()
+	for _, entry := range added {
+		changes = append(changes, func() {
+			r.server.AddResource(&mcp.Resource{
+				Name:        entry.ID,
+				Title:       entry.Result.Title,
+				Description: entry.Result.URL,
+				MIMEType:    jsonMIMEType,
+				URI:         entry.URI,
+			}, r.read)
+		})
+	}
+	if len(evicted) > 0 {
+		changes = append(changes, func() { r.server.RemoveResources(evicted...) })
+	}
+	for i, change := range changes {
+		// Only the last change notifies the sessions
+		r.quiet.Store(i < len(changes)-1)
+		change()
+	}
+}
+
+// coalesce is a sending middleware holding back the resource list
+// notifications of a search but the last
+func (r *Results) coalesce(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == listChanged && r.quiet.Load() {
+			return nil, nil
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// Get returns the stored result with id
+func (r *Results) Get(id string) (Result, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result, ok := r.results[id]
+	return result, ok
+}
+
+// remember stores entry as the most recent result
+func (r *Results) remember(entry Result) {
+	if _, ok := r.results[entry.ID]; ok {
+		for i, id := range r.order {
+			if id == entry.ID {
+				r.order = append(r.order[:i], r.order[i+1:]...)
+				break
+			}
+		}
+	}
+	r.order = append(r.order, entry.ID)
+	r.results[entry.ID] = entry
+}
+
+// read returns a stored result resource
+func (r *Results) read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	result, ok := r.Get(strings.TrimPrefix(uri, resultPrefix))
+	if !ok || !strings.HasPrefix(uri, resultPrefix) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return jsonResource(uri, result)
+}
+
+// Client returns a client recording the results of every search it performs
+func (r *Results) Client(client searxng.Client) searxng.Client {
+	return &recordingClient{client: client, results: r}
+}
+
+// recordingClient records the results of the searches of a wrapped client
+type recordingClient struct {
+	client  searxng.Client
+	results *Results
+}
+
+// Search performs a search and records its results
+func (c *recordingClient) Search(ctx context.Context, req searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	resp, err := c.client.Search(ctx, req)
+	if err == nil {
+		c.results.Record(req, resp.Results)
+	}
+	return resp, err
+}
+
+// NewSearchTemplate registers the search resource template, whose resources
+// run a search when read. client should record its results in a Results store
+// so that the result resources listed by the search can be read.
+func NewSearchTemplate(server *mcp.Server, client searxng.Client) {
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "search",
+		Title:       "SearXNG search",
+		Description: "A page of results of a SearXNG search. q is the query, category an optional comma-separated list of categories, lang an optional language code, time_range an optional day, month or year, page the result page (default 1) and disabled_engines an optional comma-separated list of engines to leave out. Each result links to its searxng://result resource.",
+		MIMEType:    jsonMIMEType,
+		URITemplate: SearchTemplate,
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		search, err := parseSearchURI(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Search(ctx, search)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+
+		out := Search{
+			URI:         uri,
+			Query:       search.Query,
+			Category:    categoryValue(search.Category),
+			Language:    search.Language,
+			TimeRange:   string(search.TimeRange),
+			Page:        max(search.PageNo, 1),
+			Total:       resp.NumberOfResults,
+			Results:     []SearchResult{},
+			Suggestions: resp.Suggestions,
+		}
+		for i, r := range resp.Results {
+			out.Results = append(out.Results, SearchResult{
+				Rank:    i + 1,
+				URI:     ResultURI(r.URL),
+				Title:   r.Title,
+				URL:     r.URL,
+				Summary: r.Content,
+			})
+		}
+		return jsonResource(uri, out)
+	})
+}
+
+// SearchURI returns the URI of the search resource repeating req
+func SearchURI(req searxng.SearchRequest) string {
+	params := []string{"q=" + escape(req.Query)}
+	if category := categoryValue(req.Category); category != "" {
+		params = append(params, "category="+escape(category))
+	}
+	if req.Language != "" {
+		params = append(params, "lang="+escape(req.Language))
+	}
+	if req.TimeRange != "" {
+		params = append(params, "time_range="+escape(string(req.TimeRange)))
+	}
+	if req.PageNo > 1 {
+		params = append(params, "page="+strconv.Itoa(req.PageNo))
+	}
+	if len(req.DisabledEngines) > 0 {
+		params = append(params, "disabled_engines="+escape(strings.Join(req.DisabledEngines, ",")))
+	}
+	return "searxng://search/?" + strings.Join(params, "&")
+}
+
+// ResultID returns the id of the result resource of a result URL
+func ResultID(rawURL string) string {
+	h := fnv.New64a()
+	h.Write([]byte(searxng.NormalizeURL(rawURL)))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// ResultURI returns the URI of the result resource of a result URL
+func ResultURI(rawURL string) string {
+	return resultPrefix + ResultID(rawURL)
+}
+
+// parseSearchURI converts a search resource URI into a search request
+func parseSearchURI(uri string) (searxng.SearchRequest, error) {
+	var req searxng.SearchRequest
+	_, rawQuery, _ := strings.Cut(uri, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return req, fmt.Errorf("invalid search resource URI %q: %w", uri, err)
+	}
+
+	req.Query = strings.TrimSpace(params.Get("q"))
+	if req.Query == "" {
+		return req, fmt.Errorf("search resource URI %q needs a non-empty q parameter", uri)
+	}
+	req.Language = params.Get("lang")
+	if value := params.Get("time_range"); value != "" {
+		timeRange, err := searxng.ValidateTimeRange(value)
+		if err != nil {
+			return req, err
+		}
+		req.TimeRange = timeRange
+	}
+	if value := params.Get("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return req, fmt.Errorf("search resource URI %q has an invalid page %q", uri, value)
+		}
+		req.PageNo = page
+	}
+	for _, name := range strings.Split(params.Get("disabled_engines"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			req.DisabledEngines = append(req.DisabledEngines, name)
+		}
+	}
+	for _, name := range strings.Split(params.Get("category"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		category, err := searxng.ValidateCategory(name)
+		if err != nil {
+			return req, err
+		}
+		req.Category = append(req.Category, category)
+	}
+	return req, nil
+}
+
+// categoryValue joins categories into the category parameter of a search resource URI
+func categoryValue(categories []searxng.Category) string {
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ",")
+}
+
+// escape escapes a URI template query value, encoding spaces as %20 since the
+// template does not match "+"
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// jsonResource returns v as the JSON content of the resource at uri
+func jsonResource(uri string, v any) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: jsonMIMEType, Text: string(data)}},
+	}, nil
+}
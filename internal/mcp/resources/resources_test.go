@@ -0,0 +1,183 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/resources"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Resources", func() {
+	var (
+		server   *httptest.Server
+		session  *mcp.ClientSession
+		ctx      context.Context
+		client   searxng.Client
+		requests []url.Values
+		changes  atomic.Int32
+	)
+
+	// connect registers the resources on a new MCP server and connects a client to it
+	connect := func(capacity int) {
+		mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+		results := resources.NewResults(mcpServer, capacity)
+		client = results.Client(searxng.NewClient(server.URL))
+		resources.NewSearchTemplate(mcpServer, client)
+
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err := mcpServer.Connect(ctx, serverTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+		session, err = mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+			ResourceListChangedHandler: func(context.Context, *mcp.ResourceListChangedRequest) { changes.Add(1) },
+		}).Connect(ctx, clientTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// readJSON reads a resource and decodes its JSON content
+	readJSON := func(uri string, v any) {
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Contents).To(HaveLen(1))
+		Expect(result.Contents[0].MIMEType).To(Equal("application/json"))
+		Expect(json.Unmarshal([]byte(result.Contents[0].Text), v)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests = nil
+		changes.Store(0)
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			requests = append(requests, r.Form)
+			var results []map[string]any
+			for n := 1; n <= 3; n++ {
+				results = append(results, map[string]any{
+					"url":     fmt.Sprintf("https://example.com/%s/%d", r.FormValue("q"), n),
+					"title":   fmt.Sprintf("Result %d", n),
+					"content": "Result content",
+					"engine":  "duckduckgo",
+					"engines": []string{"duckduckgo", "brave"},
+					"score":   float64(4 - n),
+				})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(map[string]any{"query": r.FormValue("q"), "results": results})).To(Succeed())
+		}))
+	})
+
+	AfterEach(func() {
+		if session != nil {
+			session.Close()
+		}
+		server.Close()
+	})
+
+	It("should list the search and result templates", func() {
+		connect(0)
+		result, err := session.ListResourceTemplates(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		var templates []string
+		for _, t := range result.ResourceTemplates {
+			templates = append(templates, t.URITemplate)
+		}
+		Expect(templates).To(ConsistOf(resources.SearchTemplate, resources.ResultTemplate))
+	})
+
+	It("should run the search a search resource names", func() {
+		connect(0)
+		var search resources.Search
+		readJSON("searxng://search/?q=raft%20consensus&category=it&lang=fr", &search)
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Get("q")).To(Equal("raft consensus"))
+		Expect(requests[0].Get("categories")).To(Equal("it"))
+		Expect(requests[0].Get("language")).To(Equal("fr"))
+
+		Expect(search.Query).To(Equal("raft consensus"))
+		Expect(search.Results).To(HaveLen(3))
+		Expect(search.Results[0].URI).To(Equal(resources.ResultURI("https://example.com/raft consensus/1")))
+	})
+
+	It("should accept search parameters in any order", func() {
+		connect(0)
+		var search resources.Search
+		readJSON("searxng://search/?lang=de&q=raft", &search)
+		Expect(search.Language).To(Equal("de"))
+		Expect(search.Query).To(Equal("raft"))
+	})
+
+	It("should reject a search resource without a query", func() {
+		connect(0)
+		_, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "searxng://search/?lang=de"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should list recent results with their metadata and search", func() {
+		connect(0)
+		_, err := client.Search(ctx, searxng.SearchRequest{Query: "raft", Category: []searxng.Category{searxng.CategoryNews}, TimeRange: searxng.TimeRangeMonth, PageNo: 2})
+		Expect(err).NotTo(HaveOccurred())
+
+		list, err := session.ListResources(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Resources).To(HaveLen(3))
+
+		uri := resources.ResultURI("https://example.com/raft/2")
+		var result resources.Result
+		readJSON(uri, &result)
+		Expect(result.URI).To(Equal(uri))
+		Expect(result.Page).To(Equal(2))
+		Expect(result.Rank).To(Equal(2))
+		Expect(result.Result.Title).To(Equal("Result 2"))
+		Expect(result.Result.Engines).To(Equal([]string{"duckduckgo", "brave"}))
+		Expect(result.Search).To(Equal("searxng://search/?q=raft&category=news&time_range=month&page=2"))
+
+		// The linked search resource repeats the search
+		var search resources.Search
+		readJSON(result.Search, &search)
+		Expect(search.Category).To(Equal("news"))
+		Expect(search.TimeRange).To(Equal("month"))
+		Expect(search.Page).To(Equal(2))
+		Expect(requests[len(requests)-1].Get("pageno")).To(Equal("2"))
+		Expect(requests[len(requests)-1].Get("time_range")).To(Equal("month"))
+	})
+
+	It("should drop the oldest results beyond its capacity", func() {
+		connect(4)
+		_, err := client.Search(ctx, searxng.SearchRequest{Query: "first"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Search(ctx, searxng.SearchRequest{Query: "second"})
+		Expect(err).NotTo(HaveOccurred())
+
+		list, err := session.ListResources(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		var uris []string
+		for _, r := range list.Resources {
+			uris = append(uris, r.URI)
+		}
+		Expect(uris).To(ConsistOf(
+			resources.ResultURI("https://example.com/first/3"),
+			resources.ResultURI("https://example.com/second/1"),
+			resources.ResultURI("https://example.com/second/2"),
+			resources.ResultURI("https://example.com/second/3"),
+		))
+
+		_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: resources.ResultURI("https://example.com/first/1")})
+		Expect(err).To(HaveOccurred())
+
+		// Each search changes the resource list once
+		Eventually(changes.Load).Should(BeNumerically("==", 2))
+		Consistently(changes.Load, "100ms").Should(BeNumerically("==", 2))
+	})
+})
@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/watch"
+)
+
+// WatchTemplate is the URI template of watched query resources
+const WatchTemplate = "searxng://watch/{id}"
+
+// Watch is the content of a watched query resource
+type Watch struct {
+	URI             string       `json:"uri"`
+	ID              string       `json:"id"`
+	Query           string       `json:"query"`
+	Categories      []string     `json:"categories,omitempty"`
+	Language        string       `json:"language,omitempty"`
+	TimeRange       string       `json:"time_range,omitempty"`
+	IntervalSeconds int          `json:"interval_seconds"`
+	CreatedAt       time.Time    `json:"created_at"`
+	LastRun         time.Time    `json:"last_run"`
+	LastError       string       `json:"last_error,omitempty"`
+	NewResults      []watch.Item `json:"new_results"`
+}
+
+// Watches publishes the watched queries of a watcher as resources that
+// clients can subscribe to
+type Watches struct {
+	watcher *watch.Watcher
+	server  *mcp.Server
+}
+
+// NewWatches creates the watch resources of watcher. Its Subscribe and
+// Unsubscribe methods are the subscription handlers of the server, which
+// Register then adds the resources to.
+func NewWatches(watcher *watch.Watcher) *Watches {
+	return &Watches{watcher: watcher}
+}
+
+// Register registers the watch template and a resource per watch on server,
+// and keeps them in step with the watcher: resources are added and removed
+// with their watches, and subscribers are notified when a refresh finds new results
+func (w *Watches) Register(server *mcp.Server) {
+	w.server = server
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "watch",
+		Title:       "Watched query",
+		Description: "A query watched with watch_query, with the results its background refreshes found that earlier runs had not returned, newest first. Subscribe to be notified of new results.",
+		MIMEType:    jsonMIMEType,
+		URITemplate: WatchTemplate,
+	}, w.read)
+	for _, existing := range w.watcher.List() {
+		w.add(existing)
+	}
+
+	w.watcher.OnAdd = w.add
+	w.watcher.OnRemove = func(removed watch.Watch) {
+		server.RemoveResources(removed.URI())
+	}
+	w.watcher.OnUpdate = func(ctx context.Context, updated watch.Watch, added []watch.Item) {
+		server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: updated.URI()})
+	}
+}
+
+// Subscribe accepts subscriptions to existing watches only
+func (w *Watches) Subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	id, ok := watch.ParseURI(req.Params.URI)
+	if !ok {
+		return fmt.Errorf("resource %s does not support subscriptions", req.Params.URI)
+	}
+	if _, ok := w.watcher.Get(id); !ok {
+		return mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	return nil
+}
+
+// Unsubscribe accepts every unsubscription
+func (w *Watches) Unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	return nil
+}
+
+// add lists a watch as a resource
+func (w *Watches) add(added watch.Watch) {
+	w.server.AddResource(&mcp.Resource{
+		Name:        "watch-" + added.ID,
+		Title:       "Watch: " + added.Query,
+		Description: fmt.Sprintf("New results of the query '%s', refreshed every %s", added.Query, added.Interval()),
+		MIMEType:    jsonMIMEType,
+		URI:         added.URI(),
+	}, w.read)
+}
+
+// read returns a watch resource
+func (w *Watches) read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	id, ok := watch.ParseURI(uri)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	found, ok := w.watcher.Get(id)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return jsonResource(uri, Watch{
+		URI:             uri,
+		ID:              found.ID,
+		Query:           found.Query,
+		Categories:      found.Categories,
+		Language:        found.Language,
+		TimeRange:       found.TimeRange,
+		IntervalSeconds: found.IntervalSeconds,
+		CreatedAt:       found.CreatedAt,
+		LastRun:         found.LastRun,
+		LastError:       found.LastError,
+		NewResults:      found.New,
+	})
+}
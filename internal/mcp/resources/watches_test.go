@@ -0,0 +1,126 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/resources"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/watch"
+)
+
+var _ = Describe("Watches", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		watcher *watch.Watcher
+		ctx     context.Context
+
+		mu      sync.Mutex
+		count   int
+		updated []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		count = 2
+		updated = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			var results []map[string]any
+			for n := 1; n <= count; n++ {
+				results = append(results, map[string]any{"url": fmt.Sprintf("https://example.com/%d", n), "title": fmt.Sprintf("Result %d", n)})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"query": r.FormValue("q"), "results": results})
+		}))
+
+		var err error
+		watcher, err = watch.NewWatcher(searxng.NewClient(server.URL), watch.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		watches := resources.NewWatches(watcher)
+		mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+			SubscribeHandler:   watches.Subscribe,
+			UnsubscribeHandler: watches.Unsubscribe,
+		})
+		watches.Register(mcpServer)
+
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err = mcpServer.Connect(ctx, serverTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+		session, err = mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+			ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+				mu.Lock()
+				defer mu.Unlock()
+				updated = append(updated, req.Params.URI)
+			},
+		}).Connect(ctx, clientTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	received := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), updated...)
+	}
+
+	It("should notify subscribers of new results and serve them", func() {
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "release notes"})
+		Expect(err).NotTo(HaveOccurred())
+
+		listed, err := session.ListResources(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed.Resources).To(ContainElement(HaveField("URI", w.URI())))
+		Expect(session.Subscribe(ctx, &mcp.SubscribeParams{URI: w.URI()})).To(Succeed())
+
+		mu.Lock()
+		count = 3
+		mu.Unlock()
+		_, err = watcher.Refresh(ctx, w.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(received).Should(Equal([]string{w.URI()}))
+
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: w.URI()})
+		Expect(err).NotTo(HaveOccurred())
+		var content resources.Watch
+		Expect(json.Unmarshal([]byte(result.Contents[0].Text), &content)).To(Succeed())
+		Expect(content.Query).To(Equal("release notes"))
+		Expect(content.NewResults).To(HaveLen(1))
+		Expect(content.NewResults[0].URL).To(Equal("https://example.com/3"))
+	})
+
+	It("should refuse subscriptions to unknown watches and other resources", func() {
+		Expect(session.Subscribe(ctx, &mcp.SubscribeParams{URI: watch.URI("missing")})).NotTo(Succeed())
+		Expect(session.Subscribe(ctx, &mcp.SubscribeParams{URI: "searxng://result/abc"})).NotTo(Succeed())
+	})
+
+	It("should remove the resource with its watch", func() {
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "release notes"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, err = watcher.Remove(w.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		listed, err := session.ListResources(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed.Resources).NotTo(ContainElement(HaveField("URI", w.URI())))
+		_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: w.URI()})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -4,8 +4,19 @@ import (
 	"context"
 	"fmt"
 
+	"searxng-mcp/internal/mcp/completion"
+	"searxng-mcp/internal/mcp/logging"
+	"searxng-mcp/internal/mcp/prompts"
+	"searxng-mcp/internal/mcp/resources"
 	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/config"
+	"searxng-mcp/pkg/domains"
+	"searxng-mcp/pkg/history"
+	"searxng-mcp/pkg/scholar"
 	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/thumbnail"
+	"searxng-mcp/pkg/watch"
+	"searxng-mcp/pkg/webpage"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -14,28 +25,156 @@ import (
 type SearXNGServer struct {
 	mcpServer     *mcp.Server
 	searxngClient searxng.Client
+	tracker       *searxng.ReliabilityTracker
+	fetcher       *webpage.Fetcher
+	images        *thumbnail.Fetcher
+	resolvers     scholar.Resolvers
+	render        tools.Renderers
+	cursors       *tools.Cursors
+	watcher       *watch.Watcher
+	compareClient searxng.Client
+	instances     map[string]searxng.Client
+	history       *history.Store
 }
 
-// NewSearXNGServer creates a new MCP server with SearXNG tools
-func NewSearXNGServer(searxngURL string) (*SearXNGServer, error) {
+// Options configures optional SearXNG server behavior
+type Options struct {
+	// Reliability configures engine reliability tracking and flaky engine exclusion
+	Reliability searxng.ReliabilityOptions
+	// Settings holds the SearXNG settings used by the tools; nil loads them from the config directory
+	Settings *config.Settings
+	// Format is the default rendering of tool text content; empty means Markdown
+	Format tools.Format
+	// Domains is the server-wide domain policy applied to every search, which callers cannot override
+	Domains domains.Policy
+	// PromptsDir holds custom prompt templates added to the built-in prompts; empty adds none
+	PromptsDir string
+	// Logging limits the log notifications sent to each client session
+	Logging logging.Options
+	// WatchesPath is the file used to persist watched queries; empty disables persistence
+	WatchesPath string
+	// Instances maps names to the base URLs of further SearXNG instances that
+	// compare_searches can query besides the main one
+	Instances map[string]string
+	// History configures the local search history; nil disables it
+	History *history.Options
+	// AllowPrivateFetch lets the page and image fetchers connect to loopback,
+	// private and link-local addresses
+	AllowPrivateFetch bool
+}
+
+// NewSearXNGServer creates a new MCP server with SearXNG tools.
+// If opts is nil, default options are used.
+func NewSearXNGServer(searxngURL string, opts *Options) (*SearXNGServer, error) {
 	// Default to localhost if empty URL provided
 	if searxngURL == "" {
 		searxngURL = "http://localhost:8888"
 	}
-	
-	// Create SearXNG client
-	searxngClient := searxng.NewClient(searxngURL)
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	// Track engine reliability so flaky engines are excluded automatically
+	tracker, err := searxng.NewReliabilityTracker(opts.Reliability)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create engine reliability tracker: %w", err)
+	}
+
+	// Read DOI resolvers and other tool settings from the SearXNG settings
+	settings := opts.Settings
+	if settings == nil {
+		settings, err = config.LoadSettings()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SearXNG settings: %w", err)
+		}
+	}
+
+	// Create SearXNG client, logging upstream searches and dropping results from
+	// domains the server does not allow
+	httpClient := searxng.NewClient(searxngURL)
+	var searxngClient searxng.Client = searxng.NewTrackingClient(logging.NewClient(httpClient), tracker)
+	if !opts.Domains.IsZero() {
+		searxngClient = domains.NewClient(searxngClient, opts.Domains, false)
+	}
 
-	// Create MCP server with implementation details
+	// Re-run watched queries in the background, publishing them as subscribable resources
+	watcher, err := watch.NewWatcher(searxngClient, watch.Options{Path: opts.WatchesPath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watched queries: %w", err)
+	}
+	watches := resources.NewWatches(watcher)
+
+	// Create MCP server with implementation details, completing arguments from the instance catalog
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "searxng-mcp-server",
 		Version: "1.0.0",
-	}, nil)
+	}, &mcp.ServerOptions{
+		CompletionHandler:  completion.New(httpClient).Complete,
+		SubscribeHandler:   watches.Subscribe,
+		UnsubscribeHandler: watches.Unsubscribe,
+	})
+
+	// Send log notifications to each session at the level its client sets
+	mcpServer.AddReceivingMiddleware(logging.NewSessions(opts.Logging).Middleware)
+
+	// Keep recently returned results as resources
+	results := resources.NewResults(mcpServer, resources.DefaultCapacity)
+	searxngClient = results.Client(searxngClient)
+
+	// Record the calls of the search tools in the local history when enabled
+	cursors := tools.NewCursors()
+	var store *history.Store
+	if opts.History != nil {
+		store, err = history.Open(*opts.History)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load search history: %w", err)
+		}
+		cursors.SetHistory(store)
+	}
+
+	// Compared instances, the main one included, get the same logging and domain
+	// policy only. Their searches neither exclude flaky engines nor keep their
+	// results as resources, so variants of one query stay comparable.
+	instanceClient := func(client searxng.Client) searxng.Client {
+		client = logging.NewClient(client)
+		if !opts.Domains.IsZero() {
+			client = domains.NewClient(client, opts.Domains, false)
+		}
+		return client
+	}
+	instances := make(map[string]searxng.Client, len(opts.Instances))
+	for name, instanceURL := range opts.Instances {
+		if name == "" || name == tools.DefaultInstance {
+			return nil, fmt.Errorf("invalid instance name '%s'", name)
+		}
+		instances[name] = instanceClient(searxng.NewClient(instanceURL))
+	}
+
+	// Page and image fetches stay off internal networks unless allowed, and page
+	// fetches off the domains the server does not allow
+	fetcher := webpage.NewFetcher()
+	fetcher.Policy = opts.Domains
+	fetcher.AllowPrivateNetworks = opts.AllowPrivateFetch
+	images := thumbnail.NewFetcher()
+	images.AllowPrivateNetworks = opts.AllowPrivateFetch
 
 	// Create our server wrapper
 	server := &SearXNGServer{
 		mcpServer:     mcpServer,
 		searxngClient: searxngClient,
+		tracker:       tracker,
+		fetcher:       fetcher,
+		images:        images,
+		resolvers: scholar.Resolvers{
+			URLs:    settings.DOIResolvers,
+			Default: settings.DefaultDOIResolver,
+		},
+		render:        tools.Renderers{Default: opts.Format},
+		cursors:       cursors,
+		watcher:       watcher,
+		compareClient: instanceClient(httpClient),
+		instances:     instances,
+		history:       store,
 	}
 
 	// Register SearXNG tools
@@ -43,24 +182,107 @@ func NewSearXNGServer(searxngURL string) (*SearXNGServer, error) {
 		return nil, fmt.Errorf("failed to register tools: %w", err)
 	}
 
+	// Register SearXNG resources
+	server.registerResources(watches)
+
+	// Register built-in and custom prompts
+	if err := server.registerPrompts(opts.PromptsDir); err != nil {
+		return nil, fmt.Errorf("failed to register prompts: %w", err)
+	}
+
 	return server, nil
 }
 
 // registerTools registers all SearXNG tools with the MCP server
 func (s *SearXNGServer) registerTools() error {
 	// Register simple search tool
-	tools.NewSearchTool(s.mcpServer, s.searxngClient)
+	tools.NewSearchTool(s.mcpServer, s.searxngClient, s.cursors, s.render)
 
 	// Register category search tool
-	tools.NewCategorySearchTool(s.mcpServer, s.searxngClient)
+	tools.NewCategorySearchTool(s.mcpServer, s.searxngClient, s.cursors, s.render)
 
 	// Register advanced search tool
-	tools.NewAdvancedSearchTool(s.mcpServer, s.searxngClient)
+	tools.NewAdvancedSearchTool(s.mcpServer, s.searxngClient, s.cursors, s.render)
+
+	// Register image search tool
+	tools.NewImageSearchTool(s.mcpServer, s.searxngClient, s.images, s.cursors, s.render)
+
+	// Register news digest tool
+	tools.NewNewsDigestTool(s.mcpServer, s.searxngClient, s.render)
+
+	// Register academic paper search tool
+	tools.NewSearchPapersTool(s.mcpServer, s.searxngClient, s.resolvers, s.cursors, s.render)
+
+	// Register place search tool
+	tools.NewSearchPlacesTool(s.mcpServer, s.searxngClient, s.render)
+
+	// Register video search tool
+	tools.NewSearchVideosTool(s.mcpServer, s.searxngClient, s.cursors, s.render)
+
+	// Register developer code search tool
+	tools.NewSearchCodeTool(s.mcpServer, s.searxngClient, s.cursors, s.render)
+
+	// Register batch search tool
+	tools.NewBatchSearchTool(s.mcpServer, s.searxngClient, s.render)
+
+	// Register deep research tool
+	tools.NewResearchTool(s.mcpServer, s.searxngClient, s.render)
+
+	// Register page fetch tool
+	tools.NewFetchURLTool(s.mcpServer, s.fetcher, s.render)
+
+	// Register search and read tool
+	tools.NewSearchAndReadTool(s.mcpServer, s.searxngClient, s.fetcher, s.render)
+
+	// Register claim verification tool
+	tools.NewVerifyClaimTool(s.mcpServer, s.searxngClient, s.fetcher, s.render)
+
+	// Register search comparison tool
+	tools.NewCompareSearchesTool(s.mcpServer, s.compareClient, s.instances, s.render)
+
+	// Register search continuation tool
+	tools.NewSearchNextTool(s.mcpServer, s.cursors)
+
+	// Register query watch tools
+	tools.NewWatchQueryTool(s.mcpServer, s.watcher, s.render)
+	tools.NewUnwatchQueryTool(s.mcpServer, s.watcher, s.render)
+
+	// Register search history tools when the history is enabled
+	if s.history != nil {
+		tools.NewSearchHistoryTool(s.mcpServer, s.history, s.render)
+		tools.NewRerunSearchTool(s.mcpServer, s.cursors, s.history, s.render)
+		tools.NewPurgeHistoryTool(s.mcpServer, s.history, s.render)
+	}
+
+	// Register engine reliability report tool
+	tools.NewEngineStatsTool(s.mcpServer, s.tracker, s.render)
 
 	return nil
 }
 
+// registerResources registers the search and watch resources with the MCP server;
+// result resources are added as searches return them
+func (s *SearXNGServer) registerResources(watches *resources.Watches) {
+	resources.NewSearchTemplate(s.mcpServer, s.searxngClient)
+	watches.Register(s.mcpServer)
+}
+
+// registerPrompts registers the built-in research prompts and the custom prompts
+// of dir, which replace built-in prompts of the same name
+func (s *SearXNGServer) registerPrompts(dir string) error {
+	custom, err := prompts.LoadDir(dir)
+	if err != nil {
+		return err
+	}
+	return prompts.Register(s.mcpServer, append(prompts.Builtin(), custom...))
+}
+
 // Run starts the MCP server with the given transport
 func (s *SearXNGServer) Run(ctx context.Context, transport mcp.Transport) error {
+	// Refresh watched queries while the server runs
+	go s.watcher.Run(ctx)
+
+	// Write the engine statistics of the last searches on the way out
+	defer s.tracker.Flush()
 	return s.mcpServer.Run(ctx, transport)
 }
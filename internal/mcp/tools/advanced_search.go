@@ -11,10 +11,10 @@ import (
 )
 
 // NewAdvancedSearchTool creates and registers an advanced search tool
-func NewAdvancedSearchTool(server *mcp.Server, client searxng.Client) {
+func NewAdvancedSearchTool(server *mcp.Server, client searxng.Client, cursors *Cursors, render Renderers) {
 	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
 	
-	mcp.AddTool(server, &mcp.Tool{
+	addPagedTool(server, cursors, &mcp.Tool{
 		Name:        "search_advanced",
 		Description: fmt.Sprintf("Perform an advanced search with language, time range, and pagination options using SearXNG. Available time ranges: %s", availableTimeRanges),
 		InputSchema: &jsonschema.Schema{
@@ -39,10 +39,19 @@ func NewAdvancedSearchTool(server *mcp.Server, client searxng.Client) {
 					Minimum:     floatPtr(1),
 					Maximum:     floatPtr(50),
 				},
+				"limit":           limitProperty(),
+				"max_tokens":      maxTokensProperty(),
+				"include_domains": domainsProperty("Only return results from these domains."),
+				"exclude_domains": domainsProperty("Never return results from these domains."),
+				"format":          formatProperty(),
 			},
 			Required: []string{"query"},
 		},
+		OutputSchema: outputSchema[SearchOutput](),
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args AdvancedSearchArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
 		// Validate query
 		if args.Query == "" {
 			return &mcp.CallToolResult{
@@ -88,7 +97,23 @@ func NewAdvancedSearchTool(server *mcp.Server, client searxng.Client) {
 			opts.TimeRange = timeRange
 		}
 
+		// Narrow the search to the requested domains
+		client, err := domainClient(client, args.IncludeDomains, args.ExcludeDomains)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Continue from the cursor of an earlier call
+		page, seen := resumed(ctx, opts.PageNo)
+		opts.PageNo = page
+
 		// Perform search
+		budget := newResultBudget(ctx, args.Limit, args.MaxTokens)
 		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
 		if err != nil {
 			return &mcp.CallToolResult{
@@ -100,13 +125,7 @@ func NewAdvancedSearchTool(server *mcp.Server, client searxng.Client) {
 		}
 
 		// Format results for MCP
-		content := formatSearchResultsJSON(response)
-
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: content},
-			},
-		}, nil, nil
+		return render.Result(args.Format, budget.pagedOutput(ctx, client, "search_advanced", args, args.Query, opts, response, seen))
 	})
 }
 
@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Limits for the batch search tool
+const (
+	maxBatchQueries         = 20
+	defaultBatchConcurrency = 4
+	maxBatchConcurrency     = 10
+	defaultBatchTimeout     = 30
+	maxBatchTimeout         = 120
+)
+
+// BatchQueryResult represents the results of one query in a batch
+type BatchQueryResult struct {
+	Index   int                `json:"index"`
+	Query   string             `json:"query"`
+	Total   int                `json:"total"`
+	Results []SimplifiedResult `json:"results"`
+	Omitted int                `json:"duplicates_omitted,omitempty"`
+	Error   *string            `json:"error,omitempty"`
+}
+
+// BatchOutput is the structured result of the search_batch tool
+type BatchOutput struct {
+	Results    []BatchQueryResult `json:"results"`
+	Queries    int                `json:"queries"`
+	Failed     int                `json:"failed"`
+	SharedURLs []SharedURL        `json:"shared_urls,omitempty"`
+}
+
+// SharedURL represents a URL returned by several queries of a batch
+type SharedURL struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Queries []int  `json:"queries"`
+}
+
+// NewBatchSearchTool creates and registers a tool that runs several searches in one call
+func NewBatchSearchTool(server *mcp.Server, client searxng.Client, render Renderers) {
+	availableCategories := strings.Join(getAllCategoryNames(), ", ")
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_batch",
+		Description: fmt.Sprintf("Run several searches concurrently in one call, each with its own categories, language and time range, under a shared concurrency limit and deadline. Optionally deduplicates URLs across queries and reports which URLs several queries found. Returns no next_cursor; page through a query with its page field. Available categories: %s", availableCategories),
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"queries": {
+					Type:        "array",
+					Description: fmt.Sprintf("Query specifications to run (1-%d)", maxBatchQueries),
+					MinItems:    intPtr(1),
+					MaxItems:    intPtr(maxBatchQueries),
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"query": {
+								Type:        "string",
+								Description: "The search query to execute",
+							},
+							"categories": {
+								Type:        "array",
+								Description: fmt.Sprintf("Categories to search in (default general). Available: %s", availableCategories),
+								Items: &jsonschema.Schema{
+									Type: "string",
+									Enum: interfaceSlice(getAllCategoryNames()),
+								},
+							},
+							"language": {
+								Type:        "string",
+								Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+							},
+							"time_range": {
+								Type:        "string",
+								Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+								Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+							},
+							"page": {
+								Type:        "integer",
+								Description: "Page number for pagination (1-50)",
+								Minimum:     floatPtr(1),
+								Maximum:     floatPtr(50),
+							},
+						},
+						Required: []string{"query"},
+					},
+				},
+				"concurrency": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of searches in flight (default %d)", defaultBatchConcurrency),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxBatchConcurrency),
+				},
+				"timeout_seconds": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Deadline for the whole batch in seconds (default %d)", defaultBatchTimeout),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxBatchTimeout),
+				},
+				"limit": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of results per query (1-%d, default %d)", maxResultLimit, defaultResultLimit),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxResultLimit),
+				},
+				"dedup": {
+					Type:        "boolean",
+					Description: "Omit URLs already returned by an earlier query and list URLs found by several queries",
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"queries"},
+		},
+		OutputSchema: outputSchema[BatchOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args BatchSearchArgs) (*mcp.CallToolResult, any, error) {
+		// Report the queries completed to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate queries
+		if len(args.Queries) == 0 || len(args.Queries) > maxBatchQueries {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: queries parameter must contain between 1 and %d query specifications", maxBatchQueries)},
+				},
+			}, nil, nil
+		}
+
+		// Apply the shared deadline
+		timeout := time.Duration(clampArg(ctx, "timeout_seconds", args.TimeoutSeconds, defaultBatchTimeout, maxBatchTimeout)) * time.Second
+		batchCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		// Run the queries
+		responses := make([]*searxng.SearchResponse, len(args.Queries))
+		errs := make([]error, len(args.Queries))
+		forEachBounded(batchCtx, len(args.Queries), clampArg(ctx, "concurrency", args.Concurrency, defaultBatchConcurrency, maxBatchConcurrency), func(ctx context.Context, i int) {
+			responses[i], errs[i] = runBatchQuery(ctx, client, args.Queries[i])
+			reportProgress(ctx, 1, float64(len(args.Queries)), fmt.Sprintf("Completed query '%s'", args.Queries[i].Query))
+		})
+
+		// Format results for MCP
+		limit := clampArg(ctx, "limit", args.Limit, defaultResultLimit, maxResultLimit)
+		return render.Result(args.Format, newBatchOutput(args.Queries, responses, errs, limit, args.Dedup, batchCtx.Err()))
+	})
+}
+
+// runBatchQuery validates a query specification and performs the search
+func runBatchQuery(ctx context.Context, client searxng.Client, spec BatchQuery) (*searxng.SearchResponse, error) {
+	if spec.Query == "" {
+		return nil, fmt.Errorf("query is required and must be a non-empty string")
+	}
+
+	categories, err := validateCategories(spec.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := searxng.SearchOptions{
+		Language:   spec.Language,
+		PageNo:     1,
+		Categories: categories,
+	}
+
+	if spec.Page > 0 {
+		if spec.Page > 50 {
+			return nil, fmt.Errorf("page must be between 1 and 50")
+		}
+		opts.PageNo = spec.Page
+	}
+
+	if spec.TimeRange != "" {
+		timeRange, err := searxng.ValidateTimeRange(spec.TimeRange)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time_range '%s'. Valid options are: %s",
+				spec.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))
+		}
+		opts.TimeRange = timeRange
+	}
+
+	return searxng.SearchWithOptions(ctx, client, spec.Query, opts)
+}
+
+// newBatchOutput builds the structured result of a batch with up to limit results per query,
+// optionally deduplicating across queries
+func newBatchOutput(specs []BatchQuery, responses []*searxng.SearchResponse, errs []error, limit int, dedup bool, deadlineErr error) BatchOutput {
+	results := make([]BatchQueryResult, len(specs))
+	seenBy := make(map[string][]int)
+	firstSeen := make(map[string]searxng.SearchResult)
+	var order []string
+	failed := 0
+
+	for i, spec := range specs {
+		results[i] = BatchQueryResult{
+			Index:   i,
+			Query:   spec.Query,
+			Results: []SimplifiedResult{},
+		}
+
+		err := errs[i]
+		if err == nil && responses[i] == nil {
+			err = deadlineErr
+			if err == nil {
+				err = context.Canceled
+			}
+		}
+		if err != nil {
+			failed++
+			results[i].Error = optionalString(err.Error())
+			continue
+		}
+
+		response := responses[i]
+		results[i].Total = response.NumberOfResults
+		for _, r := range response.Results {
+			if len(results[i].Results) >= limit {
+				break
+			}
+
+			key := searxng.NormalizeURL(r.URL)
+			queries := seenBy[key]
+			if len(queries) == 0 {
+				order = append(order, key)
+				firstSeen[key] = r
+			}
+			if len(queries) == 0 || queries[len(queries)-1] != i {
+				seenBy[key] = append(queries, i)
+			}
+
+			if dedup && len(queries) > 0 && queries[0] != i {
+				results[i].Omitted++
+				continue
+			}
+			results[i].Results = append(results[i].Results, simplifyResult(len(results[i].Results)+1, r))
+		}
+	}
+
+	out := BatchOutput{
+		Results: results,
+		Queries: len(specs),
+		Failed:  failed,
+	}
+
+	if dedup {
+		for _, key := range order {
+			if len(seenBy[key]) > 1 {
+				out.SharedURLs = append(out.SharedURLs, SharedURL{URL: firstSeen[key].URL, Title: firstSeen[key].Title, Queries: seenBy[key]})
+			}
+		}
+		sort.SliceStable(out.SharedURLs, func(i, j int) bool { return len(out.SharedURLs[i].Queries) > len(out.SharedURLs[j].Queries) })
+	}
+
+	return out
+}
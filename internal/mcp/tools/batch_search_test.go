@@ -0,0 +1,95 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Batch Search", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			query := r.FormValue("q")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{
+				"query": %q,
+				"number_of_results": 2,
+				"results": [
+					{"url": "https://go.dev/doc/", "title": "Go docs", "content": "shared"},
+					{"url": "https://example.com/%s", "title": "Only %s", "content": "unique"}
+				]
+			}`, query, query, query)
+		}))
+
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewBatchSearchTool(s, searxng.NewClient(server.URL), tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should run every query and report per-query errors", func() {
+		out := callToolJSON(ctx, session, "search_batch", map[string]any{
+			"queries": []map[string]any{
+				{"query": "generics", "categories": []string{"it"}},
+				{"query": "modules", "language": "en", "time_range": "year"},
+				{"query": ""},
+			},
+		})
+
+		Expect(out["failed"]).To(BeNumerically("==", 1))
+		results := out["results"].([]any)
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].(map[string]any)["results"]).To(HaveLen(2))
+		Expect(results[2].(map[string]any)["error"]).To(ContainSubstring("query is required"))
+		Expect(out).NotTo(HaveKey("shared_urls"))
+	})
+
+	It("should cap the results of each query at the limit", func() {
+		out := callToolJSON(ctx, session, "search_batch", map[string]any{
+			"queries": []map[string]any{{"query": "generics"}, {"query": "modules"}},
+			"limit":   1,
+		})
+
+		for _, result := range out["results"].([]any) {
+			Expect(result.(map[string]any)["results"]).To(HaveLen(1))
+		}
+	})
+
+	It("should deduplicate URLs across queries", func() {
+		out := callToolJSON(ctx, session, "search_batch", map[string]any{
+			"queries": []map[string]any{{"query": "generics"}, {"query": "modules"}},
+			"dedup":   true,
+		})
+
+		results := out["results"].([]any)
+		second := results[1].(map[string]any)
+		Expect(second["results"]).To(HaveLen(1))
+		Expect(second["duplicates_omitted"]).To(BeNumerically("==", 1))
+
+		shared := out["shared_urls"].([]any)
+		Expect(shared).To(HaveLen(1))
+		Expect(shared[0].(map[string]any)["url"]).To(Equal("https://go.dev/doc/"))
+		Expect(shared[0].(map[string]any)["queries"]).To(Equal([]any{0.0, 1.0}))
+	})
+})
@@ -0,0 +1,355 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"searxng-mcp/internal/mcp/logging"
+	"searxng-mcp/pkg/searxng"
+)
+
+const (
+	// defaultResultLimit is the number of results returned when no limit is given
+	defaultResultLimit = 10
+	// maxResultLimit caps the limit argument, and the results fitted to a token budget
+	maxResultLimit = 50
+	// maxSummaryRunes caps the length of a result summary
+	maxSummaryRunes = 500
+	// minSummaryRunes is the shortest summary worth keeping; shorter allowances drop it
+	minSummaryRunes = 24
+	// minMaxTokens is the smallest accepted token budget
+	minMaxTokens = 100
+	// runesPerToken approximates how many characters a model token covers
+	runesPerToken = 4
+	// resultOverheadTokens approximates the markup around each result: rank, field names, links
+	resultOverheadTokens = 12
+	// maxExtraPages caps the pages fetched beyond the first to fill a limit
+	maxExtraPages = 4
+)
+
+// Omission reports what a result limit or token budget left out of the output
+type Omission struct {
+	// Results counts fetched results left out
+	Results int `json:"results"`
+	// Truncated counts summaries shortened or dropped
+	Truncated int `json:"truncated"`
+	// Characters counts the summary characters cut
+	Characters int `json:"characters"`
+}
+
+// resultBudget bounds how many results, and how much summary text, a search output carries
+type resultBudget struct {
+	// Limit is the maximum number of results
+	Limit int
+	// MaxTokens is the approximate token budget of the output; 0 means unbounded
+	MaxTokens int
+}
+
+// newResultBudget validates the limit and max_tokens arguments. A token budget
+// without a limit fits as many results as the budget allows.
+func newResultBudget(ctx context.Context, limit, maxTokens int) resultBudget {
+	budget := resultBudget{Limit: clampArg(ctx, "limit", limit, defaultResultLimit, maxResultLimit)}
+	if maxTokens > 0 {
+		if maxTokens < minMaxTokens {
+			logging.FromContext(ctx).WarnContext(ctx, "argument raised to its minimum", "argument", "max_tokens", "value", maxTokens, "min", minMaxTokens)
+		}
+		budget.MaxTokens = max(maxTokens, minMaxTokens)
+		if limit <= 0 {
+			budget.Limit = maxResultLimit
+		}
+	}
+	return budget
+}
+
+// searchPages records which result pages a search output was built from
+type searchPages struct {
+	First int
+	Last  int
+	// Exhausted is set when no results follow the last page
+	Exhausted bool
+	// offsets holds the index in the results of the first result of each page
+	// after First; nil means every result came from First
+	offsets []int
+}
+
+// pageOf returns the page the result at index came from
+func (p searchPages) pageOf(index int) int {
+	page := p.First
+	for i, offset := range p.offsets {
+		if offset <= index {
+			page = p.First + i + 1
+		}
+	}
+	return page
+}
+
+// fillPages fetches the pages after opts.PageNo while the results of response
+// fall short of the budget, appending results not seen on earlier pages. Results
+// whose URL skip holds are dropped from every page. A page that fails or adds
+// nothing new ends the paging with the results gathered so far.
+func (b resultBudget) fillPages(ctx context.Context, client searxng.Client, query string, opts searxng.SearchOptions, response *searxng.SearchResponse, skip *urlSet) searchPages {
+	pages := searchPages{First: max(opts.PageNo, 1)}
+	pages.Last = pages.First
+	pages.Exhausted = len(response.Results) == 0
+	reportProgress(ctx, 1, 0, fmt.Sprintf("Fetched page %d", pages.First))
+	response.Results = skip.unseen(response.Results)
+
+	seen := make(map[string]bool)
+	for _, r := range response.Results {
+		seen[searxng.NormalizeURL(r.URL)] = true
+	}
+
+	for extra := 0; extra < maxExtraPages && !pages.Exhausted && pages.Last < maxSearchPage && b.wantsMore(response.Results); extra++ {
+		if ctx.Err() != nil {
+			break
+		}
+		opts.PageNo = pages.Last + 1
+		next, err := searxng.SearchWithOptions(ctx, client, query, opts)
+		if err != nil {
+			break
+		}
+		reportProgress(ctx, 1, 0, fmt.Sprintf("Fetched page %d", opts.PageNo))
+		if len(next.Results) == 0 {
+			pages.Exhausted = true
+			break
+		}
+
+		offset := len(response.Results)
+		added := 0
+		for _, r := range skip.unseen(next.Results) {
+			if key := searxng.NormalizeURL(r.URL); !seen[key] {
+				seen[key] = true
+				response.Results = append(response.Results, r)
+				added++
+			}
+		}
+		if added == 0 {
+			break
+		}
+		pages.Last = opts.PageNo
+		pages.offsets = append(pages.offsets, offset)
+	}
+	return pages
+}
+
+// pagedOutput fills the pages of a search tool call and builds its budgeted
+// output, with a cursor continuing the call from its next page. seen holds the
+// URLs returned by the calls the cursor continues.
+func (b resultBudget) pagedOutput(ctx context.Context, client searxng.Client, tool string, args any, query string, opts searxng.SearchOptions, response *searxng.SearchResponse, seen *urlSet) SearchOutput {
+	pages := b.fillPages(ctx, client, query, opts, response, seen)
+	out := b.searchOutput(response, pages)
+	out.request = searxng.SearchRequest{
+		Query:     query,
+		Language:  opts.Language,
+		TimeRange: opts.TimeRange,
+		PageNo:    pages.First,
+		Category:  opts.Categories,
+	}
+	if len(out.request.Category) == 0 {
+		out.request.Category = []searxng.Category{searxng.CategoryGeneral}
+	}
+	if out.Pagination.NextPage != nil {
+		for _, r := range out.Results {
+			seen.add(r.URL)
+		}
+		out.Pagination.NextCursor = encodeCursor(tool, args, *out.Pagination.NextPage, seen)
+	}
+	return out
+}
+
+// wantsMore reports whether another page of results could still fit
+func (b resultBudget) wantsMore(results []searxng.SearchResult) bool {
+	if len(results) >= b.Limit {
+		return false
+	}
+	if b.MaxTokens == 0 {
+		return true
+	}
+	used := 0
+	for _, r := range results {
+		used += resultTokens(r)
+	}
+	return used < b.MaxTokens
+}
+
+// searchOutput builds the structured result of the fetched pages, fitting as
+// many results as the limit and token budget allow, then sharing the remaining
+// budget between their summaries
+func (b resultBudget) searchOutput(response *searxng.SearchResponse, pages searchPages) SearchOutput {
+	out := SearchOutput{
+		Query:       response.Query,
+		Total:       response.NumberOfResults,
+		Results:     []SimplifiedResult{},
+		Suggestions: response.Suggestions,
+		Corrections: response.Corrections,
+		Pagination:  Pagination{Page: pages.First},
+	}
+	for _, infobox := range response.Infoboxes {
+		out.Infoboxes = append(out.Infoboxes, newInfoboxResult(infobox))
+	}
+	if pages.Last > pages.First {
+		out.Pagination.LastPage = &pages.Last
+	}
+	// SearXNG does not report a page count, so a non-empty page implies another may follow
+	if !pages.Exhausted && pages.Last < maxSearchPage {
+		next := pages.Last + 1
+		out.Pagination.NextPage = &next
+	}
+	candidates := response.Results[:min(len(response.Results), b.Limit)]
+	var remaining int
+	if b.MaxTokens > 0 {
+		remaining = b.MaxTokens - estimateTokens(marshalText(out))
+		for i, r := range candidates {
+			if remaining < resultTokens(r) {
+				candidates = candidates[:i]
+				break
+			}
+			remaining -= resultTokens(r)
+		}
+	}
+
+	summaries := make([]string, len(candidates))
+	for i, r := range candidates {
+		summaries[i] = r.Content
+	}
+	allowances := summaryAllowances(summaries, remaining*runesPerToken, b.MaxTokens > 0)
+
+	omitted := Omission{Results: len(response.Results) - len(candidates)}
+	for i, r := range candidates {
+		result := simplifyResult(i+1, r)
+		summary, cut := truncateText(r.Content, allowances[i])
+		result.Summary = summary
+		if cut > 0 {
+			omitted.Truncated++
+			omitted.Characters += cut
+		}
+		out.Results = append(out.Results, result)
+	}
+	if omitted != (Omission{}) {
+		out.Omitted = &omitted
+	}
+
+	// Results left out are read again from their page, which the cursor continues
+	// from skipping the returned ones
+	if omitted.Results > 0 && len(candidates) > 0 {
+		next := pages.pageOf(len(candidates))
+		out.Pagination.NextPage = &next
+	}
+	if b.MaxTokens > 0 {
+		out.EstimatedTokens = estimateTokens(marshalText(out))
+	}
+	return out
+}
+
+// summaryAllowances shares budget runes between summaries, giving short
+// summaries all they need and splitting the rest evenly between the longer ones.
+// Each allowance is capped at maxSummaryRunes; an unbounded budget only applies the cap.
+func summaryAllowances(summaries []string, budget int, bounded bool) []int {
+	allowances := make([]int, len(summaries))
+	order := make([]int, len(summaries))
+	for i, s := range summaries {
+		allowances[i] = min(utf8.RuneCountInString(s), maxSummaryRunes)
+		order[i] = i
+	}
+	if !bounded {
+		return allowances
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int { return allowances[a] - allowances[b] })
+	for n, i := range order {
+		share := max(budget, 0) / (len(order) - n)
+		allowances[i] = min(allowances[i], share)
+		budget -= allowances[i]
+	}
+	return allowances
+}
+
+// truncateText shortens s to at most maxRunes runes, cutting at a word boundary
+// and marking the cut with an ellipsis. It never splits a multi-byte rune. An
+// allowance below minSummaryRunes drops the text entirely. It returns the text
+// and the number of runes cut.
+func truncateText(s string, maxRunes int) (string, int) {
+	s = strings.TrimSpace(s)
+	total := utf8.RuneCountInString(s)
+	if total <= maxRunes {
+		return s, 0
+	}
+	if maxRunes < minSummaryRunes {
+		return "", total
+	}
+
+	// Leave room for the ellipsis, then cut on a rune boundary
+	end, kept := len(s), 0
+	for i := range s {
+		if kept == maxRunes-1 {
+			end = i
+			break
+		}
+		kept++
+	}
+	cut := s[:end]
+
+	// Back off to the last word boundary unless that discards most of the text
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	cut = strings.TrimRightFunc(cut, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + "…"
+	return cut, total - utf8.RuneCountInString(cut) + 1
+}
+
+// estimateTokens approximates the number of model tokens of s
+func estimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + runesPerToken - 1) / runesPerToken
+}
+
+// resultTokens approximates the tokens of a result without its summary
+func resultTokens(r searxng.SearchResult) int {
+	return estimateTokens(r.Title) + estimateTokens(r.URL) + resultOverheadTokens
+}
+
+// marshalText returns the JSON encoding of v, or an empty string
+func marshalText(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// limitProperty is the input schema of the limit argument of the search tools
+func limitProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Maximum number of results to return (1-50, default 10, or as many as max_tokens allows). Further result pages are fetched when one page does not fill the limit.",
+		Minimum:     floatPtr(1),
+		Maximum:     floatPtr(maxResultLimit),
+	}
+}
+
+// maxTokensProperty is the input schema of the max_tokens argument of the search tools
+func maxTokensProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Approximate token budget of the output. As many results as fit are returned, with summaries shortened at word boundaries to share the remaining budget; the output reports what was omitted.",
+		Minimum:     floatPtr(minMaxTokens),
+	}
+}
+
+// describe summarizes the omission in one sentence
+func (o Omission) describe() string {
+	var parts []string
+	if o.Results > 0 {
+		parts = append(parts, fmt.Sprintf("Omitted %s", plural(o.Results, "more result")))
+	}
+	if o.Truncated > 0 {
+		parts = append(parts, fmt.Sprintf("shortened %s by %s", plural(o.Truncated, "snippet"), plural(o.Characters, "character")))
+	}
+	s := strings.Join(parts, ", ")
+	return strings.ToUpper(s[:1]) + s[1:]
+}
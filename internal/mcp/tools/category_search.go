@@ -11,10 +11,10 @@ import (
 )
 
 // NewCategorySearchTool creates and registers a category search tool
-func NewCategorySearchTool(server *mcp.Server, client searxng.Client) {
+func NewCategorySearchTool(server *mcp.Server, client searxng.Client, cursors *Cursors, render Renderers) {
 	availableCategories := strings.Join(getAllCategoryNames(), ", ")
 	
-	mcp.AddTool(server, &mcp.Tool{
+	addPagedTool(server, cursors, &mcp.Tool{
 		Name:        "search_category",
 		Description: fmt.Sprintf("Perform a search in specific categories using SearXNG. Available categories: %s", availableCategories),
 		InputSchema: &jsonschema.Schema{
@@ -33,10 +33,19 @@ func NewCategorySearchTool(server *mcp.Server, client searxng.Client) {
 					},
 					MinItems: intPtr(1),
 				},
+				"limit":           limitProperty(),
+				"max_tokens":      maxTokensProperty(),
+				"include_domains": domainsProperty("Only return results from these domains."),
+				"exclude_domains": domainsProperty("Never return results from these domains."),
+				"format":          formatProperty(),
 			},
 			Required: []string{"query", "categories"},
 		},
+		OutputSchema: outputSchema[SearchOutput](),
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args CategorySearchArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
 		// Validate query
 		if args.Query == "" {
 			return &mcp.CallToolResult{
@@ -58,41 +67,32 @@ func NewCategorySearchTool(server *mcp.Server, client searxng.Client) {
 		}
 
 		// Convert to searxng.Category slice and validate
-		var categories []searxng.Category
-		var invalidCategories []string
-
-		for _, catStr := range args.Categories {
-			category, err := searxng.ValidateCategory(catStr)
-			if err != nil {
-				invalidCategories = append(invalidCategories, catStr)
-				continue
-			}
-			categories = append(categories, category)
-		}
-
-		if len(invalidCategories) > 0 {
-			validCategoriesFormatted := strings.Join(getAllCategoryNames(), "\", \"")
+		categories, err := validateCategories(args.Categories)
+		if err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
 				Content: []mcp.Content{
-					&mcp.TextContent{Text: fmt.Sprintf("Error: invalid categories found: \"%s\". Please use only valid categories from: [\"%s\"]. Example: {\"query\": \"machine learning\", \"categories\": [\"science\", \"it\"]}",
-						strings.Join(invalidCategories, "\", \""),
-						validCategoriesFormatted)},
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
 				},
 			}, nil, nil
 		}
 
-		if len(categories) == 0 {
+		// Narrow the search to the requested domains
+		client, err := domainClient(client, args.IncludeDomains, args.ExcludeDomains)
+		if err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
 				Content: []mcp.Content{
-					&mcp.TextContent{Text: "Error: no valid categories provided"},
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
 				},
 			}, nil, nil
 		}
 
-		// Perform search
-		response, err := searxng.SearchWithCategory(ctx, client, args.Query, categories...)
+		// Perform search, continuing the cursor of an earlier call when resumed
+		budget := newResultBudget(ctx, args.Limit, args.MaxTokens)
+		page, seen := resumed(ctx, 1)
+		opts := searxng.SearchOptions{PageNo: page, Categories: categories}
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
 		if err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
@@ -103,13 +103,7 @@ func NewCategorySearchTool(server *mcp.Server, client searxng.Client) {
 		}
 
 		// Format results for MCP
-		content := formatSearchResultsJSON(response)
-
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: content},
-			},
-		}, nil, nil
+		return render.Result(args.Format, budget.pagedOutput(ctx, client, "search_category", args, args.Query, opts, response, seen))
 	})
 }
 
@@ -124,6 +118,31 @@ func getAllCategoryNames() []string {
 	return names
 }
 
+// validateCategories converts category names, defaulting to general when empty
+func validateCategories(names []string) ([]searxng.Category, error) {
+	var categories []searxng.Category
+	var invalidCategories []string
+
+	for _, catStr := range names {
+		category, err := searxng.ValidateCategory(catStr)
+		if err != nil {
+			invalidCategories = append(invalidCategories, catStr)
+			continue
+		}
+		categories = append(categories, category)
+	}
+
+	if len(invalidCategories) > 0 {
+		return nil, fmt.Errorf("invalid categories found: \"%s\". Valid categories are: %s",
+			strings.Join(invalidCategories, "\", \""), strings.Join(getAllCategoryNames(), ", "))
+	}
+
+	if len(categories) == 0 {
+		categories = []searxng.Category{searxng.CategoryGeneral}
+	}
+	return categories, nil
+}
+
 // interfaceSlice converts string slice to interface slice for JSON schema enum
 func interfaceSlice(strings []string) []interface{} {
 	result := make([]interface{}, len(strings))
@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/compare"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Limits for the compare searches tool
+const (
+	maxCompareVariants  = 5
+	defaultComparePages = 1
+	maxComparePages     = 3
+)
+
+// DefaultInstance names the SearXNG instance the server searches by default
+const DefaultInstance = "default"
+
+// CompareOutput is the structured result of the compare_searches tool
+type CompareOutput struct {
+	Query string `json:"query"`
+	compare.Comparison
+	// Errors lists the variants left out of the comparison because their searches failed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// NewCompareSearchesTool creates and registers a tool comparing the results of variants
+// of one query. instances holds the SearXNG instances variants may name besides the default one.
+func NewCompareSearchesTool(server *mcp.Server, client searxng.Client, instances map[string]searxng.Client, render Renderers) {
+	availableCategories := strings.Join(getAllCategoryNames(), ", ")
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+	instanceNames := append([]string{DefaultInstance}, sortedKeys(instances)...)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "compare_searches",
+		Description: "Run two or more variants of one query, differing by language, time range or SearXNG instance, and compare their results by normalized URL: results every variant returned, results unique to each variant, rank changes and the Jaccard similarity of URLs and domains between every two variants.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The search query every variant runs",
+				},
+				"variants": {
+					Type:        "array",
+					Description: fmt.Sprintf("Variants of the search to compare (2-%d)", maxCompareVariants),
+					MinItems:    intPtr(2),
+					MaxItems:    intPtr(maxCompareVariants),
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"label": {
+								Type:        "string",
+								Description: "Name of the variant in the comparison (defaults to its settings)",
+							},
+							"language": {
+								Type:        "string",
+								Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+							},
+							"time_range": {
+								Type:        "string",
+								Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+								Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+							},
+							"instance": {
+								Type:        "string",
+								Description: fmt.Sprintf("SearXNG instance to search (default %s). Available: %s", DefaultInstance, strings.Join(instanceNames, ", ")),
+								Enum:        interfaceSlice(instanceNames),
+							},
+						},
+					},
+				},
+				"categories": {
+					Type:        "array",
+					Description: fmt.Sprintf("Categories every variant searches in (default general). Available: %s", availableCategories),
+					Items: &jsonschema.Schema{
+						Type: "string",
+						Enum: interfaceSlice(getAllCategoryNames()),
+					},
+				},
+				"pages": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Number of result pages compared per variant (default %d)", defaultComparePages),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxComparePages),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query", "variants"},
+		},
+		OutputSchema: outputSchema[CompareOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args CompareSearchesArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		if len(args.Variants) < 2 || len(args.Variants) > maxCompareVariants {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: variants must hold between 2 and %d variants", maxCompareVariants)},
+				},
+			}, nil, nil
+		}
+
+		categories, err := validateCategories(args.Categories)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Resolve the options and instance of each variant
+		opts := make([]searxng.SearchOptions, len(args.Variants))
+		clients := make([]searxng.Client, len(args.Variants))
+		labels := make([]string, len(args.Variants))
+		used := make(map[string]bool)
+		for i, v := range args.Variants {
+			opts[i] = searxng.SearchOptions{Language: v.Language, Categories: categories}
+			if v.TimeRange != "" {
+				timeRange, err := searxng.ValidateTimeRange(v.TimeRange)
+				if err != nil {
+					return &mcp.CallToolResult{
+						IsError: true,
+						Content: []mcp.Content{
+							&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s' in variant %d. Valid options are: %s",
+								v.TimeRange, i+1, strings.Join(getAllTimeRangeNames(), ", "))},
+						},
+					}, nil, nil
+				}
+				opts[i].TimeRange = timeRange
+			}
+
+			clients[i] = client
+			if v.Instance != "" && v.Instance != DefaultInstance {
+				instance, ok := instances[v.Instance]
+				if !ok {
+					return &mcp.CallToolResult{
+						IsError: true,
+						Content: []mcp.Content{
+							&mcp.TextContent{Text: fmt.Sprintf("Error: unknown instance '%s' in variant %d. Valid options are: %s",
+								v.Instance, i+1, strings.Join(instanceNames, ", "))},
+						},
+					}, nil, nil
+				}
+				clients[i] = instance
+			}
+
+			labels[i] = variantLabel(v, i, used)
+		}
+
+		// Fetch every page of every variant concurrently
+		pages := clampArg(ctx, "pages", args.Pages, defaultComparePages, maxComparePages)
+		responses := make([]*searxng.SearchResponse, len(args.Variants)*pages)
+		errs := make([]error, len(responses))
+		forEachBounded(ctx, len(responses), defaultBatchConcurrency, func(ctx context.Context, i int) {
+			variant := i / pages
+			pageOpts := opts[variant]
+			pageOpts.PageNo = i%pages + 1
+			responses[i], errs[i] = searxng.SearchWithOptions(ctx, clients[variant], args.Query, pageOpts)
+			reportProgress(ctx, 1, float64(len(responses)), fmt.Sprintf("Fetched page %d of %s", pageOpts.PageNo, labels[variant]))
+		})
+
+		// Compare the variants whose searches returned results, in page order
+		var sets []compare.Set
+		var failures []string
+		for v, label := range labels {
+			set := compare.Set{Label: label}
+			var failure error
+			for p := 0; p < pages; p++ {
+				i := v*pages + p
+				if responses[i] == nil {
+					failure = errs[i]
+					if failure == nil {
+						failure = ctx.Err()
+					}
+					continue
+				}
+				set.Results = append(set.Results, responses[i].Results...)
+			}
+			if failure != nil && len(set.Results) == 0 {
+				failures = append(failures, fmt.Sprintf("%s: %v", label, failure))
+				continue
+			}
+			sets = append(sets, set)
+		}
+		if len(sets) < 2 {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %s", strings.Join(failures, "; "))},
+				},
+			}, nil, nil
+		}
+
+		// Format comparison for MCP
+		return render.Result(args.Format, CompareOutput{
+			Query:      args.Query,
+			Comparison: compare.Compare(sets),
+			Errors:     failures,
+		})
+	})
+}
+
+// variantLabel returns the label of a variant, describing its settings when
+// none is given and numbering it when another variant took the same label
+func variantLabel(v CompareVariant, index int, used map[string]bool) string {
+	label := strings.TrimSpace(v.Label)
+	if label == "" {
+		var parts []string
+		if v.Language != "" {
+			parts = append(parts, "language "+v.Language)
+		}
+		if v.TimeRange != "" {
+			parts = append(parts, "time_range "+v.TimeRange)
+		}
+		if v.Instance != "" && v.Instance != DefaultInstance {
+			parts = append(parts, "instance "+v.Instance)
+		}
+		label = firstNonEmptyString(strings.Join(parts, ", "), DefaultInstance)
+	}
+	if used[label] {
+		label = fmt.Sprintf("%s (%d)", label, index+1)
+	}
+	used[label] = true
+	return label
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Compare Searches", func() {
+	var (
+		main, mirror *httptest.Server
+		session      *mcp.ClientSession
+		ctx          context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		main = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.FormValue("language") == "fr" {
+				fmt.Fprint(w, `{"query": "rust", "results": [
+					{"url": "https://rust-lang.org/fr/", "title": "Rust (fr)"},
+					{"url": "https://doc.rust-lang.org/book", "title": "The Book"}
+				]}`)
+				return
+			}
+			fmt.Fprint(w, `{"query": "rust", "results": [
+				{"url": "https://doc.rust-lang.org/book", "title": "The Book"},
+				{"url": "https://www.rust-lang.org/", "title": "Rust"},
+				{"url": "https://en.wikipedia.org/wiki/Rust", "title": "Rust - Wikipedia"}
+			]}`)
+		}))
+		mirror = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"query": "rust", "results": [
+				{"url": "https://en.wikipedia.org/wiki/Rust", "title": "Rust - Wikipedia"},
+				{"url": "http://doc.rust-lang.org/book/?utm_source=mirror", "title": "The Book"}
+			]}`)
+		}))
+
+		instances := map[string]searxng.Client{"mirror": searxng.NewClient(mirror.URL)}
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewCompareSearchesTool(s, searxng.NewClient(main.URL), instances, tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		main.Close()
+		mirror.Close()
+	})
+
+	It("should compare languages and instances by normalized URL", func() {
+		out := callToolJSON(ctx, session, "compare_searches", map[string]any{
+			"query": "rust",
+			"variants": []map[string]any{
+				{"language": "en"},
+				{"language": "fr"},
+				{"instance": "mirror", "label": "mirror"},
+			},
+		})
+
+		variants := out["variants"].([]any)
+		Expect(variants).To(HaveLen(3))
+		Expect(variants[0].(map[string]any)["label"]).To(Equal("language en"))
+		Expect(variants[0].(map[string]any)["unique"]).To(HaveLen(1))
+		fr := variants[1].(map[string]any)
+		Expect(fr["label"]).To(Equal("language fr"))
+		Expect(fr["unique"]).To(HaveLen(1))
+
+		common := out["common"].([]any)
+		Expect(common).To(HaveLen(1))
+		Expect(common[0].(map[string]any)["ranks"]).To(Equal([]any{1.0, 2.0, 2.0}))
+
+		pairs := out["pairs"].([]any)
+		Expect(pairs).To(HaveLen(3))
+		enMirror := pairs[1].(map[string]any)
+		Expect(enMirror["b"]).To(Equal("mirror"))
+		Expect(enMirror["shared"]).To(BeNumerically("==", 2))
+		Expect(enMirror["url_jaccard"]).To(BeNumerically("~", 0.667, 0.001))
+		Expect(enMirror["rank_changes"]).To(HaveLen(2))
+	})
+
+	It("should render the comparison tables", func() {
+		text, isError := callToolText(ctx, session, "compare_searches", map[string]any{
+			"query":    "rust",
+			"variants": []map[string]any{{}, {"instance": "mirror"}},
+		})
+
+		Expect(isError).To(BeFalse())
+		Expect(text).To(ContainSubstring("| default | 3 | 1 | 3 |"))
+		Expect(text).To(ContainSubstring("| default vs instance mirror | 2 | 0.67 | 0.67 |"))
+		Expect(text).To(ContainSubstring("### Rank changes from default to instance mirror"))
+		Expect(text).To(ContainSubstring("[Rust - Wikipedia](https://en.wikipedia.org/wiki/Rust): 3 → 1 (+2)"))
+	})
+
+	It("should number repeated labels and reject unknown instances and single variants", func() {
+		text, isError := callToolText(ctx, session, "compare_searches", map[string]any{
+			"query":    "rust",
+			"variants": []map[string]any{{}, {"instance": "mirror"}, {"language": "en", "label": "default"}},
+		})
+		Expect(isError).To(BeFalse())
+		Expect(text).To(ContainSubstring("| default (3) |"))
+
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "compare_searches", Arguments: map[string]any{
+			"query":    "rust",
+			"variants": []map[string]any{{}, {"instance": "elsewhere"}},
+		}})
+		Expect(err).To(MatchError(ContainSubstring("elsewhere")))
+
+		_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "compare_searches", Arguments: map[string]any{
+			"query":    "rust",
+			"variants": []map[string]any{{}},
+		}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should leave out variants whose searches failed", func() {
+		mirror.Close()
+
+		out := callToolJSON(ctx, session, "compare_searches", map[string]any{
+			"query":    "rust",
+			"variants": []map[string]any{{"language": "en"}, {"language": "fr"}, {"instance": "mirror"}},
+		})
+		Expect(out["variants"]).To(HaveLen(2))
+		Expect(out["errors"]).To(ConsistOf(HavePrefix("instance mirror: ")))
+	})
+})
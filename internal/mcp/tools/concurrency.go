@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"context"
+	"sync"
+)
+
+// forEachBounded calls fn for indexes 0..n-1 with at most limit calls in flight.
+// It stops starting new calls once ctx is done and waits for running calls.
+func forEachBounded(ctx context.Context, n, limit int, fn func(ctx context.Context, i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/history"
+	"searxng-mcp/pkg/searxng"
+)
+
+const (
+	// cursorVersion is bumped whenever the cursor encoding changes
+	cursorVersion = 1
+	// maxCursorURLs caps the seen URLs a cursor carries, keeping the most recent
+	maxCursorURLs = 500
+	// maxCursorBytes bounds the decompressed size of a cursor
+	maxCursorBytes = 64 << 10
+)
+
+// cursorState is the request state carried by a cursor: the tool, its original
+// arguments, the page to continue from and the URLs already returned
+type cursorState struct {
+	Version int             `json:"v"`
+	Tool    string          `json:"t"`
+	Args    json.RawMessage `json:"a"`
+	Page    int             `json:"p"`
+	// Seen packs the 64-bit hashes of the normalized URLs already returned
+	Seen []byte `json:"s,omitempty"`
+}
+
+// encodeCursor returns an opaque cursor continuing tool at page, or an empty
+// string when the state cannot be encoded
+func encodeCursor(tool string, args any, page int, seen *urlSet) string {
+	rawArgs, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	data, err := json.Marshal(cursorState{Version: cursorVersion, Tool: tool, Args: rawArgs, Page: page, Seen: seen.packed()})
+	if err != nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	w.Write(data)
+	w.Close()
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// decodeCursor decodes a cursor returned by encodeCursor
+func decodeCursor(cursor string) (cursorState, error) {
+	var state cursorState
+	compressed, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return state, fmt.Errorf("malformed cursor")
+	}
+	data, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxCursorBytes))
+	if err != nil {
+		return state, fmt.Errorf("malformed cursor")
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("malformed cursor")
+	}
+	if state.Version != cursorVersion {
+		return state, fmt.Errorf("cursor version %d is not supported", state.Version)
+	}
+	if state.Page < 1 || state.Page > maxSearchPage {
+		return state, fmt.Errorf("cursor page %d is out of range (1-%d)", state.Page, maxSearchPage)
+	}
+	return state, nil
+}
+
+// urlSet records normalized URLs by hash, in the order they were added
+type urlSet struct {
+	order  []uint64
+	hashes map[uint64]bool
+}
+
+// newURLSet returns a set holding the packed hashes of a cursor
+func newURLSet(packed []byte) *urlSet {
+	s := &urlSet{hashes: make(map[uint64]bool)}
+	for len(packed) >= 8 {
+		s.addHash(binary.BigEndian.Uint64(packed))
+		packed = packed[8:]
+	}
+	return s
+}
+
+// has reports whether the set holds rawURL
+func (s *urlSet) has(rawURL string) bool {
+	return s.hashes[urlHash(rawURL)]
+}
+
+// add records rawURL
+func (s *urlSet) add(rawURL string) {
+	if rawURL != "" {
+		s.addHash(urlHash(rawURL))
+	}
+}
+
+// addHash records a URL hash
+func (s *urlSet) addHash(h uint64) {
+	if !s.hashes[h] {
+		s.hashes[h] = true
+		s.order = append(s.order, h)
+	}
+}
+
+// unseen returns the results whose URL the set does not hold
+func (s *urlSet) unseen(results []searxng.SearchResult) []searxng.SearchResult {
+	var kept []searxng.SearchResult
+	for _, r := range results {
+		if !s.has(r.URL) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// packed encodes the most recent maxCursorURLs hashes
+func (s *urlSet) packed() []byte {
+	order := s.order[max(len(s.order)-maxCursorURLs, 0):]
+	packed := make([]byte, 0, 8*len(order))
+	for _, h := range order {
+		packed = binary.BigEndian.AppendUint64(packed, h)
+	}
+	return packed
+}
+
+// urlHash hashes the normalized form of a URL
+func urlHash(rawURL string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(searxng.NormalizeURL(rawURL)))
+	return h.Sum64()
+}
+
+// cursorKey is the context key of the cursor a search_next call continues
+type cursorKey struct{}
+
+// resumed returns the page and seen URLs of the cursor being continued, or
+// page and an empty set when the call does not continue a cursor
+func resumed(ctx context.Context, page int) (int, *urlSet) {
+	if state, ok := ctx.Value(cursorKey{}).(cursorState); ok {
+		return state.Page, newURLSet(state.Seen)
+	}
+	return page, newURLSet(nil)
+}
+
+// Cursors continues searches from the cursors returned by the paged search
+// tools, and records the searches of their calls when given a history
+type Cursors struct {
+	handlers map[string]mcp.ToolHandler
+	history  *history.Store
+}
+
+// NewCursors creates an empty cursor registry
+func NewCursors() *Cursors {
+	return &Cursors{handlers: make(map[string]mcp.ToolHandler)}
+}
+
+// SetHistory records each call of search, search_category and search_advanced,
+// and each call search_next continues, in store
+func (c *Cursors) SetHistory(store *history.Store) {
+	c.history = store
+}
+
+// addPagedTool registers a tool whose cursors search_next can continue
+func addPagedTool[In any](server *mcp.Server, cursors *Cursors, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, any]) {
+	t, h := mcp.ToolFor(tool, handler)
+	if cursors == nil {
+		server.AddTool(t, h)
+		return
+	}
+	cursors.handlers[tool.Name] = h
+	server.AddTool(t, cursors.recorded(tool.Name, h))
+}
+
+// recorded wraps the handler of tool to record the search of each successful
+// call, with its arguments and the results it returned, once the history is set
+func (c *Cursors) recorded(tool string, handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		res, err := handler(ctx, req)
+		if c.history == nil || err != nil || res.IsError {
+			return res, err
+		}
+		if out, ok := res.StructuredContent.(SearchOutput); ok {
+			args, _ := json.Marshal(req.Params.Arguments)
+			c.history.Record(tool, args, out.request, out.searchResults())
+		}
+		return res, err
+	}
+}
+
+// rerun repeats the tool call of a history entry at the page it recorded, and
+// records the rerun as a new entry
+func (c *Cursors) rerun(ctx context.Context, req *mcp.CallToolRequest, entry history.Entry) (SearchOutput, history.Entry, error) {
+	handler, ok := c.handlers[entry.Tool]
+	if !ok || len(entry.Args) == 0 {
+		return SearchOutput{}, history.Entry{}, fmt.Errorf("history entry %d cannot be rerun", entry.ID)
+	}
+
+	state := cursorState{Version: cursorVersion, Tool: entry.Tool, Args: entry.Args, Page: max(entry.Page, 1)}
+	call := &mcp.CallToolRequest{Session: req.Session, Extra: req.Extra}
+	call.Params = &mcp.CallToolParams{Name: entry.Tool, Arguments: entry.Args}
+	res, err := handler(context.WithValue(ctx, cursorKey{}, state), call)
+	if err != nil {
+		return SearchOutput{}, history.Entry{}, err
+	}
+	if res.IsError {
+		return SearchOutput{}, history.Entry{}, errors.New(resultText(res))
+	}
+	out, ok := res.StructuredContent.(SearchOutput)
+	if !ok {
+		return SearchOutput{}, history.Entry{}, fmt.Errorf("history entry %d cannot be rerun", entry.ID)
+	}
+	return out, c.history.Record(entry.Tool, entry.Args, out.request, out.searchResults()), nil
+}
+
+// resultText returns the text of the first text content of a tool result
+func resultText(res *mcp.CallToolResult) string {
+	for _, content := range res.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// Continue runs the tool call a cursor encodes, optionally in another format
+func (c *Cursors) Continue(ctx context.Context, req *mcp.CallToolRequest, cursor, format string) (*mcp.CallToolResult, error) {
+	state, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	handler, ok := c.handlers[state.Tool]
+	if !ok {
+		return nil, fmt.Errorf("cursor belongs to unknown tool '%s'", state.Tool)
+	}
+
+	args := state.Args
+	if format != "" {
+		var fields map[string]any
+		if err := json.Unmarshal(args, &fields); err != nil {
+			return nil, fmt.Errorf("malformed cursor")
+		}
+		fields["format"] = format
+		if args, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+	}
+
+	call := &mcp.CallToolRequest{Session: req.Session, Extra: req.Extra}
+	call.Params = &mcp.CallToolParams{Name: state.Tool, Arguments: args}
+	if req.Params != nil {
+		call.Params.Meta = req.Params.Meta
+	}
+	return c.recorded(state.Tool, handler)(context.WithValue(ctx, cursorKey{}, state), call)
+}
+
+// nextPage returns the page a cursor continues from after a call that read up
+// to page last and returned some of the available results: the last page again
+// while results were left out, since the cursor skips the returned ones, and
+// the page after it otherwise
+func nextPage(last, returned, available int) int {
+	if returned > 0 && returned < available {
+		return last
+	}
+	return last + 1
+}
+
+// cursorAfter records the URLs of results and returns a cursor continuing tool
+// at page, or an empty string when page is past the last SearXNG page
+func cursorAfter(tool string, args any, page int, seen *urlSet, results []searxng.SearchResult) string {
+	if page > maxSearchPage {
+		return ""
+	}
+	for _, r := range results {
+		seen.add(r.URL)
+	}
+	return encodeCursor(tool, args, page, seen)
+}
+
+// nextCursor returns the cursor continuing the search
+func (o SearchOutput) nextCursor() string { return o.Pagination.NextCursor }
+
+// nextCursor returns the cursor continuing the search
+func (o ImageSearchOutput) nextCursor() string { return o.NextCursor }
+
+// nextCursor returns the cursor continuing the search
+func (o PapersOutput) nextCursor() string { return o.NextCursor }
+
+// nextCursor returns the cursor continuing the search
+func (o VideosOutput) nextCursor() string { return o.NextCursor }
+
+// nextCursor returns the cursor continuing the search
+func (o CodeOutput) nextCursor() string { return o.NextCursor }
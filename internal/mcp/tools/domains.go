@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"searxng-mcp/pkg/domains"
+	"searxng-mcp/pkg/searxng"
+)
+
+// domainClient narrows client to the include_domains and exclude_domains of a
+// call, adding site: operators to the query where the engines understand them
+func domainClient(client searxng.Client, include, exclude []string) (searxng.Client, error) {
+	allow, err := domains.ParseList(include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include_domains: %w", err)
+	}
+	block, err := domains.ParseList(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude_domains: %w", err)
+	}
+
+	policy := domains.Policy{Allow: allow, Block: block}
+	if policy.IsZero() {
+		return client, nil
+	}
+	return domains.NewClient(client, policy, true), nil
+}
+
+// domainsProperty is the input schema of the include_domains and exclude_domains arguments
+func domainsProperty(description string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: description + ` A domain such as "example.com" covers its subdomains; "*" wildcards such as "*.example.com" match whole hosts.`,
+		Items:       &jsonschema.Schema{Type: "string"},
+	}
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+)
+
+// EngineReport represents the reliability report of a single engine
+type EngineReport struct {
+	Engine              string  `json:"engine"`
+	Timeouts            int     `json:"timeouts"`
+	Errors              int     `json:"errors"`
+	TopResults          int     `json:"top_results"`
+	Queries             int     `json:"queries"`
+	FailureRate         float64 `json:"failure_rate"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	Excluded            bool    `json:"excluded"`
+	ExcludedUntil       *string `json:"excluded_until,omitempty"`
+	LastError           string  `json:"last_error,omitempty"`
+}
+
+// EngineStatsOutput is the structured result of the engine_stats tool
+type EngineStatsOutput struct {
+	Engines  []EngineReport `json:"engines"`
+	Total    int            `json:"total"`
+	Excluded int            `json:"excluded"`
+}
+
+// NewEngineStatsTool creates and registers a tool reporting engine reliability statistics
+func NewEngineStatsTool(server *mcp.Server, tracker *searxng.ReliabilityTracker, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "engine_stats",
+		Description: "Report per-engine reliability statistics (timeouts, errors, top result contributions) and which engines are currently excluded as flaky",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"excluded_only": {
+					Type:        "boolean",
+					Description: "Only report engines that are currently excluded",
+				},
+				"format": formatProperty(),
+			},
+		},
+		OutputSchema: outputSchema[EngineStatsOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args EngineStatsArgs) (*mcp.CallToolResult, any, error) {
+		return render.Result(args.Format, NewEngineStatsOutput(tracker.Stats(), time.Now(), args.ExcludedOnly))
+	})
+}
+
+// BuildEngineReports converts engine statistics into reports (exported for the CLI)
+func BuildEngineReports(stats []searxng.EngineStats, now time.Time, excludedOnly bool) []EngineReport {
+	reports := make([]EngineReport, 0, len(stats))
+	for _, s := range stats {
+		excluded := s.Excluded(now)
+		if excludedOnly && !excluded {
+			continue
+		}
+
+		report := EngineReport{
+			Engine:              s.Engine,
+			Timeouts:            s.Timeouts,
+			Errors:              s.Errors,
+			TopResults:          s.TopResults,
+			Queries:             s.Queries,
+			ConsecutiveFailures: s.ConsecutiveFailures,
+			Excluded:            excluded,
+			LastError:           s.LastError,
+		}
+		if s.Queries > 0 {
+			report.FailureRate = float64(s.Timeouts+s.Errors) / float64(s.Queries)
+		}
+		if excluded {
+			until := s.ExcludedUntil.Format(time.RFC3339)
+			report.ExcludedUntil = &until
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// NewEngineStatsOutput builds the structured result of engine statistics (exported for the CLI)
+func NewEngineStatsOutput(stats []searxng.EngineStats, now time.Time, excludedOnly bool) EngineStatsOutput {
+	reports := BuildEngineReports(stats, now, excludedOnly)
+
+	excluded := 0
+	for _, r := range reports {
+		if r.Excluded {
+			excluded++
+		}
+	}
+
+	return EngineStatsOutput{
+		Engines:  reports,
+		Total:    len(reports),
+		Excluded: excluded,
+	}
+}
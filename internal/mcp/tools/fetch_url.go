@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/logging"
+	"searxng-mcp/pkg/webpage"
+)
+
+// maxFetchBytes caps the per-call max_bytes argument
+const maxFetchBytes = 10 << 20
+
+// PageContent represents the readable content of a fetched page
+type PageContent struct {
+	URL           string  `json:"url"`
+	FinalURL      string  `json:"final_url"`
+	Title         string  `json:"title"`
+	Byline        *string `json:"byline,omitempty"`
+	PublishedDate *string `json:"published_date,omitempty"`
+	SiteName      *string `json:"site_name,omitempty"`
+	WordCount     int     `json:"word_count"`
+	Truncated     bool    `json:"truncated,omitempty"`
+	Content       string  `json:"content"`
+}
+
+// NewFetchURLTool creates and registers a tool that fetches a page and extracts its readable content
+func NewFetchURLTool(server *mcp.Server, fetcher *webpage.Fetcher, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "fetch_url",
+		Description: "Fetch a web page (e.g. a search result URL) and extract its main readable content as Markdown, with headings, lists, links and tables kept. Returns the final URL, title, byline, published date and word count.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"url": {
+					Type:        "string",
+					Description: "The http(s) URL of the page to fetch",
+				},
+				"max_bytes": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of bytes to download (default %d)", webpage.DefaultMaxBytes),
+					Minimum:     floatPtr(1024),
+					Maximum:     floatPtr(maxFetchBytes),
+				},
+				"timeout_seconds": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Fetch timeout in seconds (default %d)", int(webpage.DefaultTimeout.Seconds())),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(60),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"url"},
+		},
+		OutputSchema: outputSchema[PageContent](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args FetchURLArgs) (*mcp.CallToolResult, any, error) {
+		// Report the bytes read to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate url
+		if args.URL == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: url parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Apply per-call limits
+		f := *fetcher
+		if args.MaxBytes > 0 {
+			f.MaxBytes = min(int64(args.MaxBytes), maxFetchBytes)
+		}
+		if args.TimeoutSeconds > 0 {
+			f.Timeout = time.Duration(min(args.TimeoutSeconds, 60)) * time.Second
+		}
+
+		var reported int64
+		f.Progress = func(read, total int64) {
+			if read <= reported {
+				return
+			}
+			reportProgress(ctx, float64(read-reported), float64(total), fmt.Sprintf("Read %d bytes", read))
+			reported = read
+		}
+
+		// Fetch and extract page
+		page, err := fetchPage(ctx, &f, args.URL)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Fetch failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		article, err := webpage.ExtractPage(page)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Content extraction failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Format page for MCP
+		return render.Result(args.Format, newPageContent(page, article))
+	})
+}
+
+// fetchPage fetches a page, logging its latency and size to the session of the request
+func fetchPage(ctx context.Context, fetcher *webpage.Fetcher, rawURL string) (*webpage.Page, error) {
+	logger := logging.FromContext(ctx)
+	start := time.Now()
+	page, err := fetcher.Fetch(ctx, rawURL)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		logger.WarnContext(ctx, "page fetch failed", "url", rawURL, "latency_ms", latency, "error", err.Error())
+		return nil, err
+	}
+	logger.DebugContext(ctx, "page fetched", "url", rawURL, "status", page.StatusCode, "bytes", len(page.Body),
+		"truncated", page.Truncated, "latency_ms", latency)
+	return page, nil
+}
+
+// newPageContent builds the page content returned to clients
+func newPageContent(page *webpage.Page, article *webpage.Article) PageContent {
+	return PageContent{
+		URL:           page.URL,
+		FinalURL:      page.FinalURL,
+		Title:         article.Title,
+		Byline:        optionalString(article.Byline),
+		PublishedDate: optionalString(article.PublishedDate),
+		SiteName:      optionalString(article.SiteName),
+		WordCount:     article.WordCount,
+		Truncated:     page.Truncated,
+		Content:       article.Markdown,
+	}
+}
+
+// optionalString returns a pointer to s, or nil if s is empty
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
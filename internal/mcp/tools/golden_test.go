@@ -0,0 +1,236 @@
+package tools_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/scholar"
+	"searxng-mcp/pkg/searxng"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files under testdata/golden")
+
+// goldenServerURL replaces the address of the fake SearXNG server in golden files
+const goldenServerURL = "http://searxng.test"
+
+// goldenCursor matches search cursors, which hash URLs that embed the server address
+var goldenCursor = regexp.MustCompile(`[A-Za-z0-9_-]{64,}`)
+
+var _ = Describe("Structured output", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		schemas map[string]*mcp.Tool
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mux := http.NewServeMux()
+		server = httptest.NewServer(mux)
+		mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+			category := r.FormValue("categories")
+			if category == "" {
+				category = "general"
+			}
+			data, err := os.ReadFile(filepath.Join("testdata", "searxng", category+".json"))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(bytes.ReplaceAll(data, []byte("{{server}}"), []byte(server.URL)))
+		})
+		mux.HandleFunc("/pages/{name}", func(w http.ResponseWriter, r *http.Request) {
+			data, err := os.ReadFile(filepath.Join("testdata", "pages", r.PathValue("name")+".html"))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			w.Write(data)
+		})
+		mux.HandleFunc("/images/lighthouse.png", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			png.Encode(w, image.NewRGBA(image.Rect(0, 0, 64, 48)))
+		})
+
+		tracker, err := searxng.NewReliabilityTracker(searxng.ReliabilityOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var response searxng.SearchResponse
+		data, err := os.ReadFile(filepath.Join("testdata", "searxng", "general.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(data, &response)).To(Succeed())
+		tracker.Record(&response)
+
+		client := searxng.NewClient(server.URL)
+		cursors := tools.NewCursors()
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewSearchTool(s, client, cursors, tools.Renderers{})
+			tools.NewCategorySearchTool(s, client, cursors, tools.Renderers{})
+			tools.NewAdvancedSearchTool(s, client, cursors, tools.Renderers{})
+			tools.NewImageSearchTool(s, client, localImages(), cursors, tools.Renderers{})
+			tools.NewNewsDigestTool(s, client, tools.Renderers{})
+			tools.NewSearchPapersTool(s, client, scholar.Resolvers{
+				URLs:    map[string]string{"doi.org": "https://doi.org/"},
+				Default: "doi.org",
+			}, cursors, tools.Renderers{})
+			tools.NewSearchPlacesTool(s, client, tools.Renderers{})
+			tools.NewSearchVideosTool(s, client, cursors, tools.Renderers{})
+			tools.NewSearchCodeTool(s, client, cursors, tools.Renderers{})
+			tools.NewBatchSearchTool(s, client, tools.Renderers{})
+			tools.NewResearchTool(s, client, tools.Renderers{})
+			tools.NewFetchURLTool(s, localFetcher(), tools.Renderers{})
+			tools.NewSearchAndReadTool(s, client, localFetcher(), tools.Renderers{})
+			tools.NewEngineStatsTool(s, tracker, tools.Renderers{})
+			tools.NewSearchNextTool(s, cursors)
+		})
+
+		listed, err := session.ListTools(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		schemas = make(map[string]*mcp.Tool)
+		for _, tool := range listed.Tools {
+			schemas[tool.Name] = tool
+		}
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should declare an object output schema for every tool", func() {
+		Expect(schemas).To(HaveLen(15))
+		for name, tool := range schemas {
+			Expect(tool.OutputSchema).NotTo(BeNil(), name)
+			Expect(tool.OutputSchema.Type).To(Equal("object"), name)
+		}
+	})
+
+	DescribeTable("should match the golden structured content and text",
+		func(name string, args map[string]any, out tools.Output) {
+			for key, value := range args {
+				if s, ok := value.(string); ok {
+					args[key] = strings.ReplaceAll(s, "{{server}}", server.URL)
+				}
+			}
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.IsError).To(BeFalse(), "%v", result.Content)
+			Expect(result.StructuredContent).NotTo(BeNil())
+			text := result.Content[0].(*mcp.TextContent).Text
+
+			// The structured content conforms to the declared schema
+			resolved, err := schemas[name].OutputSchema.Resolve(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Validate(result.StructuredContent)).To(Succeed())
+
+			// The text is the Markdown rendering of the structured content
+			data, err := json.Marshal(result.StructuredContent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(data, out)).To(Succeed())
+			Expect(text).To(Equal(out.Markdown()))
+
+			structured := result.StructuredContent.(map[string]any)
+			if _, ok := structured["elapsed_seconds"]; ok {
+				structured["elapsed_seconds"] = 0
+			}
+			data, err = json.MarshalIndent(structured, "", "  ")
+			Expect(err).NotTo(HaveOccurred())
+
+			expectGolden(name+".json", normalizeGolden(string(data)+"\n", server.URL))
+			expectGolden(name+".md", normalizeGolden(text, server.URL))
+		},
+		Entry("search", "search", map[string]any{"query": "raft consensus"}, &tools.SearchOutput{}),
+		Entry("search_category", "search_category", map[string]any{"query": "raft consensus", "categories": []string{"general"}}, &tools.SearchOutput{}),
+		Entry("search_advanced", "search_advanced", map[string]any{"query": "raft consensus", "page": 2}, &tools.SearchOutput{}),
+		Entry("search_images", "search_images", map[string]any{"query": "lighthouse"}, &tools.ImageSearchOutput{}),
+		Entry("news_digest", "news_digest", map[string]any{"query": "rocket launch", "pages": 1}, &tools.NewsDigestOutput{}),
+		Entry("search_papers", "search_papers", map[string]any{"query": "attention is all you need", "citation_format": "bibtex", "cite": []int{1}}, &tools.PapersOutput{}),
+		Entry("search_places", "search_places", map[string]any{"query": "lighthouse museum", "near_latitude": 52.5, "near_longitude": 13.4, "sort_by_distance": true}, &tools.PlacesOutput{}),
+		Entry("search_videos", "search_videos", map[string]any{"query": "go generics tutorial"}, &tools.VideosOutput{}),
+		Entry("search_code", "search_code", map[string]any{"query": "http router"}, &tools.CodeOutput{}),
+		Entry("search_batch", "search_batch", map[string]any{"queries": []map[string]any{{"query": "raft consensus"}, {"query": "paxos"}, {"query": ""}}, "dedup": true}, &tools.BatchOutput{}),
+		Entry("research", "research", map[string]any{"question": "how does raft consensus work", "max_queries": 2}, &tools.ResearchOutput{}),
+		Entry("fetch_url", "fetch_url", map[string]any{"url": "{{server}}/pages/raft"}, &tools.PageContent{}),
+		Entry("search_and_read", "search_and_read", map[string]any{"query": "raft consensus", "pages": 3}, &tools.ReadOutput{}),
+		Entry("engine_stats", "engine_stats", map[string]any{}, &tools.EngineStatsOutput{}),
+	)
+
+	DescribeTable("should render the text content in the requested format",
+		func(name, format string, args map[string]any) {
+			args["format"] = format
+			text, isError := callToolText(ctx, session, name, args)
+			Expect(isError).To(BeFalse(), text)
+			expectGolden(name+"."+format+".txt", normalizeGolden(text, server.URL))
+		},
+		Entry("search as text", "search", "text", map[string]any{"query": "raft consensus"}),
+		Entry("search as json", "search", "json", map[string]any{"query": "raft consensus"}),
+		Entry("search as citation", "search", "citation", map[string]any{"query": "raft consensus"}),
+		Entry("search_code as text", "search_code", "text", map[string]any{"query": "http router"}),
+		Entry("search_papers as citation", "search_papers", "citation", map[string]any{"query": "attention is all you need"}),
+		Entry("research as citation", "research", "citation", map[string]any{"question": "how does raft consensus work", "max_queries": 2}),
+	)
+
+	It("should reject an unknown format", func() {
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "search",
+			Arguments: map[string]any{"query": "raft consensus", "format": "yaml"},
+		})
+		Expect(err).To(MatchError(ContainSubstring("format")))
+	})
+
+	It("should use the server default format", func() {
+		defaultSession := connectTools(ctx, func(s *mcp.Server) {
+			tools.NewSearchTool(s, searxng.NewClient(server.URL), nil, tools.Renderers{Default: tools.FormatCitation})
+		})
+		defer defaultSession.Close()
+
+		text, isError := callToolText(ctx, defaultSession, "search", map[string]any{"query": "raft consensus"})
+		Expect(isError).To(BeFalse(), text)
+		Expect(normalizeGolden(text, server.URL)).To(Equal(readGolden("search.citation.txt")))
+
+		text, _ = callToolText(ctx, defaultSession, "search", map[string]any{"query": "raft consensus", "format": "markdown"})
+		Expect(normalizeGolden(text, server.URL)).To(Equal(readGolden("search.md")))
+	})
+})
+
+// normalizeGolden replaces the fake server address and search cursors with stable placeholders
+func normalizeGolden(content, serverURL string) string {
+	return goldenCursor.ReplaceAllString(strings.ReplaceAll(content, serverURL, goldenServerURL), "{{cursor}}")
+}
+
+// readGolden returns the content of a golden file
+func readGolden(name string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", name))
+	Expect(err).NotTo(HaveOccurred())
+	return string(data)
+}
+
+// expectGolden compares content with a golden file, rewriting the file when -update is set
+func expectGolden(name, content string) {
+	path := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+		return
+	}
+	want, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred(), "run go test with -update to create %s", path)
+	Expect(content).To(Equal(string(want)), path)
+}
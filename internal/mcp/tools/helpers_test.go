@@ -0,0 +1,67 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/thumbnail"
+	"searxng-mcp/pkg/webpage"
+)
+
+// connectTools registers tools on a fresh MCP server and returns a connected client session
+func connectTools(ctx context.Context, register func(server *mcp.Server)) *mcp.ClientSession {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	register(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	Expect(err).NotTo(HaveOccurred())
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	Expect(err).NotTo(HaveOccurred())
+
+	return session
+}
+
+// callToolText calls a tool and returns the text of its first content block
+func callToolText(ctx context.Context, session *mcp.ClientSession, name string, args map[string]any) (string, bool) {
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(result.Content).NotTo(BeEmpty())
+
+	text, ok := result.Content[0].(*mcp.TextContent)
+	Expect(ok).To(BeTrue())
+	return text.Text, result.IsError
+}
+
+// callToolJSON calls a tool and decodes its structured output
+func callToolJSON(ctx context.Context, session *mcp.ClientSession, name string, args map[string]any) map[string]any {
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(result.IsError).To(BeFalse(), "%v", result.Content)
+
+	data, err := json.Marshal(result.StructuredContent)
+	Expect(err).NotTo(HaveOccurred())
+
+	var out map[string]any
+	Expect(json.Unmarshal(data, &out)).To(Succeed())
+	return out
+}
+
+// localFetcher returns a page fetcher allowed to reach the local test servers
+func localFetcher() *webpage.Fetcher {
+	fetcher := webpage.NewFetcher()
+	fetcher.AllowPrivateNetworks = true
+	return fetcher
+}
+
+// localImages returns an image fetcher allowed to reach the local test servers
+func localImages() *thumbnail.Fetcher {
+	fetcher := thumbnail.NewFetcher()
+	fetcher.AllowPrivateNetworks = true
+	return fetcher
+}
@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/thumbnail"
+)
+
+// Defaults for the image search tool
+const (
+	defaultMaxImages        = 6
+	maxImages               = 20
+	defaultImageTotalBytes  = 1536 << 10 // 1.5 MiB
+	maxImageTotalBytes      = 5 << 20
+	defaultImageDimension   = 512
+	maxImageDimension       = 1024
+	imageFetchConcurrency   = 6
+	imageCandidatesPerImage = 2
+)
+
+// ImageSearchOutput is the structured result of the search_images tool
+type ImageSearchOutput struct {
+	Query    string        `json:"query"`
+	Total    int           `json:"total"`
+	Images   []ImageResult `json:"images"`
+	Included int           `json:"included"`
+	// NextCursor continues the search with search_next after the results returned
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ImageResult describes an image search result and whether its image was included
+type ImageResult struct {
+	Index      int     `json:"index"`
+	Title      string  `json:"title"`
+	PageURL    string  `json:"page_url"`
+	ImageURL   string  `json:"image_url"`
+	Thumbnail  *string `json:"thumbnail_url,omitempty"`
+	Resolution *string `json:"resolution,omitempty"`
+	Format     *string `json:"format,omitempty"`
+	Source     *string `json:"source,omitempty"`
+	Author     *string `json:"author,omitempty"`
+	License    *string `json:"license,omitempty"`
+	LicenseURL *string `json:"license_url,omitempty"`
+	Engine     string  `json:"engine"`
+	// ContentIndex is the position of the image in the returned content, when included
+	ContentIndex *int    `json:"content_index,omitempty"`
+	MIMEType     *string `json:"mime_type,omitempty"`
+	Width        int     `json:"width,omitempty"`
+	Height       int     `json:"height,omitempty"`
+	Bytes        int     `json:"bytes,omitempty"`
+	Error        *string `json:"error,omitempty"`
+}
+
+// NewImageSearchTool creates and registers a tool that returns image search results as image content
+func NewImageSearchTool(server *mcp.Server, client searxng.Client, fetcher *thumbnail.Fetcher, cursors *Cursors, render Renderers) {
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	addPagedTool(server, cursors, &mcp.Tool{
+		Name:        "search_images",
+		Description: "Search for images with SearXNG and return the images themselves, downscaled to fit a size budget, together with their source page, resolution, format, source, author and license metadata.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The image search query to execute",
+				},
+				"max_images": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of images to return (default %d)", defaultMaxImages),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxImages),
+				},
+				"max_total_bytes": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Byte budget shared by all returned images (default %d)", defaultImageTotalBytes),
+					Minimum:     floatPtr(16 << 10),
+					Maximum:     floatPtr(maxImageTotalBytes),
+				},
+				"max_dimension": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum width or height of returned images in pixels (default %d)", defaultImageDimension),
+					Minimum:     floatPtr(64),
+					Maximum:     floatPtr(maxImageDimension),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[ImageSearchOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ImageSearchArgs) (*mcp.CallToolResult, any, error) {
+		// Report the images fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			PageNo:     1,
+			Language:   args.Language,
+			Categories: []searxng.Category{searxng.CategoryImages},
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Continue from the cursor of an earlier call
+		page, seen := resumed(ctx, opts.PageNo)
+		opts.PageNo = page
+
+		// Perform search
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		count := clampArg(ctx, "max_images", args.MaxImages, defaultMaxImages, maxImages)
+		totalBytes := clampArg(ctx, "max_total_bytes", args.MaxTotalBytes, defaultImageTotalBytes, maxImageTotalBytes)
+		dimension := clampArg(ctx, "max_dimension", args.MaxDimension, defaultImageDimension, maxImageDimension)
+
+		// Fetch more candidates than needed so broken images can be replaced
+		available := imageResults(seen.unseen(response.Results), len(response.Results))
+		candidates := available[:min(len(available), count*imageCandidatesPerImage)]
+		images, imageContent := fetchImages(ctx, fetcher, candidates, count, totalBytes, dimension)
+
+		// Only the candidates listed in the output count as returned: continue on
+		// this page while others were left out, then on the next one
+		out := newImageSearchOutput(response, images, len(imageContent))
+		if len(response.Results) > 0 {
+			returned := candidates[:len(images)]
+			out.NextCursor = cursorAfter("search_images", args, nextPage(opts.PageNo, len(returned), len(available)), seen, returned)
+		}
+
+		// Format metadata first, followed by the images in the same order
+		return render.Result(args.Format, out, imageContent...)
+	})
+}
+
+// imageResults returns up to n results that carry an image URL, skipping duplicates
+func imageResults(results []searxng.SearchResult, n int) []searxng.SearchResult {
+	seen := make(map[string]bool)
+	var images []searxng.SearchResult
+	for _, r := range results {
+		if len(images) >= n {
+			break
+		}
+		src := firstNonEmptyString(r.ImgSrc, r.ThumbnailSrc, r.Thumbnail)
+		if src == "" || seen[src] {
+			continue
+		}
+		seen[src] = true
+		images = append(images, r)
+	}
+	return images
+}
+
+// fetchImages downloads candidate images concurrently and keeps, in rank order,
+// the first count images that fit within the shared byte budget
+func fetchImages(ctx context.Context, fetcher *thumbnail.Fetcher, candidates []searxng.SearchResult, count, totalBytes, dimension int) ([]ImageResult, []mcp.Content) {
+	fetched := make([]*thumbnail.Image, len(candidates))
+	errs := make([]error, len(candidates))
+	perImage := totalBytes / max(1, min(count, len(candidates)))
+
+	forEachBounded(ctx, len(candidates), imageFetchConcurrency, func(ctx context.Context, i int) {
+		// Prefer the engine's thumbnail: it is smaller and usually already sized for previews
+		for _, src := range []string{candidates[i].ThumbnailSrc, candidates[i].Thumbnail, candidates[i].ImgSrc} {
+			if src == "" {
+				continue
+			}
+			fetched[i], errs[i] = fetcher.Fetch(ctx, src, dimension, perImage)
+			if errs[i] == nil {
+				break
+			}
+		}
+		reportProgress(ctx, 1, float64(len(candidates)), fmt.Sprintf("Fetched image %d of %d", i+1, len(candidates)))
+	})
+
+	results := []ImageResult{}
+	var content []mcp.Content
+	remaining := totalBytes
+	for i, r := range candidates {
+		if len(content) >= count {
+			break
+		}
+		result := newImageResult(i+1, r)
+		switch {
+		case fetched[i] == nil && errs[i] != nil:
+			result.Error = optionalString(errs[i].Error())
+		case fetched[i] == nil:
+			result.Error = optionalString(fmt.Sprintf("not fetched: %v", ctx.Err()))
+		case len(fetched[i].Data) > remaining:
+			result.Error = optionalString("skipped: total byte budget exhausted")
+		default:
+			img := fetched[i]
+			remaining -= len(img.Data)
+			position := len(content) + 1
+			result.ContentIndex = &position
+			result.MIMEType = optionalString(img.MIMEType)
+			result.Width = img.Width
+			result.Height = img.Height
+			result.Bytes = len(img.Data)
+			content = append(content, &mcp.ImageContent{Data: img.Data, MIMEType: img.MIMEType})
+		}
+		results = append(results, result)
+	}
+
+	return results, content
+}
+
+// newImageResult builds the metadata of an image search result
+func newImageResult(index int, r searxng.SearchResult) ImageResult {
+	return ImageResult{
+		Index:      index,
+		Title:      r.Title,
+		PageURL:    r.URL,
+		ImageURL:   firstNonEmptyString(r.ImgSrc, r.ThumbnailSrc, r.Thumbnail),
+		Thumbnail:  optionalString(firstNonEmptyString(r.ThumbnailSrc, r.Thumbnail)),
+		Resolution: optionalString(string(r.Resolution)),
+		Format:     optionalString(string(r.ImgFormat)),
+		Source:     optionalString(string(r.Source)),
+		Author:     optionalString(string(r.Author)),
+		License:    optionalString(firstNonEmptyString(string(r.License), r.LicenseName)),
+		LicenseURL: optionalString(r.LicenseURL),
+		Engine:     r.Engine,
+	}
+}
+
+// newImageSearchOutput builds the structured result of an image search
+func newImageSearchOutput(response *searxng.SearchResponse, images []ImageResult, included int) ImageSearchOutput {
+	return ImageSearchOutput{
+		Query:    response.Query,
+		Total:    response.NumberOfResults,
+		Images:   images,
+		Included: included,
+	}
+}
+
+// firstNonEmptyString returns the first non-empty string
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
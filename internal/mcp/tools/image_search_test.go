@@ -0,0 +1,121 @@
+package tools_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Image Search", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var buf bytes.Buffer
+		Expect(png.Encode(&buf, image.NewGray(image.Rect(0, 0, 800, 600)))).To(Succeed())
+		pngData := buf.Bytes()
+
+		mux := http.NewServeMux()
+		server = httptest.NewServer(mux)
+		mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.FormValue("categories")).To(Equal("images"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{
+				"query": "lighthouse",
+				"number_of_results": 3,
+				"results": [
+					{"url": "https://example.com/a", "title": "Lighthouse A", "img_src": "%[1]s/a.png",
+					 "thumbnail_src": "%[1]s/a-thumb.png", "resolution": "800 x 600", "img_format": "png",
+					 "source": "Wikimedia Commons", "author": "Jane Doe", "license": "CC BY-SA 4.0",
+					 "license_url": "https://creativecommons.org/licenses/by-sa/4.0/", "engine": "wikicommons.images"},
+					{"url": "https://example.com/b", "title": "Lighthouse B", "img_src": "%[1]s/broken.png", "engine": "bing images"},
+					{"url": "https://example.com/c", "title": "Lighthouse C", "img_src": "%[1]s/a.png", "engine": "duckduckgo images"},
+					{"url": "https://example.com/d", "title": "No Image", "engine": "bing images"}
+				]
+			}`, server.URL)
+		})
+		mux.HandleFunc("/a-thumb.png", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(pngData)
+		})
+		mux.HandleFunc("/a.png", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(pngData)
+		})
+		mux.HandleFunc("/broken.png", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>not an image</html>"))
+		})
+
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewImageSearchTool(s, searxng.NewClient(server.URL), localImages(), nil, tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should return downscaled images with metadata", func() {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "search_images",
+			Arguments: map[string]any{"query": "lighthouse", "max_dimension": 128},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsError).To(BeFalse())
+		Expect(result.Content).To(HaveLen(2))
+
+		data, err := json.Marshal(result.StructuredContent)
+		Expect(err).NotTo(HaveOccurred())
+		var out tools.ImageSearchOutput
+		Expect(json.Unmarshal(data, &out)).To(Succeed())
+		Expect(out.Included).To(Equal(1))
+		Expect(out.Images).To(HaveLen(2))
+		Expect(*out.Images[0].Resolution).To(Equal("800 x 600"))
+		Expect(*out.Images[0].Author).To(Equal("Jane Doe"))
+		Expect(*out.Images[0].License).To(Equal("CC BY-SA 4.0"))
+		Expect(*out.Images[0].LicenseURL).To(Equal("https://creativecommons.org/licenses/by-sa/4.0/"))
+		Expect(*out.Images[0].ContentIndex).To(Equal(1))
+		Expect(*out.Images[1].Error).To(ContainSubstring("not an image"))
+
+		text := result.Content[0].(*mcp.TextContent).Text
+		Expect(text).To(ContainSubstring("1 of 2 images included"))
+		Expect(text).To(ContainSubstring("license CC BY-SA 4.0"))
+		Expect(text).NotTo(ContainSubstring("No Image"))
+
+		img, ok := result.Content[1].(*mcp.ImageContent)
+		Expect(ok).To(BeTrue())
+		Expect(img.MIMEType).To(Equal("image/jpeg"))
+		Expect(len(img.Data)).To(BeNumerically(">", 0))
+	})
+
+	It("should respect the image count cap", func() {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "search_images",
+			Arguments: map[string]any{"query": "lighthouse", "max_images": 1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Content).To(HaveLen(2))
+	})
+
+	It("should require a query", func() {
+		text, isError := callToolText(ctx, session, "search_images", map[string]any{"query": ""})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("query parameter is required"))
+	})
+})
@@ -0,0 +1,445 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"searxng-mcp/pkg/history"
+)
+
+// Summary describes the search page
+func (o SearchOutput) Summary() string {
+	summary := fmt.Sprintf("%q: %s, page %d", o.Query, strings.ToLower(resultCount(len(o.Results), o.Total)), o.Pagination.Page)
+	if o.Pagination.LastPage != nil {
+		summary += fmt.Sprintf("-%d", *o.Pagination.LastPage)
+	}
+	if o.Omitted != nil {
+		summary += "; " + strings.ToLower(o.Omitted.describe())
+	}
+	return summary
+}
+
+// Items lists the results followed by the infoboxes, so item numbers match result ranks
+func (o SearchOutput) Items() []Item {
+	var items []Item
+	for _, r := range o.Results {
+		items = append(items, simplifiedItem(r))
+	}
+	for _, infobox := range o.Infoboxes {
+		item := Item{Title: infobox.Title, Text: infobox.Content}
+		if len(infobox.Links) > 0 {
+			item.URL = infobox.Links[0].URL
+		}
+		for _, a := range infobox.Attributes {
+			item.Details = append(item.Details, a.Label+": "+a.Value)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Summary describes the batch
+func (o BatchOutput) Summary() string {
+	return fmt.Sprintf("Batch of %d queries, %d failed", o.Queries, o.Failed)
+}
+
+// Items lists the results of every query, noting the query each belongs to
+func (o BatchOutput) Items() []Item {
+	var items []Item
+	for _, q := range o.Results {
+		if q.Error != nil {
+			items = append(items, Item{Title: firstNonEmptyString(q.Query, "(empty query)"), Details: []string{"error: " + *q.Error}})
+			continue
+		}
+		for _, r := range q.Results {
+			item := simplifiedItem(r)
+			item.Details = append([]string{"query " + strconv.Itoa(q.Index+1)}, item.Details...)
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Summary describes the research ledger
+func (o ResearchOutput) Summary() string {
+	return fmt.Sprintf("Research %q: %d sources from %d queries, stopped: %s", o.Question, o.TotalSources, len(o.Queries), o.StopReason)
+}
+
+// Items lists the sources in ledger order, so item numbers match source ids
+func (o ResearchOutput) Items() []Item {
+	items := make([]Item, len(o.Sources))
+	for i, s := range o.Sources {
+		items[i] = Item{Title: s.Title, URL: s.URL, Date: s.PublishedDate, Text: strings.Join(s.Snippets, " … ")}
+	}
+	return items
+}
+
+// Summary describes the page
+func (o PageContent) Summary() string {
+	summary := fmt.Sprintf("%s (%d words", firstNonEmptyString(o.Title, o.FinalURL), o.WordCount)
+	if o.Truncated {
+		summary += ", truncated"
+	}
+	return summary + ")"
+}
+
+// Items lists the page with its content
+func (o PageContent) Items() []Item {
+	item := Item{Title: o.Title, URL: o.FinalURL, Text: o.Content}
+	if o.Byline != nil {
+		item.Details = append(item.Details, *o.Byline)
+	}
+	if o.PublishedDate != nil {
+		item.Date = *o.PublishedDate
+	}
+	return []Item{item}
+}
+
+// Summary describes the read sources
+func (o ReadOutput) Summary() string {
+	return fmt.Sprintf("%q: read %d pages, %d failed", o.Query, o.Read, o.Failed)
+}
+
+// Items lists the sources with their passages
+func (o ReadOutput) Items() []Item {
+	items := make([]Item, len(o.Sources))
+	for i, s := range o.Sources {
+		items[i] = simplifiedItem(s.SimplifiedResult)
+		if s.Error != nil {
+			items[i].Details = append(items[i].Details, "error: "+*s.Error)
+			items[i].Text = ""
+			continue
+		}
+		if len(s.Passages) > 0 {
+			passages := make([]string, len(s.Passages))
+			for j, p := range s.Passages {
+				passages[j] = p.Text
+			}
+			items[i].Text = strings.Join(passages, "\n")
+		}
+	}
+	return items
+}
+
+// Summary describes the engine statistics
+func (o EngineStatsOutput) Summary() string {
+	return fmt.Sprintf("%d engines, %d excluded", o.Total, o.Excluded)
+}
+
+// Items lists one entry per engine
+func (o EngineStatsOutput) Items() []Item {
+	items := make([]Item, len(o.Engines))
+	for i, e := range o.Engines {
+		items[i] = Item{
+			Title: e.Engine,
+			Details: []string{
+				fmt.Sprintf("%d queries", e.Queries),
+				fmt.Sprintf("%d timeouts", e.Timeouts),
+				fmt.Sprintf("%d errors", e.Errors),
+				fmt.Sprintf("%d top results", e.TopResults),
+				fmt.Sprintf("%.0f%% failures", e.FailureRate*100),
+			},
+		}
+		if e.Excluded && e.ExcludedUntil != nil {
+			items[i].Details = append(items[i].Details, "excluded until "+*e.ExcludedUntil)
+		}
+	}
+	return items
+}
+
+// Summary describes the image results
+func (o ImageSearchOutput) Summary() string {
+	return fmt.Sprintf("%q: %d of %d images included", o.Query, o.Included, len(o.Images))
+}
+
+// Items lists the image results with their source pages
+func (o ImageSearchOutput) Items() []Item {
+	items := make([]Item, len(o.Images))
+	for i, img := range o.Images {
+		items[i] = Item{Title: img.Title, URL: img.PageURL}
+		if img.Author != nil {
+			items[i].Details = append(items[i].Details, "by "+*img.Author)
+		}
+		if img.License != nil {
+			items[i].Details = append(items[i].Details, "license "+*img.License)
+		}
+		if img.ContentIndex != nil {
+			items[i].Details = append(items[i].Details, "image "+strconv.Itoa(*img.ContentIndex))
+		} else if img.Error != nil {
+			items[i].Details = append(items[i].Details, "not included: "+*img.Error)
+		}
+	}
+	return items
+}
+
+// Summary describes the news digest
+func (o NewsDigestOutput) Summary() string {
+	return fmt.Sprintf("%q: %d stories from %d articles", o.Query, o.TotalStories, o.Articles)
+}
+
+// Items lists one entry per story, linking its earliest article
+func (o NewsDigestOutput) Items() []Item {
+	items := make([]Item, len(o.Stories))
+	for i, s := range o.Stories {
+		items[i] = Item{Title: s.Headline, Text: s.Snippet, Details: []string{plural(len(s.Outlets), "outlet")}}
+		if len(s.Articles) > 0 {
+			items[i].URL = s.Articles[0].URL
+		}
+		if s.Latest != nil {
+			items[i].Date = s.Latest.Format(time.DateOnly)
+		}
+	}
+	return items
+}
+
+// Summary describes the paper results
+func (o PapersOutput) Summary() string {
+	return fmt.Sprintf("%q: %s", o.Query, strings.ToLower(resultCount(len(o.Papers), o.Total)))
+}
+
+// Items lists the papers with authors, venue and DOI
+func (o PapersOutput) Items() []Item {
+	items := make([]Item, len(o.Papers))
+	for i, p := range o.Papers {
+		items[i] = Item{Title: p.Title, URL: p.URL, Text: p.Abstract}
+		if len(p.Authors) > 0 {
+			items[i].Details = append(items[i].Details, strings.Join(p.Authors, ", "))
+		}
+		if p.Journal != "" {
+			items[i].Details = append(items[i].Details, p.Journal)
+		}
+		if p.DOI != "" {
+			items[i].Details = append(items[i].Details, "doi:"+p.DOI)
+		}
+		if p.Year > 0 {
+			items[i].Date = strconv.Itoa(p.Year)
+		}
+	}
+	return items
+}
+
+// Summary describes the places
+func (o PlacesOutput) Summary() string {
+	return plural(len(o.Features), "place") + " found"
+}
+
+// Items lists the places with address and distance
+func (o PlacesOutput) Items() []Item {
+	items := make([]Item, len(o.Features))
+	for i, f := range o.Features {
+		p := f.Properties
+		pos := f.Position()
+		items[i] = Item{Title: p.Name, URL: p.URL, Details: []string{fmt.Sprintf("%.6f, %.6f", pos.Lat, pos.Lon)}}
+		if p.Address != "" {
+			items[i].Details = append([]string{p.Address}, items[i].Details...)
+		}
+		if p.DistanceKm != nil {
+			items[i].Details = append(items[i].Details, fmt.Sprintf("%.3f km away", *p.DistanceKm))
+		}
+	}
+	return items
+}
+
+// Summary describes the video results
+func (o VideosOutput) Summary() string {
+	summary := fmt.Sprintf("%q: %s", o.Query, plural(len(o.Videos), "video"))
+	if o.Filtered > 0 {
+		summary += fmt.Sprintf(", %d filtered out", o.Filtered)
+	}
+	return summary
+}
+
+// Items lists the videos with platform, duration and channel
+func (o VideosOutput) Items() []Item {
+	items := make([]Item, len(o.Videos))
+	for i, v := range o.Videos {
+		items[i] = Item{Title: v.Title, URL: v.URL, Text: v.Description, Details: []string{v.Platform}}
+		if v.Duration != "" {
+			items[i].Details = append(items[i].Details, v.Duration)
+		}
+		if v.Channel != "" {
+			items[i].Details = append(items[i].Details, v.Channel)
+		}
+		if v.PublishedDate != nil {
+			items[i].Date = v.PublishedDate.Format(time.DateOnly)
+		}
+	}
+	return items
+}
+
+// Summary describes the developer results
+func (o CodeOutput) Summary() string {
+	return fmt.Sprintf("%q: %s in %d groups", o.Query, plural(o.Count, "result"), len(o.Groups))
+}
+
+// Items lists the results group by group, noting their source type
+func (o CodeOutput) Items() []Item {
+	var items []Item
+	for _, g := range o.Groups {
+		for _, r := range g.Results {
+			item := Item{Title: r.Title, URL: r.URL, Text: r.Summary, Details: []string{string(g.Kind), r.Engine}}
+			if r.Version != "" {
+				item.Details = append(item.Details, r.Version)
+			}
+			if r.UpdatedDate != nil {
+				item.Date = r.UpdatedDate.Format(time.DateOnly)
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Summary describes the watch
+func (o WatchOutput) Summary() string {
+	if o.Status == "removed" {
+		return fmt.Sprintf("Stopped watching %q (%s): %s found", o.Query, o.URI, plural(len(o.Results), "new result"))
+	}
+	return fmt.Sprintf("Watching %q every %d minutes as %s: %s", o.Query, o.IntervalMinutes, o.URI, plural(len(o.Results), "current result"))
+}
+
+// Items lists the current or new results of the watch
+func (o WatchOutput) Items() []Item {
+	items := make([]Item, len(o.Results))
+	for i, r := range o.Results {
+		items[i] = Item{Title: r.Title, URL: r.URL, Date: r.PublishedDate, Text: r.Content}
+	}
+	return items
+}
+
+// Summary counts the passages of each stance
+func (o VerifyClaimOutput) Summary() string {
+	return fmt.Sprintf("%q: %d supporting, %d contradicting, %d unrelated passages from %s",
+		o.Claim, o.Supporting, o.Contradicting, o.Unrelated, plural(len(o.Sources), "source"))
+}
+
+// Items lists the rows of the evidence table
+func (o VerifyClaimOutput) Items() []Item {
+	items := make([]Item, len(o.Evidence))
+	for i, e := range o.Evidence {
+		items[i] = Item{Title: e.Title, URL: e.URL, Details: append([]string{string(e.Stance)}, e.Cues...), Text: e.Passage}
+		if e.Date != nil {
+			items[i].Date = *e.Date
+		}
+	}
+	return items
+}
+
+// Summary describes the variants and their overlap
+func (o CompareOutput) Summary() string {
+	summary := fmt.Sprintf("%q: %d variants, %s in every variant", o.Query, len(o.Variants), plural(len(o.Common), "result"))
+	if len(o.Errors) > 0 {
+		summary += fmt.Sprintf(", %d failed", len(o.Errors))
+	}
+	return summary
+}
+
+// Items lists the results every variant returned, then the results unique to each variant
+func (o CompareOutput) Items() []Item {
+	var items []Item
+	for _, r := range o.Common {
+		ranks := make([]string, len(r.Ranks))
+		for i, rank := range r.Ranks {
+			ranks[i] = strconv.Itoa(rank)
+		}
+		items = append(items, Item{Title: r.Title, URL: r.URL, Details: []string{"in every variant", "ranks " + strings.Join(ranks, ", ")}})
+	}
+	for _, v := range o.Variants {
+		for _, r := range v.Unique {
+			items = append(items, Item{Title: r.Title, URL: r.URL, Details: []string{"only in " + v.Label, "rank " + strconv.Itoa(r.Rank)}})
+		}
+	}
+	return items
+}
+
+// Summary counts the searches listed
+func (o SearchHistoryOutput) Summary() string {
+	if o.Total > len(o.Entries) {
+		return fmt.Sprintf("Search history: showing the newest %d of %d matching searches", len(o.Entries), o.Total)
+	}
+	return fmt.Sprintf("Search history: %d matching searches", o.Total)
+}
+
+// Items lists the recorded searches
+func (o SearchHistoryOutput) Items() []Item {
+	items := make([]Item, len(o.Entries))
+	for i, e := range o.Entries {
+		items[i] = Item{Title: e.Query, Date: e.Time.Local().Format(time.DateTime), Details: historyDetails(e)}
+	}
+	return items
+}
+
+// Summary counts the changes since the search was last run
+func (o RerunOutput) Summary() string {
+	return fmt.Sprintf("%q since %s: %d added, %d removed, %d moved, %d unchanged",
+		o.Query, o.PreviousTime.Local().Format(time.DateTime), len(o.Added), len(o.Removed), len(o.Moved), o.Unchanged)
+}
+
+// Items lists the current results, marking new and moved ones, then the removed results
+func (o RerunOutput) Items() []Item {
+	added := make(map[string]bool, len(o.Added))
+	for _, r := range o.Added {
+		added[r.URL] = true
+	}
+	moved := make(map[string]int, len(o.Moved))
+	for _, c := range o.Moved {
+		moved[c.URL] = c.From
+	}
+
+	items := make([]Item, 0, len(o.Results)+len(o.Removed))
+	for _, r := range o.Results {
+		item := simplifiedItem(r)
+		if added[r.URL] {
+			item.Details = append(item.Details, "new")
+		} else if from, ok := moved[r.URL]; ok {
+			item.Details = append(item.Details, "was rank "+strconv.Itoa(from))
+		}
+		items = append(items, item)
+	}
+	for _, r := range o.Removed {
+		items = append(items, Item{Title: r.Title, URL: r.URL, Details: []string{"removed", "was rank " + strconv.Itoa(r.Rank)}})
+	}
+	return items
+}
+
+// Summary counts the searches removed and kept
+func (o PurgeHistoryOutput) Summary() string {
+	return fmt.Sprintf("Search history: %d searches removed, %d left", o.Removed, o.Remaining)
+}
+
+// Items lists nothing: a purge has no entries to show
+func (o PurgeHistoryOutput) Items() []Item {
+	return nil
+}
+
+// historyDetails describes the parameters of a recorded search
+func historyDetails(e history.Entry) []string {
+	details := []string{"#" + strconv.FormatInt(e.ID, 10)}
+	if e.Tool != "" {
+		details = append(details, e.Tool)
+	}
+	if len(e.Categories) > 0 {
+		details = append(details, strings.Join(e.Categories, ", "))
+	}
+	if e.Language != "" {
+		details = append(details, "language "+e.Language)
+	}
+	if e.TimeRange != "" {
+		details = append(details, "time_range "+e.TimeRange)
+	}
+	if e.Page > 1 {
+		details = append(details, "page "+strconv.Itoa(e.Page))
+	}
+	return append(details, plural(len(e.Results), "result"))
+}
+
+// simplifiedItem converts a simplified search result to an item
+func simplifiedItem(r SimplifiedResult) Item {
+	item := Item{Title: r.Title, URL: r.URL, Text: r.Summary}
+	if r.Date != nil {
+		item.Date = *r.Date
+	}
+	return item
+}
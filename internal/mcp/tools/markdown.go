@@ -0,0 +1,649 @@
+package tools
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Markdown renders search results as a numbered list
+func (o SearchOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Search results for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "%s, page %d.\n", resultCount(len(o.Results), o.Total), o.Pagination.Page)
+	if len(o.Corrections) > 0 {
+		fmt.Fprintf(&b, "\nDid you mean: %s\n", strings.Join(o.Corrections, ", "))
+	}
+
+	for _, infobox := range o.Infoboxes {
+		fmt.Fprintf(&b, "\n### %s\n\n", infobox.Title)
+		if infobox.Content != "" {
+			fmt.Fprintf(&b, "%s\n\n", infobox.Content)
+		}
+		for _, a := range infobox.Attributes {
+			fmt.Fprintf(&b, "- %s: %s\n", a.Label, a.Value)
+		}
+		for _, l := range infobox.Links {
+			fmt.Fprintf(&b, "- %s\n", markdownLink(l.Title, l.URL))
+		}
+	}
+
+	if len(o.Results) == 0 {
+		b.WriteString("\nNo results found.\n")
+	} else {
+		b.WriteString("\n")
+	}
+	for _, r := range o.Results {
+		writeSimplifiedResult(&b, r)
+	}
+	if o.Omitted != nil {
+		fmt.Fprintf(&b, "\n_%s; raise limit or max_tokens for more._\n", o.Omitted.describe())
+	}
+
+	if len(o.Suggestions) > 0 {
+		fmt.Fprintf(&b, "\nRelated searches: %s\n", strings.Join(o.Suggestions, ", "))
+	}
+	if o.Pagination.LastPage != nil {
+		fmt.Fprintf(&b, "\nFilled from pages %d-%d.\n", o.Pagination.Page, *o.Pagination.LastPage)
+	}
+	if o.Pagination.NextPage != nil {
+		fmt.Fprintf(&b, "\nMore results on page %d.\n", *o.Pagination.NextPage)
+	}
+	writeNextCursor(&b, o.Pagination.NextCursor)
+	return b.String()
+}
+
+// Markdown renders batch results one section per query
+func (o BatchOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Batch of %d queries (%d failed)\n", o.Queries, o.Failed)
+
+	for _, q := range o.Results {
+		fmt.Fprintf(&b, "\n### %d. %s\n\n", q.Index+1, firstNonEmptyString(q.Query, "(empty query)"))
+		if q.Error != nil {
+			fmt.Fprintf(&b, "Error: %s\n", *q.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "%s", resultCount(len(q.Results), q.Total))
+		if q.Omitted > 0 {
+			fmt.Fprintf(&b, ", %d duplicates omitted", q.Omitted)
+		}
+		b.WriteString(".\n")
+		if len(q.Results) > 0 {
+			b.WriteString("\n")
+		}
+		for _, r := range q.Results {
+			writeSimplifiedResult(&b, r)
+		}
+	}
+
+	if len(o.SharedURLs) > 0 {
+		b.WriteString("\n### Shared URLs\n\n")
+		for _, u := range o.SharedURLs {
+			queries := make([]string, len(u.Queries))
+			for i, q := range u.Queries {
+				queries[i] = strconv.Itoa(q + 1)
+			}
+			fmt.Fprintf(&b, "- %s (queries %s)\n", markdownLink(u.Title, u.URL), strings.Join(queries, ", "))
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the research ledger as a query log followed by numbered sources
+func (o ResearchOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Research: %s\n\n", o.Question)
+	fmt.Fprintf(&b, "%d sources from %d queries, stopped: %s.\n", o.TotalSources, len(o.Queries), o.StopReason)
+
+	if len(o.Queries) > 0 {
+		b.WriteString("\n### Queries\n\n")
+	}
+	for _, q := range o.Queries {
+		if q.Error != "" {
+			fmt.Fprintf(&b, "- %s (%s): error: %s\n", q.Query, q.Origin, q.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s (%s): %d results, %d new sources\n", q.Query, q.Origin, q.Results, q.NewSources)
+	}
+
+	if len(o.Sources) > 0 {
+		b.WriteString("\n### Sources\n")
+	}
+	for _, s := range o.Sources {
+		fmt.Fprintf(&b, "\n[%d] %s", s.ID, markdownLink(s.Title, s.URL))
+		if s.PublishedDate != "" {
+			fmt.Fprintf(&b, " (%s)", s.PublishedDate)
+		}
+		b.WriteString("\n")
+		for _, snippet := range s.Snippets {
+			fmt.Fprintf(&b, "%s\n", quote(snippet))
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the page as its extracted Markdown content under a metadata header
+func (o PageContent) Markdown() string {
+	var b strings.Builder
+	var meta []string
+	if o.Byline != nil {
+		meta = append(meta, "By "+*o.Byline)
+	}
+	if o.SiteName != nil {
+		meta = append(meta, *o.SiteName)
+	}
+	if o.PublishedDate != nil {
+		meta = append(meta, *o.PublishedDate)
+	}
+	meta = append(meta, fmt.Sprintf("%d words", o.WordCount))
+	if o.Truncated {
+		meta = append(meta, "truncated")
+	}
+	fmt.Fprintf(&b, "Source: %s (%s)\n\n", o.FinalURL, strings.Join(meta, ", "))
+	content := strings.TrimSpace(o.Content)
+	// Extracted content usually opens with the page title as its heading
+	if !strings.HasPrefix(content, "# ") {
+		fmt.Fprintf(&b, "# %s\n\n", firstNonEmptyString(o.Title, o.FinalURL))
+	}
+	b.WriteString(content)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Markdown renders each read source with its relevant passages
+func (o ReadOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Sources for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "Read %d pages, %d failed.\n", o.Read, o.Failed)
+
+	for _, s := range o.Sources {
+		fmt.Fprintf(&b, "\n### [%d] %s\n\n", s.Rank, markdownLink(s.Title, s.URL))
+		if s.Error != nil {
+			fmt.Fprintf(&b, "Error: %s\n", *s.Error)
+			continue
+		}
+		var passages []string
+		for _, p := range s.Passages {
+			passages = append(passages, quote(p.Text))
+		}
+		if len(passages) == 0 && s.Summary != "" {
+			passages = append(passages, quote(s.Summary))
+		}
+		for i, p := range passages {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s\n", p)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders engine statistics as a table
+func (o EngineStatsOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Engine statistics\n\n%d engines, %d excluded.\n", o.Total, o.Excluded)
+	if len(o.Engines) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n| Engine | Queries | Timeouts | Errors | Top results | Failure rate | Status |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, e := range o.Engines {
+		status := "ok"
+		if e.Excluded {
+			status = "excluded"
+			if e.ExcludedUntil != nil {
+				status += " until " + *e.ExcludedUntil
+			}
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %.0f%% | %s |\n",
+			e.Engine, e.Queries, e.Timeouts, e.Errors, e.TopResults, e.FailureRate*100, status)
+	}
+	return b.String()
+}
+
+// Markdown renders image metadata; the images themselves follow as image content
+func (o ImageSearchOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Images for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "%d of %d images included.\n\n", o.Included, len(o.Images))
+
+	for _, img := range o.Images {
+		fmt.Fprintf(&b, "%d. %s", img.Index, markdownLink(img.Title, img.PageURL))
+		var details []string
+		if img.Resolution != nil {
+			details = append(details, *img.Resolution)
+		}
+		if img.Author != nil {
+			details = append(details, "by "+*img.Author)
+		}
+		if img.Source != nil {
+			details = append(details, *img.Source)
+		}
+		if img.License != nil {
+			details = append(details, "license "+*img.License)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(details, ", "))
+		}
+		b.WriteString("\n")
+		switch {
+		case img.ContentIndex != nil:
+			fmt.Fprintf(&b, "   Image %d, %dx%d, %d bytes\n", *img.ContentIndex, img.Width, img.Height, img.Bytes)
+		case img.Error != nil:
+			fmt.Fprintf(&b, "   Not included: %s\n", *img.Error)
+		}
+	}
+	writeNextCursor(&b, o.NextCursor)
+	return b.String()
+}
+
+// Markdown renders news stories with the articles covering them
+func (o NewsDigestOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## News digest for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "%d stories from %d articles", o.TotalStories, o.Articles)
+	if len(o.Stories) < o.TotalStories {
+		fmt.Fprintf(&b, ", top %d shown", len(o.Stories))
+	}
+	b.WriteString(".\n")
+	for _, e := range o.PageErrors {
+		fmt.Fprintf(&b, "\nWarning: %s\n", e)
+	}
+
+	for i, s := range o.Stories {
+		fmt.Fprintf(&b, "\n### %d. %s\n\n", i+1, s.Headline)
+		fmt.Fprintf(&b, "%s: %s", plural(len(s.Outlets), "outlet"), strings.Join(s.Outlets, ", "))
+		if s.Latest != nil {
+			fmt.Fprintf(&b, ", latest %s", s.Latest.Format(time.RFC3339))
+		}
+		b.WriteString("\n\n")
+		if s.Snippet != "" {
+			fmt.Fprintf(&b, "%s\n\n", s.Snippet)
+		}
+		for _, a := range s.Articles {
+			fmt.Fprintf(&b, "- %s (%s)\n", markdownLink(a.Title, a.URL), a.Outlet)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders papers with their bibliographic details, followed by any citations
+func (o PapersOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Papers for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "%s.\n", resultCount(len(o.Papers), o.Total))
+
+	for _, p := range o.Papers {
+		fmt.Fprintf(&b, "\n%d. **%s**\n", p.Index, markdownLink(p.Title, p.URL))
+		var details []string
+		if len(p.Authors) > 0 {
+			details = append(details, strings.Join(p.Authors, ", "))
+		}
+		if p.Journal != "" {
+			details = append(details, "*"+p.Journal+"*")
+		}
+		if p.Year > 0 {
+			details = append(details, strconv.Itoa(p.Year))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, "   %s\n", strings.Join(details, " · "))
+		}
+		if p.DOI != "" {
+			fmt.Fprintf(&b, "   DOI: %s\n", p.DOI)
+		}
+		for _, name := range slices.Sorted(maps.Keys(p.ResolverLinks)) {
+			fmt.Fprintf(&b, "   %s: %s\n", name, p.ResolverLinks[name])
+		}
+		if p.PDFURL != "" {
+			fmt.Fprintf(&b, "   PDF: %s\n", p.PDFURL)
+		}
+	}
+
+	if o.CitationFormat != "" {
+		fmt.Fprintf(&b, "\n### Citations (%s)\n\n```\n%s\n```\n", o.CitationFormat, strings.TrimSpace(o.Citations))
+	}
+	writeNextCursor(&b, o.NextCursor)
+	return b.String()
+}
+
+// Markdown renders places with their address, coordinates and distance
+func (o PlacesOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Places\n\n%d places found.\n\n", len(o.Features))
+
+	for i, f := range o.Features {
+		p := f.Properties
+		fmt.Fprintf(&b, "%d. %s\n", i+1, markdownLink(p.Name, p.URL))
+		if p.Address != "" {
+			fmt.Fprintf(&b, "   %s\n", p.Address)
+		}
+		pos := f.Position()
+		fmt.Fprintf(&b, "   %.6f, %.6f", pos.Lat, pos.Lon)
+		if p.DistanceKm != nil {
+			fmt.Fprintf(&b, " (%.3f km away)", *p.DistanceKm)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Markdown renders videos with duration, channel and views
+func (o VideosOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Videos for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "%d videos", len(o.Videos))
+	if o.Filtered > 0 {
+		fmt.Fprintf(&b, ", %d filtered out", o.Filtered)
+	}
+	b.WriteString(".\n\n")
+
+	for _, v := range o.Videos {
+		fmt.Fprintf(&b, "%d. %s", v.Index, markdownLink(v.Title, v.URL))
+		details := []string{v.Platform}
+		if v.Duration != "" {
+			details = append(details, v.Duration)
+		}
+		if v.Channel != "" {
+			details = append(details, v.Channel)
+		}
+		if v.Views > 0 {
+			details = append(details, fmt.Sprintf("%d views", v.Views))
+		}
+		if v.PublishedDate != nil {
+			details = append(details, v.PublishedDate.Format(time.DateOnly))
+		}
+		fmt.Fprintf(&b, " (%s)\n", strings.Join(details, ", "))
+	}
+	writeNextCursor(&b, o.NextCursor)
+	return b.String()
+}
+
+// Markdown renders developer results grouped by source type
+func (o CodeOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Developer results for %q\n\n", o.Query)
+	fmt.Fprintf(&b, "%d results in %d groups.\n", o.Count, len(o.Groups))
+
+	for _, g := range o.Groups {
+		fmt.Fprintf(&b, "\n### %s\n\n", g.Kind)
+		for _, r := range g.Results {
+			fmt.Fprintf(&b, "- %s", markdownLink(r.Title, r.URL))
+			var details []string
+			if r.Version != "" {
+				details = append(details, r.Version)
+			}
+			if r.Language != "" {
+				details = append(details, r.Language)
+			}
+			if r.Stars != nil {
+				details = append(details, fmt.Sprintf("%d stars", *r.Stars))
+			}
+			if r.Downloads != nil {
+				details = append(details, fmt.Sprintf("%d downloads", *r.Downloads))
+			}
+			if r.Answered != nil && *r.Answered {
+				details = append(details, "answered")
+			}
+			if r.Score != nil {
+				details = append(details, fmt.Sprintf("score %d", *r.Score))
+			}
+			details = append(details, r.Engine)
+			fmt.Fprintf(&b, " (%s)\n", strings.Join(details, ", "))
+		}
+	}
+	writeNextCursor(&b, o.NextCursor)
+	return b.String()
+}
+
+// Markdown renders the watch with its current or new results
+func (o WatchOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Watch: %s\n\n", o.Query)
+	if o.Status == "removed" {
+		fmt.Fprintf(&b, "Stopped watching `%s`. New results found: %d.\n", o.URI, len(o.Results))
+	} else {
+		fmt.Fprintf(&b, "Re-run every %d minutes. Subscribe to `%s` for new results. Current results: %d.\n", o.IntervalMinutes, o.URI, len(o.Results))
+	}
+	if len(o.Results) > 0 {
+		b.WriteString("\n")
+	}
+	for i, r := range o.Results {
+		fmt.Fprintf(&b, "%d. %s", i+1, markdownLink(r.Title, r.URL))
+		if r.PublishedDate != "" {
+			fmt.Fprintf(&b, " (%s)", r.PublishedDate)
+		}
+		b.WriteString("\n")
+		if r.Content != "" {
+			fmt.Fprintf(&b, "   %s\n", r.Content)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the evidence table followed by the queries and failed sources
+func (o VerifyClaimOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Evidence for %q\n\n", o.Claim)
+	fmt.Fprintf(&b, "%d supporting, %d contradicting and %d unrelated passages from %s. Stances are guessed from lexical cues; this is not a verdict.\n",
+		o.Supporting, o.Contradicting, o.Unrelated, plural(len(o.Sources), "source"))
+
+	if len(o.Evidence) > 0 {
+		b.WriteString("\n| Stance | Passage | Source | Date | Cues |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, e := range o.Evidence {
+			date := ""
+			if e.Date != nil {
+				date = *e.Date
+			}
+			fmt.Fprintf(&b, "| %s | %s | [%d] %s | %s | %s |\n", e.Stance, tableCell(e.Passage),
+				e.Source, tableCell(markdownLink(e.Title, e.URL)), tableCell(date), tableCell(strings.Join(e.Cues, "; ")))
+		}
+	}
+
+	b.WriteString("\n### Queries\n\n")
+	for _, q := range o.Queries {
+		fmt.Fprintf(&b, "- %s, %s: `%s`", q.Framing, q.Category, q.Query)
+		if q.Error != nil {
+			fmt.Fprintf(&b, " (error: %s)\n", *q.Error)
+			continue
+		}
+		fmt.Fprintf(&b, " (%s)\n", plural(q.Results, "result"))
+	}
+
+	var failed []ClaimSource
+	for _, s := range o.Sources {
+		if s.Error != nil {
+			failed = append(failed, s)
+		}
+	}
+	if len(failed) > 0 {
+		b.WriteString("\n### Unread sources\n\n")
+		for _, s := range failed {
+			fmt.Fprintf(&b, "- [%d] %s: %s\n", s.Rank, markdownLink(s.Title, s.URL), *s.Error)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the variants, their pairwise similarity, the shared and
+// unique results and the rank changes
+func (o CompareOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Comparison of %q\n\n", o.Query)
+	b.WriteString("| Variant | Results | Unique | Domains |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, v := range o.Variants {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", tableCell(v.Label), v.Results, len(v.Unique), v.Domains)
+	}
+	for _, e := range o.Errors {
+		fmt.Fprintf(&b, "\nFailed: %s\n", e)
+	}
+
+	b.WriteString("\n### Similarity\n\n")
+	b.WriteString("| Variants | Shared | URL Jaccard | Domain Jaccard |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, p := range o.Pairs {
+		fmt.Fprintf(&b, "| %s vs %s | %d | %.2f | %.2f |\n", tableCell(p.A), tableCell(p.B), p.Shared, p.URLJaccard, p.DomainJaccard)
+	}
+
+	fmt.Fprintf(&b, "\n### In every variant (%d)\n\n", len(o.Common))
+	if len(o.Common) == 0 {
+		b.WriteString("No result is shared by every variant.\n")
+	}
+	for i, r := range o.Common {
+		ranks := make([]string, len(r.Ranks))
+		for j, rank := range r.Ranks {
+			ranks[j] = fmt.Sprint(rank)
+		}
+		fmt.Fprintf(&b, "%d. %s (ranks %s)\n", i+1, markdownLink(r.Title, r.URL), strings.Join(ranks, ", "))
+	}
+
+	for _, v := range o.Variants {
+		if len(v.Unique) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### Only in %s (%d)\n\n", v.Label, len(v.Unique))
+		for _, r := range v.Unique {
+			fmt.Fprintf(&b, "- %s (rank %d)\n", markdownLink(r.Title, r.URL), r.Rank)
+		}
+	}
+
+	for _, p := range o.Pairs {
+		if len(p.RankChanges) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### Rank changes from %s to %s\n\n", p.A, p.B)
+		for _, c := range p.RankChanges {
+			fmt.Fprintf(&b, "- %s: %d → %d (%+d)\n", markdownLink(c.Title, c.URL), c.From, c.To, c.Delta)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the recorded searches as a table
+func (o SearchHistoryOutput) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Search history\n\n")
+	if len(o.Entries) == 0 {
+		b.WriteString("No recorded search matches.\n")
+		return b.String()
+	}
+	if o.Total > len(o.Entries) {
+		fmt.Fprintf(&b, "Showing the newest %d of %d matching searches.\n\n", len(o.Entries), o.Total)
+	}
+	b.WriteString("| ID | Time | Query | Parameters | Results | Top result |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, e := range o.Entries {
+		details := historyDetails(e)
+		top := ""
+		if len(e.Results) > 0 {
+			top = markdownLink(e.Results[0].Title, e.Results[0].URL)
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %d | %s |\n", e.ID, e.Time.Local().Format(time.DateTime),
+			tableCell(e.Query), tableCell(strings.Join(details[1:len(details)-1], "; ")), len(e.Results), tableCell(top))
+	}
+	b.WriteString("\nRepeat a search with rerun_search and its ID.\n")
+	return b.String()
+}
+
+// Markdown renders the changes since the search was last run, then the current results
+func (o RerunOutput) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Rerun of %q\n\n", o.Query)
+	fmt.Fprintf(&b, "Compared with entry %d from %s: %d added, %d removed, %d moved, %d unchanged. Recorded as entry %d.\n",
+		o.Previous, o.PreviousTime.Local().Format(time.DateTime), len(o.Added), len(o.Removed), len(o.Moved), o.Unchanged, o.Current)
+
+	if len(o.Added) > 0 {
+		fmt.Fprintf(&b, "\n### Added (%d)\n\n", len(o.Added))
+		for _, r := range o.Added {
+			fmt.Fprintf(&b, "- %s (rank %d)\n", markdownLink(r.Title, r.URL), r.Rank)
+		}
+	}
+	if len(o.Removed) > 0 {
+		fmt.Fprintf(&b, "\n### Removed (%d)\n\n", len(o.Removed))
+		for _, r := range o.Removed {
+			fmt.Fprintf(&b, "- %s (was rank %d)\n", markdownLink(r.Title, r.URL), r.Rank)
+		}
+	}
+	if len(o.Moved) > 0 {
+		fmt.Fprintf(&b, "\n### Moved (%d)\n\n", len(o.Moved))
+		for _, c := range o.Moved {
+			fmt.Fprintf(&b, "- %s: %d → %d (%+d)\n", markdownLink(c.Title, c.URL), c.From, c.To, c.Delta)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n### Current results (%d)\n\n", len(o.Results))
+	for _, r := range o.Results {
+		writeSimplifiedResult(&b, r)
+	}
+	return b.String()
+}
+
+// Markdown reports how many searches were removed
+func (o PurgeHistoryOutput) Markdown() string {
+	return fmt.Sprintf("## Search history purged\n\nRemoved %d searches; %d remain.\n", o.Removed, o.Remaining)
+}
+
+// writeNextCursor tells how to continue a search from its cursor
+func writeNextCursor(b *strings.Builder, cursor string) {
+	if cursor != "" {
+		fmt.Fprintf(b, "\nContinue with search_next and cursor: `%s`\n", cursor)
+	}
+}
+
+// writeSimplifiedResult writes a numbered search result with its summary
+func writeSimplifiedResult(b *strings.Builder, r SimplifiedResult) {
+	fmt.Fprintf(b, "%d. %s", r.Rank, markdownLink(r.Title, r.URL))
+	if r.Date != nil {
+		fmt.Fprintf(b, " (%s)", *r.Date)
+	}
+	b.WriteString("\n")
+	if r.Summary != "" {
+		fmt.Fprintf(b, "   %s\n", r.Summary)
+	}
+}
+
+// tableCell makes text fit in a Markdown table cell
+func tableCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(strings.TrimSpace(text))
+}
+
+// quote renders text as a Markdown block quote
+func quote(text string) string {
+	return "> " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n> ")
+}
+
+// plural formats a count with a noun, adding an s unless the count is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// resultCount describes how many of the reported results are shown
+func resultCount(shown, total int) string {
+	if total > shown {
+		return fmt.Sprintf("Showing %d of about %d results", shown, total)
+	}
+	return fmt.Sprintf("%d results", shown)
+}
+
+// markdownLink renders a Markdown link, falling back to the URL as title
+func markdownLink(title, url string) string {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = url
+	}
+	if url == "" {
+		return title
+	}
+	title = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(title)
+	return "[" + title + "](" + url + ")"
+}
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/news"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Defaults for the news digest tool
+const (
+	defaultNewsPages   = 3
+	maxNewsPages       = 5
+	defaultNewsStories = 10
+	maxNewsStories     = 30
+)
+
+// NewNewsDigestTool creates and registers a tool that clusters news results into stories
+func NewNewsDigestTool(server *mcp.Server, client searxng.Client, render Renderers) {
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "news_digest",
+		Description: "Search news over several result pages and cluster near-identical articles from different outlets into stories. Each story lists its outlets, earliest and latest publication dates, articles and a representative snippet; stories are ordered by recency and coverage breadth. Returns no next_cursor, since the digest already covers several result pages.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The news search query to execute",
+				},
+				"pages": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Number of news result pages to pull (default %d)", defaultNewsPages),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxNewsPages),
+				},
+				"max_stories": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of stories to return (default %d)", defaultNewsStories),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxNewsStories),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[NewsDigestOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args NewsDigestArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			Language:   args.Language,
+			Categories: []searxng.Category{searxng.CategoryNews},
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Pull the result pages concurrently
+		pages := clampArg(ctx, "pages", args.Pages, defaultNewsPages, maxNewsPages)
+		responses := make([]*searxng.SearchResponse, pages)
+		errs := make([]error, pages)
+		forEachBounded(ctx, pages, pages, func(ctx context.Context, i int) {
+			pageOpts := opts
+			pageOpts.PageNo = i + 1
+			responses[i], errs[i] = searxng.SearchWithOptions(ctx, client, args.Query, pageOpts)
+			reportProgress(ctx, 1, float64(pages), fmt.Sprintf("Fetched page %d", pageOpts.PageNo))
+		})
+
+		var results []searxng.SearchResult
+		var pageErrors []string
+		for i, response := range responses {
+			if response == nil {
+				err := errs[i]
+				if err == nil {
+					err = ctx.Err()
+				}
+				pageErrors = append(pageErrors, fmt.Sprintf("page %d: %v", i+1, err))
+				continue
+			}
+			results = append(results, response.Results...)
+		}
+		if len(pageErrors) == pages {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %s", strings.Join(pageErrors, "; "))},
+				},
+			}, nil, nil
+		}
+
+		// Cluster into stories
+		stories := news.Cluster(results, news.Options{})
+		totalStories := len(stories)
+		if limit := clampArg(ctx, "max_stories", args.MaxStories, defaultNewsStories, maxNewsStories); len(stories) > limit {
+			stories = stories[:limit]
+		}
+
+		// Format digest for MCP
+		return render.Result(args.Format, newNewsDigestOutput(args.Query, len(results), totalStories, stories, pageErrors))
+	})
+}
+
+// NewsDigestOutput is the structured result of the news_digest tool
+type NewsDigestOutput struct {
+	Query        string       `json:"query"`
+	Articles     int          `json:"articles"`
+	TotalStories int          `json:"total_stories"`
+	Stories      []news.Story `json:"stories"`
+	PageErrors   []string     `json:"page_errors,omitempty"`
+}
+
+// newNewsDigestOutput builds the structured result of a news digest
+func newNewsDigestOutput(query string, articles, totalStories int, stories []news.Story, pageErrors []string) NewsDigestOutput {
+	if stories == nil {
+		stories = []news.Story{}
+	}
+	return NewsDigestOutput{
+		Query:        query,
+		Articles:     articles,
+		TotalStories: totalStories,
+		Stories:      stories,
+		PageErrors:   pageErrors,
+	}
+}
@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"searxng-mcp/pkg/searxng"
+)
+
+// maxSearchPage is the highest result page SearXNG serves
+const maxSearchPage = 50
+
+// Output is the structured result of a tool. One of its renderings is sent as
+// text content alongside the structured content, for clients that only read text.
+type Output interface {
+	// Markdown renders the output as Markdown
+	Markdown() string
+	// Summary describes the output in one line
+	Summary() string
+	// Items lists the entries of the output for the text and citation renderings
+	Items() []Item
+}
+
+// SearchOutput is the structured result of the search, search_category and search_advanced tools
+type SearchOutput struct {
+	Query       string             `json:"query"`
+	Total       int                `json:"total"`
+	Results     []SimplifiedResult `json:"results"`
+	Infoboxes   []InfoboxResult    `json:"infoboxes,omitempty"`
+	Suggestions []string           `json:"suggestions,omitempty"`
+	Corrections []string           `json:"corrections,omitempty"`
+	Pagination  Pagination         `json:"pagination"`
+	Omitted     *Omission          `json:"omitted,omitempty"`
+	// EstimatedTokens approximates the size of the output when a token budget applies
+	EstimatedTokens int `json:"estimated_tokens,omitempty"`
+	// request is the search the output answers, as the caller asked for it
+	request searxng.SearchRequest
+}
+
+// searchResults returns the URLs and titles of the results returned
+func (o SearchOutput) searchResults() []searxng.SearchResult {
+	results := make([]searxng.SearchResult, len(o.Results))
+	for i, r := range o.Results {
+		results[i] = searxng.SearchResult{URL: r.URL, Title: r.Title}
+	}
+	return results
+}
+
+// Pagination describes the position of a result page
+type Pagination struct {
+	Page int `json:"page"`
+	// LastPage is set when further pages were fetched to fill the result limit
+	LastPage *int `json:"last_page,omitempty"`
+	NextPage *int `json:"next_page,omitempty"`
+	// NextCursor continues the search with search_next after the results returned
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// InfoboxResult represents a knowledge panel such as a Wikipedia summary
+type InfoboxResult struct {
+	Title      string         `json:"title"`
+	Content    string         `json:"content,omitempty"`
+	Image      string         `json:"image,omitempty"`
+	Links      []InfoboxLink  `json:"links,omitempty"`
+	Attributes []InfoboxField `json:"attributes,omitempty"`
+	Engine     string         `json:"engine,omitempty"`
+}
+
+// InfoboxLink is a link of an infobox
+type InfoboxLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// InfoboxField is a labelled value of an infobox
+type InfoboxField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// newSearchOutput builds the structured result of a search page with the default result limit
+func newSearchOutput(response *searxng.SearchResponse, page int) SearchOutput {
+	pages := searchPages{First: max(page, 1), Exhausted: len(response.Results) == 0}
+	pages.Last = pages.First
+	return resultBudget{Limit: defaultResultLimit}.searchOutput(response, pages)
+}
+
+// newInfoboxResult converts a SearXNG infobox
+func newInfoboxResult(infobox searxng.Infobox) InfoboxResult {
+	result := InfoboxResult{
+		Title:   strings.TrimSpace(infobox.Infobox),
+		Content: strings.Join(strings.Fields(infobox.Content), " "),
+		Image:   infobox.ImgSrc,
+		Engine:  infobox.Engine,
+	}
+	for _, u := range infobox.URLs {
+		if u.URL != "" {
+			result.Links = append(result.Links, InfoboxLink{Title: u.Title, URL: u.URL})
+		}
+	}
+	for _, a := range infobox.Attributes {
+		if value := attributeValue(a.Value); a.Label != "" && value != "" {
+			result.Attributes = append(result.Attributes, InfoboxField{Label: a.Label, Value: value})
+		}
+	}
+	return result
+}
+
+// attributeValue renders an infobox attribute value, which engines emit as text or as structured data
+func attributeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		// Wikidata image attributes carry the image URL in a nested object
+		if src, ok := v["src"].(string); ok {
+			return src
+		}
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+// outputSchema infers the JSON schema of a tool output type
+func outputSchema[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](nil)
+	if err != nil {
+		panic(fmt.Sprintf("output schema for %T: %v", *new(T), err))
+	}
+	flattenEmbedded(reflect.TypeFor[T](), schema)
+	return schema
+}
+
+// flattenEmbedded moves the properties of embedded structs up into the schema of
+// the embedding struct, matching how encoding/json promotes their fields
+func flattenEmbedded(t reflect.Type, schema *jsonschema.Schema) {
+	if schema == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		flattenEmbedded(t.Elem(), schema.Items)
+	case reflect.Map:
+		flattenEmbedded(t.Elem(), schema.AdditionalProperties)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, tagged := jsonFieldName(field)
+			if name == "-" || !field.IsExported() && !field.Anonymous {
+				continue
+			}
+
+			fieldSchema := schema.Properties[name]
+			flattenEmbedded(field.Type, fieldSchema)
+
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if !field.Anonymous || tagged || embedded.Kind() != reflect.Struct || fieldSchema == nil {
+				continue
+			}
+
+			delete(schema.Properties, name)
+			schema.Required = slices.DeleteFunc(schema.Required, func(r string) bool { return r == name })
+			for prop, s := range fieldSchema.Properties {
+				schema.Properties[prop] = s
+			}
+			schema.Required = append(schema.Required, fieldSchema.Required...)
+		}
+	}
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether its tag names it
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name, false
+	}
+	return name, true
+}
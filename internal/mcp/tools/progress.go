@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressKey is the context key of the progress reporter of a tool call
+type progressKey struct{}
+
+// progressReporter sends MCP progress notifications for a tool call whose
+// caller supplied a progress token
+type progressReporter struct {
+	session *mcp.ServerSession
+	token   any
+
+	mu       sync.Mutex
+	progress float64
+}
+
+// withProgress returns ctx carrying a progress reporter for req when the
+// caller supplied a progress token, and ctx unchanged otherwise
+func withProgress(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return ctx
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{session: req.Session, token: token})
+}
+
+// reportProgress advances the progress of the tool call in ctx by amount and
+// notifies its caller. total is the expected final progress, or 0 when unknown.
+// Calls without a progress token report nothing.
+func reportProgress(ctx context.Context, amount, total float64, message string) {
+	p, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok || ctx.Err() != nil {
+		return
+	}
+
+	// Notifications are sent under the lock so that concurrent steps arrive in order
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress += amount
+	params := &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      p.progress,
+		Message:       message,
+	}
+	if total > 0 {
+		params.Total = max(total, p.progress)
+	}
+	p.session.NotifyProgress(ctx, params)
+}
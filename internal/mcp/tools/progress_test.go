@@ -0,0 +1,133 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Progress", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		ctx     context.Context
+
+		mu            sync.Mutex
+		notifications []*mcp.ProgressNotificationParams
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		notifications = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/search":
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"query": %q, "results": [{"url": "https://example.com/", "title": "Example", "content": "text"}]}`, r.FormValue("q"))
+			case "/page":
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte(strings.Repeat("word ", 30000)))
+			case "/slow":
+				// Send part of the body, then stall until the client gives up
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte(strings.Repeat("word ", 100)))
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+			}
+		}))
+
+		mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+		tools.NewBatchSearchTool(mcpServer, searxng.NewClient(server.URL), tools.Renderers{})
+		tools.NewFetchURLTool(mcpServer, localFetcher(), tools.Renderers{})
+
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err := mcpServer.Connect(ctx, serverTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+			ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+				mu.Lock()
+				defer mu.Unlock()
+				notifications = append(notifications, req.Params)
+			},
+		})
+		session, err = client.Connect(ctx, clientTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	received := func() []*mcp.ProgressNotificationParams {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*mcp.ProgressNotificationParams(nil), notifications...)
+	}
+
+	It("should report each completed query of a batch", func() {
+		// SetProgressToken drops the token when Meta is nil, so set it directly
+		params := &mcp.CallToolParams{Meta: mcp.Meta{"progressToken": "batch"}, Name: "search_batch", Arguments: map[string]any{
+			"queries": []map[string]any{{"query": "generics"}, {"query": "modules"}, {"query": "channels"}},
+		}}
+
+		result, err := session.CallTool(ctx, params)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsError).To(BeFalse())
+
+		Eventually(received).Should(HaveLen(3))
+		for i, n := range received() {
+			Expect(n.ProgressToken).To(Equal("batch"))
+			Expect(n.Progress).To(BeNumerically("==", i+1))
+			Expect(n.Total).To(BeNumerically("==", 3))
+		}
+	})
+
+	It("should report the bytes read by fetch_url", func() {
+		params := &mcp.CallToolParams{Meta: mcp.Meta{"progressToken": 7}, Name: "fetch_url", Arguments: map[string]any{"url": server.URL + "/page"}}
+
+		result, err := session.CallTool(ctx, params)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsError).To(BeFalse())
+
+		Eventually(func() float64 {
+			n := received()
+			if len(n) == 0 {
+				return 0
+			}
+			return n[len(n)-1].Progress
+		}).Should(BeNumerically("==", 150000))
+		Expect(len(received())).To(BeNumerically(">", 1))
+	})
+
+	It("should not report progress without a progress token", func() {
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "search_batch", Arguments: map[string]any{
+			"queries": []map[string]any{{"query": "generics"}},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Consistently(received).Should(BeEmpty())
+	})
+
+	It("should return the partial page when a fetch times out mid-read", func() {
+		out := callToolJSON(ctx, session, "fetch_url", map[string]any{
+			"url":             server.URL + "/slow",
+			"timeout_seconds": 1,
+		})
+
+		Expect(out["truncated"]).To(BeTrue())
+		Expect(out["content"]).To(ContainSubstring("word word"))
+	})
+})
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Format names a text rendering of tool output
+type Format string
+
+const (
+	// FormatMarkdown renders headings and numbered lists with links
+	FormatMarkdown Format = "markdown"
+	// FormatText renders compact plain text, one line per item
+	FormatText Format = "text"
+	// FormatJSON renders the structured content as compact JSON
+	FormatJSON Format = "json"
+	// FormatCitation renders a numbered reference list ready to cite as [n]
+	FormatCitation Format = "citation"
+)
+
+// Formats lists the available formats
+var Formats = []Format{FormatMarkdown, FormatText, FormatJSON, FormatCitation}
+
+// Item is one entry of a tool output, as listed by the text and citation renderers
+type Item struct {
+	Title   string
+	URL     string
+	Date    string
+	Details []string
+	Text    string
+}
+
+// pagedOutput is implemented by outputs that search_next can continue
+type pagedOutput interface {
+	nextCursor() string
+}
+
+// Renderer renders a structured tool output as text
+type Renderer interface {
+	Render(out Output) string
+}
+
+// RendererFunc adapts a function to the Renderer interface
+type RendererFunc func(out Output) string
+
+// Render calls f(out)
+func (f RendererFunc) Render(out Output) string {
+	return f(out)
+}
+
+// renderers maps each format to its renderer
+var renderers = map[Format]Renderer{
+	FormatMarkdown: RendererFunc(Output.Markdown),
+	FormatText:     RendererFunc(renderText),
+	FormatJSON:     RendererFunc(renderJSON),
+	FormatCitation: RendererFunc(renderCitation),
+}
+
+// ParseFormat validates a format name; an empty name is returned as is
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	if format == "" {
+		return "", nil
+	}
+	if _, ok := renderers[format]; !ok {
+		return "", fmt.Errorf("invalid format '%s'. Valid options are: %s", name, strings.Join(formatNames(), ", "))
+	}
+	return format, nil
+}
+
+// RendererFor returns the renderer of a format, defaulting to Markdown
+func RendererFor(format Format) Renderer {
+	if r, ok := renderers[format]; ok {
+		return r
+	}
+	return renderers[FormatMarkdown]
+}
+
+// Renderers renders tool results in the format each call asks for
+type Renderers struct {
+	// Default is used when a call names no format; empty means Markdown
+	Default Format
+}
+
+// Result returns a tool result carrying out as structured content and its
+// rendering in the requested format, or the default format, as text content
+func (r Renderers) Result(format string, out Output, extra ...mcp.Content) (*mcp.CallToolResult, any, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+			},
+		}, nil, nil
+	}
+	if f == "" {
+		f = r.Default
+	}
+
+	content := append([]mcp.Content{&mcp.TextContent{Text: RendererFor(f).Render(out)}}, extra...)
+	return &mcp.CallToolResult{Content: content}, out, nil
+}
+
+// formatProperty is the input schema of the format argument shared by all tools
+func formatProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Rendering of the text content: markdown (numbered list with links), text (compact plain text), json (compact JSON) or citation (numbered reference list to cite as [n]). Defaults to the server setting.",
+		Enum:        interfaceSliceFromStringSlice(formatNames()),
+	}
+}
+
+// formatNames returns the names of the available formats
+func formatNames() []string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// renderJSON renders the structured content as compact JSON
+func renderJSON(out Output) string {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Sprintf(`{"error": "Failed to format results: %v"}`, err)
+	}
+	return string(data)
+}
+
+// renderText renders the summary and one line per item, followed by its text
+func renderText(out Output) string {
+	var b strings.Builder
+	b.WriteString(out.Summary())
+	b.WriteString("\n")
+	for i, item := range out.Items() {
+		fmt.Fprintf(&b, "%d. %s", i+1, firstNonEmptyString(item.Title, item.URL))
+		if item.URL != "" && item.Title != "" {
+			fmt.Fprintf(&b, " <%s>", item.URL)
+		}
+		if details := itemDetails(item); len(details) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(details, "; "))
+		}
+		b.WriteString("\n")
+		if text := strings.TrimSpace(item.Text); text != "" {
+			fmt.Fprintf(&b, "   %s\n", strings.ReplaceAll(text, "\n", "\n   "))
+		}
+	}
+	if paged, ok := out.(pagedOutput); ok && paged.nextCursor() != "" {
+		fmt.Fprintf(&b, "\nNext cursor: %s\n", paged.nextCursor())
+	}
+	return b.String()
+}
+
+// renderCitation renders a numbered reference list: title, site, date and URL,
+// followed by the quoted text each reference supports
+func renderCitation(out Output) string {
+	var b strings.Builder
+	b.WriteString(out.Summary())
+	b.WriteString("\n")
+	for i, item := range out.Items() {
+		parts := []string{firstNonEmptyString(item.Title, item.URL)}
+		parts = append(parts, item.Details...)
+		if domain := searxng.Domain(item.URL); domain != "" && item.Title != "" {
+			parts = append(parts, domain)
+		}
+		if item.Date != "" {
+			parts = append(parts, item.Date)
+		}
+		fmt.Fprintf(&b, "\n[%d] %s.", i+1, strings.Join(parts, ". "))
+		if item.URL != "" && item.Title != "" {
+			fmt.Fprintf(&b, " %s", item.URL)
+		}
+		b.WriteString("\n")
+		if text := strings.TrimSpace(item.Text); text != "" {
+			fmt.Fprintf(&b, "    %q\n", strings.Join(strings.Fields(text), " "))
+		}
+	}
+	if paged, ok := out.(pagedOutput); ok && paged.nextCursor() != "" {
+		fmt.Fprintf(&b, "\nNext cursor: %s\n", paged.nextCursor())
+	}
+	return b.String()
+}
+
+// itemDetails returns the date and details of an item
+func itemDetails(item Item) []string {
+	details := slices.Clone(item.Details)
+	if item.Date != "" {
+		details = append(details, item.Date)
+	}
+	return details
+}
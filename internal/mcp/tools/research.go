@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/research"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Limits for the research tool
+const (
+	maxResearchQueries = 20
+	maxResearchSeconds = 300
+	maxResearchSources = 100
+)
+
+// NewResearchTool creates and registers an iterative deep-research tool
+func NewResearchTool(server *mcp.Server, client searxng.Client, render Renderers) {
+	availableCategories := strings.Join(getAllCategoryNames(), ", ")
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+	defaults := research.DefaultOptions()
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "research",
+		Description: "Research a question iteratively: search, then follow SearXNG suggestions, corrections and terms frequent in top results with follow-up queries until a query, time or source budget is reached. Returns a numbered source ledger with the queries that found each source and key snippets. Returns no next_cursor; raise the budgets to research further.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"question": {
+					Type:        "string",
+					Description: "The question or topic to research",
+				},
+				"max_queries": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of searches to run (default %d)", defaults.MaxQueries),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxResearchQueries),
+				},
+				"max_seconds": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Time budget in seconds (default %d)", int(defaults.MaxDuration.Seconds())),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxResearchSeconds),
+				},
+				"max_sources": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Stop once this many distinct sources are collected (default %d)", defaults.MaxSources),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxResearchSources),
+				},
+				"categories": {
+					Type:        "array",
+					Description: fmt.Sprintf("Categories to search in (default general). Available: %s", availableCategories),
+					Items: &jsonschema.Schema{
+						Type: "string",
+						Enum: interfaceSlice(getAllCategoryNames()),
+					},
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"question"},
+		},
+		OutputSchema: outputSchema[ResearchOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args ResearchArgs) (*mcp.CallToolResult, any, error) {
+		// Report the queries run to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate question
+		if strings.TrimSpace(args.Question) == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: question parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build research options
+		opts := research.DefaultOptions()
+		opts.MaxQueries = clampArg(ctx, "max_queries", args.MaxQueries, opts.MaxQueries, maxResearchQueries)
+		opts.MaxSources = clampArg(ctx, "max_sources", args.MaxSources, opts.MaxSources, maxResearchSources)
+		opts.MaxDuration = time.Duration(clampArg(ctx, "max_seconds", args.MaxSeconds, int(opts.MaxDuration.Seconds()), maxResearchSeconds)) * time.Second
+		opts.Language = args.Language
+
+		categories, err := validateCategories(args.Categories)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+				},
+			}, nil, nil
+		}
+		opts.Categories = categories
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Run research
+		opts.OnQuery = func(log research.QueryLog) {
+			reportProgress(ctx, 1, float64(opts.MaxQueries), fmt.Sprintf("Searched '%s'", log.Query))
+		}
+		ledger, err := research.Run(ctx, client, args.Question, opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Research failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Format ledger for MCP
+		return render.Result(args.Format, newResearchOutput(ledger))
+	})
+}
+
+// ResearchOutput is the structured result of the research tool
+type ResearchOutput struct {
+	Question       string              `json:"question"`
+	StopReason     string              `json:"stop_reason"`
+	ElapsedSeconds float64             `json:"elapsed_seconds"`
+	Queries        []research.QueryLog `json:"queries"`
+	Sources        []research.Source   `json:"sources"`
+	TotalSources   int                 `json:"total_sources"`
+}
+
+// newResearchOutput builds the structured result of a research ledger
+func newResearchOutput(ledger *research.Ledger) ResearchOutput {
+	out := ResearchOutput{
+		Question:       ledger.Question,
+		StopReason:     ledger.StopReason,
+		ElapsedSeconds: ledger.Elapsed.Round(100 * time.Millisecond).Seconds(),
+		Queries:        ledger.Queries,
+		Sources:        ledger.Sources,
+		TotalSources:   len(ledger.Sources),
+	}
+	if out.Queries == nil {
+		out.Queries = []research.QueryLog{}
+	}
+	if out.Sources == nil {
+		out.Sources = []research.Source{}
+	}
+	return out
+}
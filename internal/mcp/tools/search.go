@@ -10,8 +10,8 @@ import (
 )
 
 // NewSearchTool creates and registers a simple search tool
-func NewSearchTool(server *mcp.Server, client searxng.Client) {
-	mcp.AddTool(server, &mcp.Tool{
+func NewSearchTool(server *mcp.Server, client searxng.Client, cursors *Cursors, render Renderers) {
+	addPagedTool(server, cursors, &mcp.Tool{
 		Name:        "search",
 		Description: "Perform a simple web search using SearXNG",
 		InputSchema: &jsonschema.Schema{
@@ -21,10 +21,19 @@ func NewSearchTool(server *mcp.Server, client searxng.Client) {
 					Type:        "string",
 					Description: "The search query to execute",
 				},
+				"limit":           limitProperty(),
+				"max_tokens":      maxTokensProperty(),
+				"include_domains": domainsProperty("Only return results from these domains."),
+				"exclude_domains": domainsProperty("Never return results from these domains."),
+				"format":          formatProperty(),
 			},
 			Required: []string{"query"},
 		},
+		OutputSchema: outputSchema[SearchOutput](),
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
 		// Validate query
 		if args.Query == "" {
 			return &mcp.CallToolResult{
@@ -35,8 +44,22 @@ func NewSearchTool(server *mcp.Server, client searxng.Client) {
 			}, nil, nil
 		}
 
-		// Perform search
-		response, err := searxng.SimpleSearch(ctx, client, args.Query)
+		// Narrow the search to the requested domains
+		client, err := domainClient(client, args.IncludeDomains, args.ExcludeDomains)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Perform search, continuing the cursor of an earlier call when resumed
+		budget := newResultBudget(ctx, args.Limit, args.MaxTokens)
+		page, seen := resumed(ctx, 1)
+		opts := searxng.SearchOptions{PageNo: page}
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
 		if err != nil {
 			return &mcp.CallToolResult{
 				IsError: true,
@@ -47,13 +70,7 @@ func NewSearchTool(server *mcp.Server, client searxng.Client) {
 		}
 
 		// Format results for MCP
-		content := formatSearchResultsJSON(response)
-
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: content},
-			},
-		}, nil, nil
+		return render.Result(args.Format, budget.pagedOutput(ctx, client, "search", args, args.Query, opts, response, seen))
 	})
 }
 
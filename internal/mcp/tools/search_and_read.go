@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/logging"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/webpage"
+)
+
+// Defaults for the search and read tool
+const (
+	defaultReadPages       = 3
+	maxReadPages           = 10
+	defaultReadConcurrency = 3
+	defaultPassagesPerPage = 3
+	maxPassageChars        = 600
+)
+
+// ReadSource represents a search result read in full, with its relevant passages
+type ReadSource struct {
+	SimplifiedResult
+	Passages  []webpage.Passage `json:"passages,omitempty"`
+	WordCount int               `json:"word_count,omitempty"`
+	Error     *string           `json:"error,omitempty"`
+}
+
+// ReadOutput is the structured result of the search_and_read tool
+type ReadOutput struct {
+	Query   string       `json:"query"`
+	Total   int          `json:"total"`
+	Sources []ReadSource `json:"sources"`
+	Read    int          `json:"read"`
+	Failed  int          `json:"failed"`
+}
+
+// NewSearchAndReadTool creates and registers a tool that searches and reads the top result pages
+func NewSearchAndReadTool(server *mcp.Server, client searxng.Client, fetcher *webpage.Fetcher, render Renderers) {
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_and_read",
+		Description: "Search with SearXNG, fetch the top result pages concurrently, and return the passages of each page most relevant to the query, numbered by source. Saves separate search and fetch_url round trips. Reads the first result page only and returns no next_cursor; use search for further pages.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The search query to execute",
+				},
+				"pages": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Number of top result pages to read (default %d)", defaultReadPages),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxReadPages),
+				},
+				"passages_per_page": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of passages returned per page (default %d)", defaultPassagesPerPage),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(10),
+				},
+				"concurrency": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of pages fetched in parallel (default %d)", defaultReadConcurrency),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxReadPages),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[ReadOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchAndReadArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages read to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			PageNo:   1,
+			Language: args.Language,
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Perform search
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Read the top pages
+		sources := readSources(ctx, fetcher, args.Query, topResults(response.Results, clampArg(ctx, "pages", args.Pages, defaultReadPages, maxReadPages)),
+			clampArg(ctx, "concurrency", args.Concurrency, defaultReadConcurrency, maxReadPages), clampArg(ctx, "passages_per_page", args.PassagesPerPage, defaultPassagesPerPage, 10), 0)
+
+		// Format sources for MCP
+		return render.Result(args.Format, newReadOutput(response, sources))
+	})
+}
+
+// readSources fetches result pages with bounded parallelism and extracts relevant passages.
+// Fetch failures are reported per source instead of failing the whole call. done counts
+// the progress steps the tool reported before reading, such as its searches.
+func readSources(ctx context.Context, fetcher *webpage.Fetcher, query string, results []searxng.SearchResult, concurrency, passagesPerPage, done int) []ReadSource {
+	sources := make([]ReadSource, len(results))
+	for i, result := range results {
+		sources[i] = ReadSource{SimplifiedResult: simplifyResult(i+1, result)}
+	}
+
+	started := make([]bool, len(results))
+	forEachBounded(ctx, len(results), concurrency, func(ctx context.Context, i int) {
+		started[i] = true
+		defer reportProgress(ctx, 1, float64(done+len(results)), fmt.Sprintf("Read %s", results[i].URL))
+		page, err := fetchPage(ctx, fetcher, results[i].URL)
+		if err != nil {
+			sources[i].Error = optionalString(err.Error())
+			return
+		}
+
+		article, err := webpage.ExtractPage(page)
+		if err != nil {
+			sources[i].Error = optionalString(err.Error())
+			return
+		}
+
+		sources[i].WordCount = article.WordCount
+		if sources[i].Date == nil && article.PublishedDate != "" {
+			sources[i].Date = optionalString(article.PublishedDate)
+		}
+		sources[i].Passages = webpage.RelevantPassages(article.Markdown, query, passagesPerPage, maxPassageChars)
+		if len(sources[i].Passages) == 0 && article.Excerpt != "" {
+			sources[i].Passages = []webpage.Passage{{Text: article.Excerpt}}
+		}
+	})
+
+	// Pages never started because the context ended still get an explanation
+	for i := range sources {
+		if !started[i] && ctx.Err() != nil {
+			sources[i].Error = optionalString(ctx.Err().Error())
+		}
+	}
+
+	return sources
+}
+
+// topResults returns up to n results, skipping duplicate URLs
+func topResults(results []searxng.SearchResult, n int) []searxng.SearchResult {
+	seen := make(map[string]bool)
+	var top []searxng.SearchResult
+	for _, r := range results {
+		if len(top) >= n {
+			break
+		}
+		if r.URL == "" || seen[r.URL] {
+			continue
+		}
+		seen[r.URL] = true
+		top = append(top, r)
+	}
+	return top
+}
+
+// newReadOutput builds the structured result of read sources
+func newReadOutput(response *searxng.SearchResponse, sources []ReadSource) ReadOutput {
+	failed := 0
+	for _, s := range sources {
+		if s.Error != nil {
+			failed++
+		}
+	}
+
+	return ReadOutput{
+		Query:   response.Query,
+		Total:   response.NumberOfResults,
+		Sources: sources,
+		Read:    len(sources) - failed,
+		Failed:  failed,
+	}
+}
+
+// clampInt returns value, or def when value is not positive, capped at maxValue
+func clampInt(value, def, maxValue int) int {
+	if value <= 0 {
+		return def
+	}
+	return min(value, maxValue)
+}
+
+// clampArg clamps the tool argument name like clampInt, logging when a given
+// value is replaced by the default or the cap
+func clampArg(ctx context.Context, name string, value, def, maxValue int) int {
+	if value < 0 || value > maxValue {
+		logging.FromContext(ctx).WarnContext(ctx, "argument out of range", "argument", name, "value", value, "used", clampInt(value, def, maxValue))
+	}
+	return clampInt(value, def, maxValue)
+}
@@ -0,0 +1,90 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Search and Read", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mux := http.NewServeMux()
+		server = httptest.NewServer(mux)
+		mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{
+				"query": "raft consensus",
+				"number_of_results": 2,
+				"results": [
+					{"url": "%[1]s/raft", "title": "Raft Explained", "content": "Raft overview"},
+					{"url": "%[1]s/missing", "title": "Missing Page", "content": "Gone"},
+					{"url": "%[1]s/raft", "title": "Raft Explained (duplicate)", "content": "Raft overview"}
+				]
+			}`, server.URL)
+		})
+		mux.HandleFunc("/raft", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><article>
+				<h1>Raft</h1>
+				<p>Distributed systems need agreement between machines even when some of them fail or restart unexpectedly.</p>
+				<p>Raft is a consensus algorithm that elects a leader, and the leader replicates the log to followers.</p>
+				<p>Cooking pasta requires salted boiling water and a timer set to the package instructions exactly.</p>
+			</article></body></html>`))
+		})
+		mux.HandleFunc("/missing", http.NotFound)
+
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewSearchAndReadTool(s, searxng.NewClient(server.URL), localFetcher(), tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should return relevant passages per source and report failures inline", func() {
+		out := callToolJSON(ctx, session, "search_and_read", map[string]any{
+			"query":             "raft consensus leader",
+			"passages_per_page": 1,
+		})
+
+		Expect(out["read"]).To(BeNumerically("==", 1))
+		Expect(out["failed"]).To(BeNumerically("==", 1))
+
+		sources := out["sources"].([]any)
+		Expect(sources).To(HaveLen(2))
+
+		first := sources[0].(map[string]any)
+		Expect(first["rank"]).To(BeNumerically("==", 1))
+		passages := first["passages"].([]any)
+		Expect(passages).To(HaveLen(1))
+		Expect(passages[0].(map[string]any)["text"]).To(ContainSubstring("consensus algorithm"))
+
+		second := sources[1].(map[string]any)
+		Expect(second["error"]).To(ContainSubstring("404"))
+	})
+
+	It("should reject an empty query", func() {
+		text, isError := callToolText(ctx, session, "search_and_read", map[string]any{"query": ""})
+
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("query parameter is required"))
+	})
+})
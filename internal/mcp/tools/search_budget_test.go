@@ -0,0 +1,137 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Search result budget", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		ctx     context.Context
+		pages   []int
+		content string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		pages = nil
+		content = strings.Repeat("Raft elects a leader that replicates the log to followers. ", 20)
+
+		// Serves eight results per page, over three pages
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.FormValue("pageno"))
+			page = max(page, 1)
+			pages = append(pages, page)
+
+			response := map[string]any{"query": r.FormValue("q"), "number_of_results": 24}
+			var results []map[string]any
+			for i := range 8 {
+				if page > 3 {
+					break
+				}
+				n := (page-1)*8 + i + 1
+				results = append(results, map[string]any{
+					"url":     fmt.Sprintf("https://example.com/raft/%d", n),
+					"title":   fmt.Sprintf("Raft part %d", n),
+					"content": content,
+				})
+			}
+			response["results"] = results
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(response)).To(Succeed())
+		}))
+
+		session = connectTools(ctx, func(s *mcp.Server) {
+			client := searxng.NewClient(server.URL)
+			tools.NewSearchTool(s, client, nil, tools.Renderers{})
+			tools.NewAdvancedSearchTool(s, client, nil, tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should return ten results and report the rest as omitted by default", func() {
+		out := callToolJSON(ctx, session, "search", map[string]any{"query": "raft"})
+
+		Expect(out["results"]).To(HaveLen(10))
+		Expect(pages).To(Equal([]int{1, 2}))
+		pagination := out["pagination"].(map[string]any)
+		Expect(pagination["last_page"]).To(BeNumerically("==", 2))
+		// The omitted results are on page 2, which the next call reads again
+		Expect(pagination["next_page"]).To(BeNumerically("==", 2))
+
+		omitted := out["omitted"].(map[string]any)
+		Expect(omitted["results"]).To(BeNumerically("==", 6))
+		Expect(omitted["truncated"]).To(BeNumerically("==", 10))
+		Expect(out).NotTo(HaveKey("estimated_tokens"))
+	})
+
+	It("should fetch further pages to fill the limit", func() {
+		out := callToolJSON(ctx, session, "search_advanced", map[string]any{"query": "raft", "page": 2, "limit": 20})
+
+		Expect(pages).To(Equal([]int{2, 3, 4}))
+		results := out["results"].([]any)
+		Expect(results).To(HaveLen(16))
+		Expect(results[0].(map[string]any)["title"]).To(Equal("Raft part 9"))
+		pagination := out["pagination"].(map[string]any)
+		Expect(pagination["page"]).To(BeNumerically("==", 2))
+		Expect(pagination["last_page"]).To(BeNumerically("==", 3))
+		Expect(pagination).NotTo(HaveKey("next_page"))
+	})
+
+	It("should fit results and summaries to the token budget", func() {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "search",
+			Arguments: map[string]any{"query": "raft", "max_tokens": 400},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var out tools.SearchOutput
+		data, err := json.Marshal(result.StructuredContent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(data, &out)).To(Succeed())
+
+		Expect(len(out.Results)).To(BeNumerically(">", 3))
+		Expect(out.EstimatedTokens).To(BeNumerically("<=", 400))
+		Expect(out.Omitted).NotTo(BeNil())
+		Expect(out.Omitted.Truncated).To(BeNumerically(">", 0))
+		Expect(out.Omitted.Characters).To(BeNumerically(">", 0))
+		for _, r := range out.Results {
+			Expect(r.Summary).To(Or(BeEmpty(), HaveSuffix("…")))
+			Expect(r.Summary).NotTo(ContainSubstring(" …"))
+		}
+
+		text := result.Content[0].(*mcp.TextContent).Text
+		Expect(text).To(ContainSubstring("raise limit or max_tokens"))
+	})
+
+	It("should truncate multi-byte text on rune boundaries", func() {
+		content = strings.Repeat("分布式共识算法🚀", 100)
+
+		out := callToolJSON(ctx, session, "search", map[string]any{"query": "raft", "limit": 1})
+
+		summary := out["results"].([]any)[0].(map[string]any)["summary"].(string)
+		Expect(utf8.ValidString(summary)).To(BeTrue())
+		Expect(utf8.RuneCountInString(summary)).To(Equal(500))
+		Expect(summary).To(HaveSuffix("…"))
+		Expect(out["omitted"].(map[string]any)["characters"]).To(BeNumerically("==", 800-499))
+	})
+})
@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/devsearch"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Defaults for the code search tool
+const (
+	defaultCodeResults = 20
+	maxCodeResults     = 50
+)
+
+// NewSearchCodeTool creates and registers a developer-focused search tool over the it category
+func NewSearchCodeTool(server *mcp.Server, client searxng.Client, cursors *Cursors, render Renderers) {
+	availableKinds := make([]string, len(devsearch.Kinds))
+	for i, kind := range devsearch.Kinds {
+		availableKinds[i] = string(kind)
+	}
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	addPagedTool(server, cursors, &mcp.Tool{
+		Name:        "search_code",
+		Description: fmt.Sprintf("Search developer sources in the SearXNG it category (GitHub, Stack Overflow, pkg.go.dev, PyPI, npm, Docker Hub, MDN, ...) and group results by source type (%s). Extracts structured fields where the engine reports them: repository owner, stars and language; package name, version, license and downloads; answered state, score and tags for Q&A.", strings.Join(availableKinds, ", ")),
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The developer search query to execute",
+				},
+				"kinds": {
+					Type:        "array",
+					Description: fmt.Sprintf("Only keep results of these source types. Available: %s", strings.Join(availableKinds, ", ")),
+					Items: &jsonschema.Schema{
+						Type: "string",
+						Enum: interfaceSliceFromStringSlice(availableKinds),
+					},
+				},
+				"max_results": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of results to return across all groups (default %d)", defaultCodeResults),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxCodeResults),
+				},
+				"page": {
+					Type:        "integer",
+					Description: "Page number for pagination (1-50)",
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(50),
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[CodeOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchCodeArgs) (*mcp.CallToolResult, any, error) {
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			PageNo:     1,
+			Categories: []searxng.Category{searxng.CategoryIT},
+		}
+
+		if args.Page > 0 {
+			if args.Page > 50 {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: "Error: page parameter must be between 1 and 50"},
+					},
+				}, nil, nil
+			}
+			opts.PageNo = args.Page
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Continue from the cursor of an earlier call
+		page, seen := resumed(ctx, opts.PageNo)
+		opts.PageNo = page
+
+		// Perform search
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Keep the requested source types, then group
+		var results []searxng.SearchResult
+		for _, r := range topResults(seen.unseen(response.Results), len(response.Results)) {
+			if len(args.Kinds) == 0 || slices.Contains(args.Kinds, string(devsearch.Classify(r))) {
+				results = append(results, r)
+			}
+		}
+		available := len(results)
+		if limit := clampArg(ctx, "max_results", args.MaxResults, defaultCodeResults, maxCodeResults); len(results) > limit {
+			results = results[:limit]
+		}
+		groups := devsearch.GroupResults(results)
+
+		// Continue on this page while results were left out, then on the next one
+		out := newCodeOutput(response, groups, len(results))
+		if len(response.Results) > 0 {
+			out.NextCursor = cursorAfter("search_code", args, nextPage(opts.PageNo, len(results), available), seen, results)
+		}
+
+		// Format groups for MCP
+		return render.Result(args.Format, out)
+	})
+}
+
+// CodeOutput is the structured result of the search_code tool
+type CodeOutput struct {
+	Query  string            `json:"query"`
+	Total  int               `json:"total"`
+	Count  int               `json:"count"`
+	Groups []devsearch.Group `json:"groups"`
+	// NextCursor continues the search with search_next after the results returned
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// newCodeOutput builds the structured result of grouped developer results
+func newCodeOutput(response *searxng.SearchResponse, groups []devsearch.Group, count int) CodeOutput {
+	if groups == nil {
+		groups = []devsearch.Group{}
+	}
+	return CodeOutput{
+		Query:  response.Query,
+		Total:  response.NumberOfResults,
+		Count:  count,
+		Groups: groups,
+	}
+}
@@ -0,0 +1,86 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Search domain filters", func() {
+	var (
+		server   *httptest.Server
+		session  *mcp.ClientSession
+		ctx      context.Context
+		requests []url.Values
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests = nil
+
+		// Ignores site: operators, as some engines do
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			requests = append(requests, r.Form)
+			var results []map[string]any
+			if r.FormValue("pageno") == "1" || r.FormValue("pageno") == "" {
+				for _, u := range []string{"https://go.dev/doc", "https://blog.golang.org/generics", "https://medium.com/go", "https://seo-farm.net/go"} {
+					results = append(results, map[string]any{"url": u, "title": u, "content": "Go generics"})
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(map[string]any{"query": r.FormValue("q"), "results": results})).To(Succeed())
+		}))
+
+		session = connectTools(ctx, func(s *mcp.Server) {
+			client := searxng.NewClient(server.URL)
+			tools.NewSearchTool(s, client, nil, tools.Renderers{})
+			tools.NewCategorySearchTool(s, client, nil, tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should add site operators and drop results outside the included domains", func() {
+		out := callToolJSON(ctx, session, "search", map[string]any{
+			"query": "generics", "include_domains": []string{"go.dev", "golang.org"},
+		})
+
+		Expect(requests[0].Get("q")).To(Equal("generics site:go.dev OR site:golang.org"))
+		Expect(out["query"]).To(Equal("generics"))
+		var urls []string
+		for _, r := range out["results"].([]any) {
+			urls = append(urls, r.(map[string]any)["url"].(string))
+		}
+		Expect(urls).To(Equal([]string{"https://go.dev/doc", "https://blog.golang.org/generics"}))
+	})
+
+	It("should exclude wildcard domains client-side only", func() {
+		out := callToolJSON(ctx, session, "search_category", map[string]any{
+			"query": "generics", "categories": []string{"it"}, "exclude_domains": []string{"medium.com", "seo-*.net"},
+		})
+
+		Expect(requests[0].Get("q")).To(Equal("generics -site:medium.com"))
+		Expect(out["results"]).To(HaveLen(2))
+	})
+
+	It("should reject an invalid domain", func() {
+		text, isError := callToolText(ctx, session, "search", map[string]any{
+			"query": "generics", "exclude_domains": []string{"not a domain"},
+		})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("invalid exclude_domains"))
+	})
+})
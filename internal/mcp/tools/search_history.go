@@ -0,0 +1,292 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/compare"
+	"searxng-mcp/pkg/history"
+)
+
+// Limits for the search history tool
+const (
+	defaultHistoryEntries = 20
+	maxHistoryEntries     = 200
+)
+
+// historyTimeDescription describes the values accepted for history times
+const historyTimeDescription = "an RFC 3339 time, a date (2006-01-02) or an age such as 12h, 7d or 4w"
+
+// SearchHistoryOutput is the structured result of the search_history tool
+type SearchHistoryOutput struct {
+	Entries []history.Entry `json:"entries"`
+	// Total is the number of entries matching the filters, of which Entries holds the newest
+	Total int `json:"total"`
+}
+
+// RerunOutput is the structured result of the rerun_search tool
+type RerunOutput struct {
+	Query string `json:"query"`
+	// Previous is the entry re-run and Current the entry recording the rerun
+	Previous     int64     `json:"previous"`
+	PreviousTime time.Time `json:"previous_time"`
+	Current      int64     `json:"current"`
+	// Added lists the results the rerun returned that the entry had not, at their new rank
+	Added []compare.Unique `json:"added"`
+	// Removed lists the results of the entry the rerun no longer returned, at their old rank
+	Removed []compare.Unique `json:"removed"`
+	// Moved lists the results returned at a different rank, largest moves first
+	Moved     []compare.RankChange `json:"moved"`
+	Unchanged int                  `json:"unchanged"`
+	Results   []SimplifiedResult   `json:"results"`
+}
+
+// PurgeHistoryOutput is the structured result of the purge_history tool
+type PurgeHistoryOutput struct {
+	Removed   int `json:"removed"`
+	Remaining int `json:"remaining"`
+}
+
+// NewSearchHistoryTool creates and registers a tool listing recorded searches
+func NewSearchHistoryTool(server *mcp.Server, store *history.Store, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_history",
+		Description: "List past searches from the local search history, newest first, with their parameters, time and result URLs. Filter by query text, category, language, result URL or domain, and time. Pass an entry id to rerun_search to repeat it and see what changed.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "Only list searches whose query contains this text",
+				},
+				"category": {
+					Type:        "string",
+					Description: "Only list searches in this category",
+					Enum:        interfaceSlice(getAllCategoryNames()),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Only list searches with this language code",
+				},
+				"url": {
+					Type:        "string",
+					Description: "Only list searches that returned a result URL containing this text, such as a domain",
+				},
+				"since": {
+					Type:        "string",
+					Description: "Only list searches made at or after this time: " + historyTimeDescription,
+				},
+				"until": {
+					Type:        "string",
+					Description: "Only list searches made before this time: " + historyTimeDescription,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of searches to list (default %d)", defaultHistoryEntries),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxHistoryEntries),
+				},
+				"format": formatProperty(),
+			},
+		},
+		OutputSchema: outputSchema[SearchHistoryOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchHistoryArgs) (*mcp.CallToolResult, any, error) {
+		filter := history.Filter{
+			Query:    strings.TrimSpace(args.Query),
+			Category: args.Category,
+			Language: args.Language,
+			URL:      strings.TrimSpace(args.URL),
+		}
+
+		var err error
+		now := time.Now()
+		if filter.Since, err = parseHistoryTime(args.Since, now); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: invalid since: %v", err)},
+				},
+			}, nil, nil
+		}
+		if filter.Until, err = parseHistoryTime(args.Until, now); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: invalid until: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		entries, total := store.List(filter, clampArg(ctx, "limit", args.Limit, defaultHistoryEntries, maxHistoryEntries))
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+
+		// Format history for MCP
+		return render.Result(args.Format, SearchHistoryOutput{Entries: entries, Total: total})
+	})
+}
+
+// NewRerunSearchTool creates and registers a tool repeating a recorded search
+// through the tool that made it, registered with cursors. The rerun is recorded in store.
+func NewRerunSearchTool(server *mcp.Server, cursors *Cursors, store *history.Store, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "rerun_search",
+		Description: "Repeat a search from the local search history with the same parameters and show what changed since: results added, results removed and results that moved, compared by normalized URL. The rerun is recorded as a new history entry. Returns the current results.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"id": {
+					Type:        "integer",
+					Description: "The id of the search_history entry to repeat",
+					Minimum:     floatPtr(1),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"id"},
+		},
+		OutputSchema: outputSchema[RerunOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args RerunSearchArgs) (*mcp.CallToolResult, any, error) {
+		entry, ok := store.Get(args.ID)
+		if !ok {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: unknown history entry %d", args.ID)},
+				},
+			}, nil, nil
+		}
+
+		out, current, err := cursors.rerun(ctx, req, entry)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Rerun failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Format changes for MCP
+		return render.Result(args.Format, newRerunOutput(entry, current, out))
+	})
+}
+
+// NewPurgeHistoryTool creates and registers a tool removing recorded searches
+func NewPurgeHistoryTool(server *mcp.Server, store *history.Store, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "purge_history",
+		Description: "Remove searches from the local search history: those whose query contains the given text, those made before a time, or all of them. At least one of query, before or all is required.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "Remove searches whose query contains this text",
+				},
+				"before": {
+					Type:        "string",
+					Description: "Remove searches made before this time: " + historyTimeDescription,
+				},
+				"all": {
+					Type:        "boolean",
+					Description: "Remove every search",
+				},
+				"format": formatProperty(),
+			},
+		},
+		OutputSchema: outputSchema[PurgeHistoryOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args PurgeHistoryArgs) (*mcp.CallToolResult, any, error) {
+		filter := history.Filter{Query: strings.TrimSpace(args.Query)}
+
+		var err error
+		if filter.Until, err = parseHistoryTime(args.Before, time.Now()); err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: invalid before: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// An empty filter matches every entry, so wiping the history must be asked for
+		if filter.Query == "" && filter.Until.IsZero() && !args.All {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: one of query, before or all is required"},
+				},
+			}, nil, nil
+		}
+
+		removed, err := store.Purge(filter)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Purge failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		_, remaining := store.List(history.Filter{}, 1)
+		return render.Result(args.Format, PurgeHistoryOutput{Removed: removed, Remaining: remaining})
+	})
+}
+
+// newRerunOutput compares the results of a history entry with those of its rerun
+func newRerunOutput(previous, current history.Entry, rerun SearchOutput) RerunOutput {
+	c := compare.Compare([]compare.Set{
+		{Label: "before", Results: previous.SearchResults()},
+		{Label: "now", Results: rerun.searchResults()},
+	})
+
+	return RerunOutput{
+		Query:        previous.Query,
+		Previous:     previous.ID,
+		PreviousTime: previous.Time,
+		Current:      current.ID,
+		Added:        c.Variants[1].Unique,
+		Removed:      c.Variants[0].Unique,
+		Moved:        c.Pairs[0].RankChanges,
+		Unchanged:    c.Pairs[0].Shared - len(c.Pairs[0].RankChanges),
+		Results:      rerun.Results,
+	}
+}
+
+// parseHistoryTime parses a time given as RFC 3339, a date or an age before
+// now in hours, days or weeks. An empty value yields the zero time.
+func parseHistoryTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+
+	// Days and weeks are not time.ParseDuration units
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit != 0 {
+		if n, err := strconv.ParseFloat(value[:len(value)-1], 64); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n * float64(unit))), nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not %s", value, historyTimeDescription)
+}
@@ -0,0 +1,155 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/history"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Search History", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		store   *history.Store
+		calls   atomic.Int32
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls.Store(0)
+
+		// The second search of a query returns changed results
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) == 1 {
+				fmt.Fprintf(w, `{"query": %q, "results": [
+					{"url": "https://go.dev/doc/", "title": "Documentation"},
+					{"url": "https://go.dev/blog/", "title": "Blog"},
+					{"url": "https://example.com/old", "title": "Old"}
+				]}`, r.FormValue("q"))
+				return
+			}
+			fmt.Fprintf(w, `{"query": %q, "results": [
+				{"url": "https://go.dev/blog/", "title": "Blog"},
+				{"url": "https://go.dev/doc/", "title": "Documentation"},
+				{"url": "https://example.com/new", "title": "New"}
+			]}`, r.FormValue("q"))
+		}))
+
+		var err error
+		store, err = history.Open(history.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		client := searxng.NewClient(server.URL)
+		cursors := tools.NewCursors()
+		cursors.SetHistory(store)
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewSearchTool(s, client, cursors, tools.Renderers{})
+			tools.NewAdvancedSearchTool(s, client, cursors, tools.Renderers{})
+			tools.NewSearchHistoryTool(s, store, tools.Renderers{})
+			tools.NewRerunSearchTool(s, cursors, store, tools.Renderers{})
+			tools.NewPurgeHistoryTool(s, store, tools.Renderers{})
+		})
+	})
+
+	// search runs a search whose results fill its limit, so no further page is fetched
+	search := func(query string) {
+		callToolJSON(ctx, session, "search", map[string]any{"query": query, "limit": 3})
+	}
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should list and filter recorded searches", func() {
+		search("golang docs")
+		search("rust book")
+
+		out := callToolJSON(ctx, session, "search_history", map[string]any{})
+		Expect(out["total"]).To(BeNumerically("==", 2))
+		entries := out["entries"].([]any)
+		Expect(entries[0].(map[string]any)["query"]).To(Equal("rust book"))
+
+		out = callToolJSON(ctx, session, "search_history", map[string]any{"query": "GOLANG", "url": "go.dev/doc", "since": "1h"})
+		Expect(out["total"]).To(BeNumerically("==", 1))
+		entry := out["entries"].([]any)[0].(map[string]any)
+		Expect(entry["query"]).To(Equal("golang docs"))
+		Expect(entry["categories"]).To(Equal([]any{"general"}))
+		Expect(entry["results"]).To(HaveLen(3))
+
+		text, isError := callToolText(ctx, session, "search_history", map[string]any{"since": "last week"})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("invalid since"))
+	})
+
+	It("should rerun a search and report what changed", func() {
+		search("golang docs")
+
+		out := callToolJSON(ctx, session, "rerun_search", map[string]any{"id": 1})
+		Expect(out["previous"]).To(BeNumerically("==", 1))
+		Expect(out["current"]).To(BeNumerically("==", 2))
+		Expect(out["added"]).To(HaveLen(1))
+		Expect(out["added"].([]any)[0].(map[string]any)["url"]).To(Equal("https://example.com/new"))
+		Expect(out["removed"]).To(HaveLen(1))
+		Expect(out["removed"].([]any)[0].(map[string]any)["url"]).To(Equal("https://example.com/old"))
+		Expect(out["moved"]).To(HaveLen(2))
+		Expect(out["unchanged"]).To(BeNumerically("==", 0))
+		Expect(out["results"]).To(HaveLen(3))
+
+		_, total := store.List(history.Filter{}, 0)
+		Expect(total).To(Equal(2))
+
+		text, isError := callToolText(ctx, session, "rerun_search", map[string]any{"id": 42})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("unknown history entry 42"))
+	})
+
+	It("should record one entry per call with its arguments and rerun it the same way", func() {
+		// The limit is not filled, so a second page is fetched
+		out := callToolJSON(ctx, session, "search_advanced", map[string]any{"query": "golang docs", "include_domains": []string{"go.dev"}, "limit": 5})
+		Expect(out["results"]).To(HaveLen(2))
+		Expect(calls.Load()).To(BeNumerically("==", 2))
+
+		entries, total := store.List(history.Filter{}, 0)
+		Expect(total).To(Equal(1))
+		Expect(entries[0].Tool).To(Equal("search_advanced"))
+		Expect(entries[0].Query).To(Equal("golang docs"))
+		Expect(string(entries[0].Args)).To(ContainSubstring(`"include_domains":["go.dev"]`))
+		Expect(entries[0].Results).To(HaveLen(2))
+
+		out = callToolJSON(ctx, session, "rerun_search", map[string]any{"id": entries[0].ID})
+		Expect(out["added"]).To(BeEmpty())
+		Expect(out["removed"]).To(BeEmpty())
+		Expect(out["results"]).To(HaveLen(2))
+		_, total = store.List(history.Filter{URL: "example.com"}, 0)
+		Expect(total).To(BeZero())
+	})
+
+	It("should purge matching searches and require a filter", func() {
+		search("golang docs")
+		search("rust book")
+
+		text, isError := callToolText(ctx, session, "purge_history", map[string]any{})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("one of query, before or all is required"))
+
+		out := callToolJSON(ctx, session, "purge_history", map[string]any{"query": "rust"})
+		Expect(out["removed"]).To(BeNumerically("==", 1))
+		Expect(out["remaining"]).To(BeNumerically("==", 1))
+
+		out = callToolJSON(ctx, session, "purge_history", map[string]any{"all": true})
+		Expect(out["removed"]).To(BeNumerically("==", 1))
+		Expect(out["remaining"]).To(BeNumerically("==", 0))
+	})
+})
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// NewSearchNextTool creates and registers a tool that continues a search from its cursor
+func NewSearchNextTool(server *mcp.Server, cursors *Cursors) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_next",
+		Description: "Continue an earlier search from the next_cursor it returned. The cursor encodes the original tool, query, filters and page, and the URLs already returned, so the next results repeat none of them. Works with search, search_category, search_advanced, search_images, search_papers, search_videos and search_code; search_places, news_digest, search_batch, search_and_read and research return no cursor.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"cursor": {
+					Type:        "string",
+					Description: "The next_cursor returned by an earlier search",
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"cursor"},
+		},
+		OutputSchema: &jsonschema.Schema{
+			Type:        "object",
+			Description: "The structured result of the tool the cursor continues",
+		},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchNextArgs) (*mcp.CallToolResult, any, error) {
+		// Validate cursor
+		if args.Cursor == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: cursor parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Run the continued call
+		result, err := cursors.Continue(ctx, req, args.Cursor, args.Format)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		return result, result.StructuredContent, nil
+	})
+}
@@ -0,0 +1,166 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/scholar"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Search Next", func() {
+	var (
+		server   *httptest.Server
+		session  *mcp.ClientSession
+		ctx      context.Context
+		requests []url.Values
+		mu       sync.Mutex
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests = nil
+
+		// Pages overlap by one result, as SearXNG pages often do
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			mu.Lock()
+			requests = append(requests, r.Form)
+			mu.Unlock()
+			page, _ := strconv.Atoi(r.FormValue("pageno"))
+			page = max(page, 1)
+
+			var results []map[string]any
+			for n := (page-1)*3 + 1; n <= page*3+1; n++ {
+				results = append(results, map[string]any{
+					"url":     fmt.Sprintf("https://example.com/%s/%d", r.FormValue("categories"), n),
+					"title":   fmt.Sprintf("Result %d", n),
+					"content": "Result content",
+					"engine":  "youtube",
+				})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(map[string]any{"query": r.FormValue("q"), "results": results})).To(Succeed())
+		}))
+
+		cursors := tools.NewCursors()
+		session = connectTools(ctx, func(s *mcp.Server) {
+			client := searxng.NewClient(server.URL)
+			tools.NewAdvancedSearchTool(s, client, cursors, tools.Renderers{})
+			tools.NewSearchVideosTool(s, client, cursors, tools.Renderers{})
+			tools.NewSearchPapersTool(s, client, scholar.Resolvers{}, cursors, tools.Renderers{})
+			tools.NewSearchNextTool(s, cursors)
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should continue a search with its original options and skip seen URLs", func() {
+		first := callToolJSON(ctx, session, "search_advanced", map[string]any{
+			"query": "raft", "language": "fr", "time_range": "year", "limit": 4,
+		})
+		Expect(first["results"]).To(HaveLen(4))
+		cursor := first["pagination"].(map[string]any)["next_cursor"].(string)
+		Expect(cursor).NotTo(BeEmpty())
+
+		requests = nil
+		next := callToolJSON(ctx, session, "search_next", map[string]any{"cursor": cursor})
+
+		Expect(requests).NotTo(BeEmpty())
+		Expect(requests[0].Get("q")).To(Equal("raft"))
+		Expect(requests[0].Get("language")).To(Equal("fr"))
+		Expect(requests[0].Get("time_range")).To(Equal("year"))
+		Expect(requests[0].Get("pageno")).To(Equal("2"))
+
+		results := next["results"].([]any)
+		Expect(results).To(HaveLen(4))
+		Expect(results[0].(map[string]any)["title"]).To(Equal("Result 5"))
+		Expect(next["pagination"].(map[string]any)["page"]).To(BeNumerically("==", 2))
+	})
+
+	It("should continue with the results a limit left out", func() {
+		first := callToolJSON(ctx, session, "search_advanced", map[string]any{"query": "raft", "limit": 3})
+		Expect(first["results"]).To(HaveLen(3))
+		pagination := first["pagination"].(map[string]any)
+		Expect(pagination["next_page"]).To(BeNumerically("==", 1))
+
+		requests = nil
+		next := callToolJSON(ctx, session, "search_next", map[string]any{"cursor": pagination["next_cursor"]})
+
+		// The first page is read again, skipping the results already returned
+		Expect(requests[0].Get("pageno")).To(Equal("1"))
+		results := next["results"].([]any)
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].(map[string]any)["title"]).To(Equal("Result 4"))
+		Expect(results[1].(map[string]any)["title"]).To(Equal("Result 5"))
+	})
+
+	It("should continue from the page of the first video a call left out", func() {
+		first := callToolJSON(ctx, session, "search_videos", map[string]any{
+			"query": "raft", "platforms": []string{"youtube"}, "max_results": 2,
+		})
+		cursor := first["next_cursor"].(string)
+
+		requests = nil
+		next := callToolJSON(ctx, session, "search_next", map[string]any{"cursor": cursor})
+
+		// Filtered video searches read three pages at once, from the page holding
+		// the first video left out
+		var pages []string
+		for _, form := range requests {
+			Expect(form.Get("categories")).To(Equal("videos"))
+			pages = append(pages, form.Get("pageno"))
+		}
+		Expect(pages).To(ConsistOf("1", "2", "3"))
+		videos := next["videos"].([]any)
+		Expect(videos).To(HaveLen(2))
+		Expect(videos[0].(map[string]any)["title"]).To(Equal("Result 3"))
+		Expect(videos[1].(map[string]any)["title"]).To(Equal("Result 4"))
+	})
+
+	It("should cite every paper of the next page after a call citing chosen papers", func() {
+		first := callToolJSON(ctx, session, "search_papers", map[string]any{
+			"query": "raft", "max_results": 2, "citation_format": "bibtex", "cite": []int{2},
+		})
+		Expect(first["citations"]).To(ContainSubstring("Result 2"))
+		Expect(first["citations"]).NotTo(ContainSubstring("Result 1"))
+
+		text, isError := callToolText(ctx, session, "search_next", map[string]any{"cursor": first["next_cursor"], "format": "json"})
+		Expect(isError).To(BeFalse(), text)
+		var next map[string]any
+		Expect(json.Unmarshal([]byte(text), &next)).To(Succeed())
+		Expect(next["papers"]).To(HaveLen(2))
+		Expect(next["citations"]).To(ContainSubstring("Result 3"))
+		Expect(next["citations"]).To(ContainSubstring("Result 4"))
+	})
+
+	It("should render the continued results in the requested format", func() {
+		first := callToolJSON(ctx, session, "search_advanced", map[string]any{"query": "raft"})
+		cursor := first["pagination"].(map[string]any)["next_cursor"].(string)
+
+		text, isError := callToolText(ctx, session, "search_next", map[string]any{"cursor": cursor, "format": "text"})
+		Expect(isError).To(BeFalse(), text)
+		Expect(text).To(HavePrefix(`"raft": `))
+		Expect(text).To(ContainSubstring("Next cursor: "))
+	})
+
+	It("should reject a malformed cursor", func() {
+		text, isError := callToolText(ctx, session, "search_next", map[string]any{"cursor": "not a cursor"})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("malformed cursor"))
+	})
+})
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/scholar"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Defaults for the paper search tool
+const (
+	defaultPaperResults = 10
+	maxPaperResults     = 30
+)
+
+// PaperResult represents a paper search result with its resolver links
+type PaperResult struct {
+	Index int `json:"index"`
+	scholar.Paper
+	ResolverLinks map[string]string `json:"resolver_links,omitempty"`
+	OpenAccessURL *string           `json:"open_access_url,omitempty"`
+}
+
+// NewSearchPapersTool creates and registers a tool that searches academic papers
+func NewSearchPapersTool(server *mcp.Server, client searxng.Client, resolvers scholar.Resolvers, cursors *Cursors, render Renderers) {
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	addPagedTool(server, cursors, &mcp.Tool{
+		Name:        "search_papers",
+		Description: fmt.Sprintf("Search academic papers in the SearXNG science category. Returns DOI, authors, journal and year for each paper, with links to the configured DOI resolvers (%s), and can export citations for chosen papers as BibTeX, RIS or CSL-JSON.", strings.Join(resolvers.Names(), ", ")),
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The paper search query to execute",
+				},
+				"max_results": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of papers to return (default %d)", defaultPaperResults),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxPaperResults),
+				},
+				"page": {
+					Type:        "integer",
+					Description: "Page number for pagination (1-50)",
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(50),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"citation_format": {
+					Type:        "string",
+					Description: fmt.Sprintf("Export citations in this format. Available: %s", strings.Join(scholar.Formats, ", ")),
+					Enum:        interfaceSliceFromStringSlice(scholar.Formats),
+				},
+				"cite": {
+					Type:        "array",
+					Description: "Indexes of the papers to cite (default all returned papers)",
+					Items: &jsonschema.Schema{
+						Type:    "integer",
+						Minimum: floatPtr(1),
+					},
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[PapersOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchPapersArgs) (*mcp.CallToolResult, any, error) {
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			PageNo:     1,
+			Language:   args.Language,
+			Categories: []searxng.Category{searxng.CategoryScience},
+		}
+
+		if args.Page > 0 {
+			if args.Page > 50 {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: "Error: page parameter must be between 1 and 50"},
+					},
+				}, nil, nil
+			}
+			opts.PageNo = args.Page
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Continue from the cursor of an earlier call
+		page, seen := resumed(ctx, opts.PageNo)
+		opts.PageNo = page
+
+		// Perform search
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		available := topResults(seen.unseen(response.Results), len(response.Results))
+		results := available[:min(len(available), clampArg(ctx, "max_results", args.MaxResults, defaultPaperResults, maxPaperResults))]
+		papers := paperResults(results, resolvers)
+
+		// Export citations for the chosen papers
+		var citations string
+		if args.CitationFormat != "" {
+			chosen, err := chosenPapers(papers, args.Cite)
+			if err == nil {
+				citations, err = scholar.Cite(chosen, args.CitationFormat)
+			}
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+					},
+				}, nil, nil
+			}
+		}
+
+		// Continue on this page while results were left out, then on the next one
+		out := newPapersOutput(response, papers, args.CitationFormat, citations)
+		if len(response.Results) > 0 {
+			// The cite indexes refer to this page's papers; later pages cite all of theirs
+			next := args
+			next.Cite = nil
+			out.NextCursor = cursorAfter("search_papers", next, nextPage(opts.PageNo, len(results), len(available)), seen, results)
+		}
+
+		// Format papers for MCP
+		return render.Result(args.Format, out)
+	})
+}
+
+// paperResults extracts paper metadata and resolver links from search results
+func paperResults(results []searxng.SearchResult, resolvers scholar.Resolvers) []PaperResult {
+	papers := make([]PaperResult, len(results))
+	for i, r := range results {
+		paper := scholar.FromResult(r)
+		papers[i] = PaperResult{
+			Index:         i + 1,
+			Paper:         paper,
+			ResolverLinks: resolvers.Links(paper.DOI),
+			OpenAccessURL: optionalString(resolvers.OpenAccessURL(paper.DOI)),
+		}
+	}
+	return papers
+}
+
+// chosenPapers returns the papers selected by 1-based indexes, or all papers when none are given
+func chosenPapers(papers []PaperResult, indexes []int) ([]scholar.Paper, error) {
+	if len(indexes) == 0 {
+		chosen := make([]scholar.Paper, len(papers))
+		for i, p := range papers {
+			chosen[i] = p.Paper
+		}
+		return chosen, nil
+	}
+
+	chosen := make([]scholar.Paper, 0, len(indexes))
+	for _, index := range indexes {
+		if index < 1 || index > len(papers) {
+			return nil, fmt.Errorf("cite index %d is out of range (1-%d)", index, len(papers))
+		}
+		chosen = append(chosen, papers[index-1].Paper)
+	}
+	return chosen, nil
+}
+
+// PapersOutput is the structured result of the search_papers tool
+type PapersOutput struct {
+	Query          string        `json:"query"`
+	Total          int           `json:"total"`
+	Papers         []PaperResult `json:"papers"`
+	CitationFormat string        `json:"citation_format,omitempty"`
+	Citations      string        `json:"citations,omitempty"`
+	// NextCursor continues the search with search_next after the results returned
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// newPapersOutput builds the structured result of paper results and optional citations
+func newPapersOutput(response *searxng.SearchResponse, papers []PaperResult, format, citations string) PapersOutput {
+	return PapersOutput{
+		Query:          response.Query,
+		Total:          response.NumberOfResults,
+		Papers:         papers,
+		CitationFormat: format,
+		Citations:      citations,
+	}
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/geo"
+	"searxng-mcp/pkg/searxng"
+)
+
+// Defaults for the place search tool
+const (
+	defaultPlaceResults = 10
+	maxPlaceResults     = 50
+)
+
+// PlacesOutput is the structured result of the search_places tool, a GeoJSON feature collection
+type PlacesOutput struct {
+	geo.FeatureCollection
+}
+
+// NewSearchPlacesTool creates and registers a tool that searches places and returns GeoJSON
+func NewSearchPlacesTool(server *mcp.Server, client searxng.Client, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_places",
+		Description: "Search places in the SearXNG map category (OpenStreetMap, Photon) and return a GeoJSON FeatureCollection with name, address, OSM type and id, coordinates and bounding box. Optionally filter by distance from a reference point and order by distance. Reads the first result page only and returns no next_cursor.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The place search query to execute (e.g., 'cafe near Alexanderplatz')",
+				},
+				"near_latitude": {
+					Type:        "number",
+					Description: "Latitude of the reference point used for distance filtering and ordering",
+					Minimum:     floatPtr(-90),
+					Maximum:     floatPtr(90),
+				},
+				"near_longitude": {
+					Type:        "number",
+					Description: "Longitude of the reference point used for distance filtering and ordering",
+					Minimum:     floatPtr(-180),
+					Maximum:     floatPtr(180),
+				},
+				"max_distance_km": {
+					Type:        "number",
+					Description: "Only keep places within this many kilometers of the reference point",
+					Minimum:     floatPtr(0),
+				},
+				"sort_by_distance": {
+					Type:        "boolean",
+					Description: "Order places by distance from the reference point, nearest first",
+				},
+				"max_results": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of places to return (default %d)", defaultPlaceResults),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxPlaceResults),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[PlacesOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchPlacesArgs) (*mcp.CallToolResult, any, error) {
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Validate reference point
+		hasOrigin := args.NearLatitude != nil && args.NearLongitude != nil
+		if (args.NearLatitude != nil) != (args.NearLongitude != nil) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: near_latitude and near_longitude must be given together"},
+				},
+			}, nil, nil
+		}
+		if !hasOrigin && (args.MaxDistanceKm > 0 || args.SortByDistance) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: max_distance_km and sort_by_distance require near_latitude and near_longitude"},
+				},
+			}, nil, nil
+		}
+
+		// Perform search
+		response, err := searxng.SearchWithOptions(ctx, client, args.Query, searxng.SearchOptions{
+			PageNo:     1,
+			Language:   args.Language,
+			Categories: []searxng.Category{searxng.CategoryMap},
+		})
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Convert results with coordinates to features
+		var features []geo.Feature
+		seen := make(map[string]bool)
+		for _, r := range response.Results {
+			feature, ok := geo.FromResult(r)
+			if !ok {
+				continue
+			}
+			// Several engines often return the same OSM object
+			if key := feature.Properties.OSMType + "/" + feature.Properties.OSMID; feature.Properties.OSMID != "" {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			features = append(features, feature)
+		}
+
+		if hasOrigin {
+			features = geo.WithinDistance(features, geo.Point{Lat: *args.NearLatitude, Lon: *args.NearLongitude}, args.MaxDistanceKm)
+			if args.SortByDistance {
+				geo.SortByDistance(features)
+			}
+		}
+		if limit := clampArg(ctx, "max_results", args.MaxResults, defaultPlaceResults, maxPlaceResults); len(features) > limit {
+			features = features[:limit]
+		}
+
+		// Format places for MCP
+		return render.Result(args.Format, PlacesOutput{FeatureCollection: geo.NewFeatureCollection(features)})
+	})
+}
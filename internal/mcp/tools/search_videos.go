@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/video"
+)
+
+// Defaults for the video search tool
+const (
+	defaultVideoResults = 10
+	maxVideoResults     = 30
+	// filteredVideoPages is the number of result pages pulled when filters are set
+	filteredVideoPages = 3
+)
+
+// VideoResult represents a video search result
+type VideoResult struct {
+	Index int `json:"index"`
+	video.Video
+}
+
+// VideosOutput is the structured result of the search_videos tool
+type VideosOutput struct {
+	Query    string        `json:"query"`
+	Total    int           `json:"total"`
+	Videos   []VideoResult `json:"videos"`
+	Filtered int           `json:"filtered"`
+	// NextCursor continues the search with search_next after the results returned
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewSearchVideosTool creates and registers a tool that searches videos with duration and platform filters
+func NewSearchVideosTool(server *mcp.Server, client searxng.Client, cursors *Cursors, render Renderers) {
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	addPagedTool(server, cursors, &mcp.Tool{
+		Name:        "search_videos",
+		Description: "Search videos with SearXNG and return embed URL, duration, channel, view count, thumbnail and published date for each video. Filter by minimum/maximum duration and by platform (engine, e.g. 'youtube', 'vimeo'), combined with a time range, e.g. a 10-minute tutorial from the last month.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The video search query to execute",
+				},
+				"min_duration_minutes": {
+					Type:        "number",
+					Description: "Only keep videos at least this many minutes long",
+					Minimum:     floatPtr(0),
+				},
+				"max_duration_minutes": {
+					Type:        "number",
+					Description: "Only keep videos at most this many minutes long",
+					Minimum:     floatPtr(0),
+				},
+				"platforms": {
+					Type:        "array",
+					Description: "Only keep videos from engines whose name contains one of these values (e.g., 'youtube', 'vimeo', 'dailymotion', 'peertube')",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"max_results": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of videos to return (default %d)", defaultVideoResults),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxVideoResults),
+				},
+				"page": {
+					Type:        "integer",
+					Description: "Page number for pagination (1-50)",
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(50),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[VideosOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args SearchVideosArgs) (*mcp.CallToolResult, any, error) {
+		// Report the pages fetched to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate query
+		if args.Query == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Validate filters
+		filter := video.Filter{
+			MinDuration: time.Duration(args.MinDurationMinutes * float64(time.Minute)),
+			MaxDuration: time.Duration(args.MaxDurationMinutes * float64(time.Minute)),
+			Platforms:   args.Platforms,
+		}
+		if filter.MaxDuration > 0 && filter.MinDuration > filter.MaxDuration {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: min_duration_minutes cannot exceed max_duration_minutes"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			PageNo:     1,
+			Language:   args.Language,
+			Categories: []searxng.Category{searxng.CategoryVideos},
+		}
+
+		if args.Page > 0 {
+			if args.Page > 50 {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: "Error: page parameter must be between 1 and 50"},
+					},
+				}, nil, nil
+			}
+			opts.PageNo = args.Page
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Continue from the cursor of an earlier call
+		page, seen := resumed(ctx, opts.PageNo)
+		opts.PageNo = page
+
+		// Filters discard results, so pull extra pages to keep enough candidates
+		pages := 1
+		if filter.MinDuration > 0 || filter.MaxDuration > 0 || len(filter.Platforms) > 0 {
+			pages = min(filteredVideoPages, 51-opts.PageNo)
+		}
+		responses := make([]*searxng.SearchResponse, pages)
+		errs := make([]error, pages)
+		forEachBounded(ctx, pages, pages, func(ctx context.Context, i int) {
+			pageOpts := opts
+			pageOpts.PageNo = opts.PageNo + i
+			responses[i], errs[i] = searxng.SearchWithOptions(ctx, client, args.Query, pageOpts)
+			reportProgress(ctx, 1, float64(pages), fmt.Sprintf("Fetched page %d", pageOpts.PageNo))
+		})
+
+		// The first page decides success; later pages only add candidates
+		if responses[0] == nil {
+			err := errs[0]
+			if err == nil {
+				err = ctx.Err()
+			}
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		// Later pages count until the first one that failed or came back empty
+		var results []searxng.SearchResult
+		pageOf := make(map[string]int)
+		last := opts.PageNo - 1
+		for _, response := range responses {
+			if response == nil || len(response.Results) == 0 {
+				break
+			}
+			last++
+			for _, r := range response.Results {
+				if _, ok := pageOf[r.URL]; !ok {
+					pageOf[r.URL] = last
+				}
+			}
+			results = append(results, response.Results...)
+		}
+
+		limit := clampArg(ctx, "max_results", args.MaxResults, defaultVideoResults, maxVideoResults)
+		results = topResults(seen.unseen(results), len(results))
+		videos, filtered, leftOut := filterVideos(results, filter, limit)
+
+		// Continue from the page of the first matching video left out, skipping the
+		// returned ones, or after the last page that returned results
+		out := newVideosOutput(responses[0], videos, filtered)
+		if last >= opts.PageNo {
+			next := last + 1
+			if leftOut >= 0 && len(videos) > 0 {
+				next = pageOf[results[leftOut].URL]
+			}
+			returned := make([]searxng.SearchResult, len(videos))
+			for i, v := range videos {
+				returned[i] = searxng.SearchResult{URL: v.URL}
+			}
+			out.NextCursor = cursorAfter("search_videos", args, next, seen, returned)
+		}
+
+		// Format videos for MCP
+		return render.Result(args.Format, out)
+	})
+}
+
+// filterVideos converts results to videos and keeps up to limit of those matching filter.
+// It also returns the number of videos the filter rejected and the index in results of
+// the first matching video left out by the limit, or -1.
+func filterVideos(results []searxng.SearchResult, filter video.Filter, limit int) ([]VideoResult, int, int) {
+	videos := []VideoResult{}
+	filtered := 0
+	leftOut := -1
+	for i, r := range results {
+		v := video.FromResult(r)
+		if !filter.Match(v) {
+			filtered++
+			continue
+		}
+		if len(videos) < limit {
+			videos = append(videos, VideoResult{Index: len(videos) + 1, Video: v})
+		} else if leftOut < 0 {
+			leftOut = i
+		}
+	}
+	return videos, filtered, leftOut
+}
+
+// newVideosOutput builds the structured result of video results
+func newVideosOutput(response *searxng.SearchResponse, videos []VideoResult, filtered int) VideosOutput {
+	return VideosOutput{
+		Query:    response.Query,
+		Total:    response.NumberOfResults,
+		Videos:   videos,
+		Filtered: filtered,
+	}
+}
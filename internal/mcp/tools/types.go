@@ -2,19 +2,233 @@ package tools
 
 // SearchArgs represents arguments for simple search tool
 type SearchArgs struct {
-	Query string `json:"query" jsonschema:"the search query to execute"`
+	Query          string   `json:"query" jsonschema:"the search query to execute"`
+	Format         string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+	Limit          int      `json:"limit,omitempty" jsonschema:"maximum number of results to return"`
+	MaxTokens      int      `json:"max_tokens,omitempty" jsonschema:"approximate token budget of the output"`
+	IncludeDomains []string `json:"include_domains,omitempty" jsonschema:"only return results from these domains"`
+	ExcludeDomains []string `json:"exclude_domains,omitempty" jsonschema:"never return results from these domains"`
 }
 
 // CategorySearchArgs represents arguments for category search tool
 type CategorySearchArgs struct {
-	Query      string   `json:"query" jsonschema:"the search query to execute"`
-	Categories []string `json:"categories" jsonschema:"categories to search in"`
+	Query          string   `json:"query" jsonschema:"the search query to execute"`
+	Categories     []string `json:"categories" jsonschema:"categories to search in"`
+	Format         string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+	Limit          int      `json:"limit,omitempty" jsonschema:"maximum number of results to return"`
+	MaxTokens      int      `json:"max_tokens,omitempty" jsonschema:"approximate token budget of the output"`
+	IncludeDomains []string `json:"include_domains,omitempty" jsonschema:"only return results from these domains"`
+	ExcludeDomains []string `json:"exclude_domains,omitempty" jsonschema:"never return results from these domains"`
 }
 
 // AdvancedSearchArgs represents arguments for advanced search tool
 type AdvancedSearchArgs struct {
-	Query     string `json:"query" jsonschema:"the search query to execute"`
+	Query          string   `json:"query" jsonschema:"the search query to execute"`
+	Language       string   `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange      string   `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Page           int      `json:"page,omitempty" jsonschema:"page number for pagination"`
+	Format         string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+	Limit          int      `json:"limit,omitempty" jsonschema:"maximum number of results to return"`
+	MaxTokens      int      `json:"max_tokens,omitempty" jsonschema:"approximate token budget of the output"`
+	IncludeDomains []string `json:"include_domains,omitempty" jsonschema:"only return results from these domains"`
+	ExcludeDomains []string `json:"exclude_domains,omitempty" jsonschema:"never return results from these domains"`
+}
+
+// EngineStatsArgs represents arguments for engine stats tool
+type EngineStatsArgs struct {
+	ExcludedOnly bool   `json:"excluded_only,omitempty" jsonschema:"only report engines that are currently excluded"`
+	Format       string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// FetchURLArgs represents arguments for fetch url tool
+type FetchURLArgs struct {
+	URL            string `json:"url" jsonschema:"the http(s) URL of the page to fetch"`
+	MaxBytes       int    `json:"max_bytes,omitempty" jsonschema:"maximum number of bytes to download"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"fetch timeout in seconds"`
+	Format         string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchAndReadArgs represents arguments for search and read tool
+type SearchAndReadArgs struct {
+	Query           string `json:"query" jsonschema:"the search query to execute"`
+	Pages           int    `json:"pages,omitempty" jsonschema:"number of top result pages to read"`
+	PassagesPerPage int    `json:"passages_per_page,omitempty" jsonschema:"maximum number of passages returned per page"`
+	Concurrency     int    `json:"concurrency,omitempty" jsonschema:"maximum number of pages fetched in parallel"`
+	Language        string `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange       string `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format          string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// BatchQuery represents one query specification of the batch search tool
+type BatchQuery struct {
+	Query      string   `json:"query" jsonschema:"the search query to execute"`
+	Categories []string `json:"categories,omitempty" jsonschema:"categories to search in"`
+	Language   string   `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange  string   `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Page       int      `json:"page,omitempty" jsonschema:"page number for pagination"`
+}
+
+// BatchSearchArgs represents arguments for batch search tool
+type BatchSearchArgs struct {
+	Queries        []BatchQuery `json:"queries" jsonschema:"query specifications to run"`
+	Concurrency    int          `json:"concurrency,omitempty" jsonschema:"maximum number of searches in flight"`
+	TimeoutSeconds int          `json:"timeout_seconds,omitempty" jsonschema:"deadline for the whole batch in seconds"`
+	Limit          int          `json:"limit,omitempty" jsonschema:"maximum number of results per query"`
+	Dedup          bool         `json:"dedup,omitempty" jsonschema:"omit URLs already returned by an earlier query"`
+	Format         string       `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// ResearchArgs represents arguments for research tool
+type ResearchArgs struct {
+	Question   string   `json:"question" jsonschema:"the question or topic to research"`
+	MaxQueries int      `json:"max_queries,omitempty" jsonschema:"maximum number of searches to run"`
+	MaxSeconds int      `json:"max_seconds,omitempty" jsonschema:"time budget in seconds"`
+	MaxSources int      `json:"max_sources,omitempty" jsonschema:"stop once this many distinct sources are collected"`
+	Categories []string `json:"categories,omitempty" jsonschema:"categories to search in"`
+	Language   string   `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange  string   `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format     string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// ImageSearchArgs represents arguments for image search tool
+type ImageSearchArgs struct {
+	Query         string `json:"query" jsonschema:"the image search query to execute"`
+	MaxImages     int    `json:"max_images,omitempty" jsonschema:"maximum number of images to return"`
+	MaxTotalBytes int    `json:"max_total_bytes,omitempty" jsonschema:"byte budget for all returned images"`
+	MaxDimension  int    `json:"max_dimension,omitempty" jsonschema:"maximum width or height of returned images in pixels"`
+	Language      string `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange     string `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format        string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// NewsDigestArgs represents arguments for news digest tool
+type NewsDigestArgs struct {
+	Query      string `json:"query" jsonschema:"the news search query to execute"`
+	Pages      int    `json:"pages,omitempty" jsonschema:"number of result pages to pull"`
+	MaxStories int    `json:"max_stories,omitempty" jsonschema:"maximum number of stories to return"`
+	Language   string `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange  string `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format     string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchPapersArgs represents arguments for paper search tool
+type SearchPapersArgs struct {
+	Query          string `json:"query" jsonschema:"the paper search query to execute"`
+	MaxResults     int    `json:"max_results,omitempty" jsonschema:"maximum number of papers to return"`
+	Page           int    `json:"page,omitempty" jsonschema:"page number for pagination"`
+	Language       string `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange      string `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	CitationFormat string `json:"citation_format,omitempty" jsonschema:"citation format to export"`
+	Cite           []int  `json:"cite,omitempty" jsonschema:"indexes of the papers to cite"`
+	Format         string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchPlacesArgs represents arguments for place search tool
+type SearchPlacesArgs struct {
+	Query          string   `json:"query" jsonschema:"the place search query to execute"`
+	NearLatitude   *float64 `json:"near_latitude,omitempty" jsonschema:"latitude of the reference point"`
+	NearLongitude  *float64 `json:"near_longitude,omitempty" jsonschema:"longitude of the reference point"`
+	MaxDistanceKm  float64  `json:"max_distance_km,omitempty" jsonschema:"only keep places within this distance of the reference point"`
+	SortByDistance bool     `json:"sort_by_distance,omitempty" jsonschema:"order places by distance from the reference point"`
+	MaxResults     int      `json:"max_results,omitempty" jsonschema:"maximum number of places to return"`
+	Language       string   `json:"language,omitempty" jsonschema:"language code for search results"`
+	Format         string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchVideosArgs represents arguments for video search tool
+type SearchVideosArgs struct {
+	Query              string   `json:"query" jsonschema:"the video search query to execute"`
+	MinDurationMinutes float64  `json:"min_duration_minutes,omitempty" jsonschema:"minimum video length in minutes"`
+	MaxDurationMinutes float64  `json:"max_duration_minutes,omitempty" jsonschema:"maximum video length in minutes"`
+	Platforms          []string `json:"platforms,omitempty" jsonschema:"only keep videos from these engines"`
+	MaxResults         int      `json:"max_results,omitempty" jsonschema:"maximum number of videos to return"`
+	Page               int      `json:"page,omitempty" jsonschema:"page number for pagination"`
+	Language           string   `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange          string   `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format             string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchCodeArgs represents arguments for code search tool
+type SearchCodeArgs struct {
+	Query      string   `json:"query" jsonschema:"the developer search query to execute"`
+	Kinds      []string `json:"kinds,omitempty" jsonschema:"only keep results of these source types"`
+	MaxResults int      `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
+	Page       int      `json:"page,omitempty" jsonschema:"page number for pagination"`
+	TimeRange  string   `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format     string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchNextArgs represents arguments for search next tool
+type SearchNextArgs struct {
+	Cursor string `json:"cursor" jsonschema:"the next_cursor returned by an earlier search"`
+	Format string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// WatchQueryArgs represents arguments for watch query tool
+type WatchQueryArgs struct {
+	Query           string   `json:"query" jsonschema:"the query to watch"`
+	Categories      []string `json:"categories,omitempty" jsonschema:"categories to search in"`
+	Language        string   `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange       string   `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	IntervalMinutes int      `json:"interval_minutes,omitempty" jsonschema:"how often the query is re-run in minutes"`
+	Format          string   `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// UnwatchQueryArgs represents arguments for unwatch query tool
+type UnwatchQueryArgs struct {
+	ID     string `json:"id" jsonschema:"the id or resource URI of the watch"`
+	Format string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// VerifyClaimArgs represents arguments for verify claim tool
+type VerifyClaimArgs struct {
+	Claim           string `json:"claim" jsonschema:"the factual statement to gather evidence about"`
+	Pages           int    `json:"pages,omitempty" jsonschema:"number of result pages to read across all queries"`
+	PassagesPerPage int    `json:"passages_per_page,omitempty" jsonschema:"maximum number of passages assessed per page"`
+	Language        string `json:"language,omitempty" jsonschema:"language code for search results"`
+	TimeRange       string `json:"time_range,omitempty" jsonschema:"time range for search results"`
+	Format          string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// CompareVariant represents one variant of the compare searches tool
+type CompareVariant struct {
+	Label     string `json:"label,omitempty" jsonschema:"name of the variant in the comparison"`
 	Language  string `json:"language,omitempty" jsonschema:"language code for search results"`
 	TimeRange string `json:"time_range,omitempty" jsonschema:"time range for search results"`
-	Page      int    `json:"page,omitempty" jsonschema:"page number for pagination"`
-}
\ No newline at end of file
+	Instance  string `json:"instance,omitempty" jsonschema:"SearXNG instance to search"`
+}
+
+// CompareSearchesArgs represents arguments for compare searches tool
+type CompareSearchesArgs struct {
+	Query      string           `json:"query" jsonschema:"the search query every variant runs"`
+	Variants   []CompareVariant `json:"variants" jsonschema:"variants of the search to compare"`
+	Categories []string         `json:"categories,omitempty" jsonschema:"categories every variant searches in"`
+	Pages      int              `json:"pages,omitempty" jsonschema:"number of result pages compared per variant"`
+	Format     string           `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// SearchHistoryArgs represents arguments for search history tool
+type SearchHistoryArgs struct {
+	Query    string `json:"query,omitempty" jsonschema:"only list searches whose query contains this text"`
+	Category string `json:"category,omitempty" jsonschema:"only list searches in this category"`
+	Language string `json:"language,omitempty" jsonschema:"only list searches with this language code"`
+	URL      string `json:"url,omitempty" jsonschema:"only list searches that returned a result URL containing this text"`
+	Since    string `json:"since,omitempty" jsonschema:"only list searches made at or after this time"`
+	Until    string `json:"until,omitempty" jsonschema:"only list searches made before this time"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"maximum number of searches to list"`
+	Format   string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// RerunSearchArgs represents arguments for rerun search tool
+type RerunSearchArgs struct {
+	ID     int64  `json:"id" jsonschema:"the id of the history entry to repeat"`
+	Format string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
+
+// PurgeHistoryArgs represents arguments for purge history tool
+type PurgeHistoryArgs struct {
+	Query  string `json:"query,omitempty" jsonschema:"remove searches whose query contains this text"`
+	Before string `json:"before,omitempty" jsonschema:"remove searches made before this time"`
+	All    bool   `json:"all,omitempty" jsonschema:"remove every search"`
+	Format string `json:"format,omitempty" jsonschema:"rendering of the text content"`
+}
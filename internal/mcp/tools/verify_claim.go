@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/claims"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/webpage"
+)
+
+// Defaults for the verify claim tool
+const (
+	defaultClaimPages    = 6
+	maxClaimPages        = 15
+	defaultClaimPassages = 3
+	maxClaimPassages     = 10
+)
+
+// claimCategories are the categories every query variant is searched in
+var claimCategories = []searxng.Category{searxng.CategoryGeneral, searxng.CategoryNews}
+
+// ClaimQuery is one search run for a claim
+type ClaimQuery struct {
+	Framing  claims.Framing `json:"framing"`
+	Category string         `json:"category"`
+	Query    string         `json:"query"`
+	Results  int            `json:"results"`
+	Error    *string        `json:"error,omitempty"`
+}
+
+// ClaimSource is a page read for a claim, with the framings of the queries that found it
+type ClaimSource struct {
+	SimplifiedResult
+	Framings []claims.Framing `json:"framings"`
+	Error    *string          `json:"error,omitempty"`
+}
+
+// ClaimEvidence is a row of the evidence table: a passage, its likely stance and its source
+type ClaimEvidence struct {
+	Stance  claims.Stance `json:"stance"`
+	Passage string        `json:"passage"`
+	// Overlap is the share of the claim's terms found in the passage
+	Overlap float64  `json:"overlap"`
+	Cues    []string `json:"cues,omitempty"`
+	// Source is the number of the source in sources
+	Source int     `json:"source"`
+	Title  string  `json:"title"`
+	URL    string  `json:"url"`
+	Date   *string `json:"date,omitempty"`
+}
+
+// VerifyClaimOutput is the structured result of the verify_claim tool
+type VerifyClaimOutput struct {
+	Claim         string          `json:"claim"`
+	Queries       []ClaimQuery    `json:"queries"`
+	Sources       []ClaimSource   `json:"sources"`
+	Evidence      []ClaimEvidence `json:"evidence"`
+	Supporting    int             `json:"supporting"`
+	Contradicting int             `json:"contradicting"`
+	Unrelated     int             `json:"unrelated"`
+}
+
+// NewVerifyClaimTool creates and registers a tool gathering evidence for and against a claim
+func NewVerifyClaimTool(server *mcp.Server, client searxng.Client, fetcher *webpage.Fetcher, render Renderers) {
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "verify_claim",
+		Description: "Gather evidence about a factual statement: search neutral, affirming and negating variants of it in the general and news categories, read the top pages, and sort their passages into likely supporting, contradicting or unrelated using lexical cues (negation, numeric mismatch, term and entity overlap). Returns an evidence table with source URLs and dates, not a verdict.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"claim": {
+					Type:        "string",
+					Description: "The factual statement to gather evidence about",
+				},
+				"pages": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Number of result pages to read across all queries (default %d)", defaultClaimPages),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxClaimPages),
+				},
+				"passages_per_page": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum number of passages assessed per page (default %d)", defaultClaimPassages),
+					Minimum:     floatPtr(1),
+					Maximum:     floatPtr(maxClaimPassages),
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"claim"},
+		},
+		OutputSchema: outputSchema[VerifyClaimOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args VerifyClaimArgs) (*mcp.CallToolResult, any, error) {
+		// Report the searches and pages read to callers that asked for progress
+		ctx = withProgress(ctx, req)
+
+		// Validate claim
+		claim := claims.Parse(args.Claim)
+		if claim.Text() == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: claim parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		// Build search options
+		opts := searxng.SearchOptions{
+			PageNo:   1,
+			Language: args.Language,
+		}
+
+		if args.TimeRange != "" {
+			timeRange, err := searxng.ValidateTimeRange(args.TimeRange)
+			if err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+			opts.TimeRange = timeRange
+		}
+
+		// Search every variant in every category concurrently
+		var queries []ClaimQuery
+		for _, variant := range claim.Variants() {
+			for _, category := range claimCategories {
+				queries = append(queries, ClaimQuery{Framing: variant.Framing, Category: string(category), Query: variant.Query})
+			}
+		}
+		pages := clampArg(ctx, "pages", args.Pages, defaultClaimPages, maxClaimPages)
+		responses := make([]*searxng.SearchResponse, len(queries))
+		forEachBounded(ctx, len(queries), len(queries), func(ctx context.Context, i int) {
+			queryOpts := opts
+			queryOpts.Categories = []searxng.Category{searxng.Category(queries[i].Category)}
+			response, err := searxng.SearchWithOptions(ctx, client, queries[i].Query, queryOpts)
+			if err != nil {
+				queries[i].Error = optionalString(err.Error())
+			} else {
+				responses[i] = response
+				queries[i].Results = len(response.Results)
+			}
+			reportProgress(ctx, 1, float64(len(queries)+pages), fmt.Sprintf("Searched '%s' in %s", queries[i].Query, queries[i].Category))
+		})
+
+		failed := 0
+		for i := range queries {
+			if queries[i].Error == nil && responses[i] == nil {
+				queries[i].Error = optionalString(ctx.Err().Error())
+			}
+			if queries[i].Error != nil {
+				failed++
+			}
+		}
+		if failed == len(queries) {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Search failed: %s", *queries[0].Error)},
+				},
+			}, nil, nil
+		}
+
+		// Read the top pages, taken in turn from each query so every framing is represented
+		results, framings := interleaveResults(queries, responses, pages)
+		read := readSources(ctx, fetcher, claim.Text(), results,
+			defaultReadConcurrency, clampArg(ctx, "passages_per_page", args.PassagesPerPage, defaultClaimPassages, maxClaimPassages), len(queries))
+
+		// Format evidence for MCP
+		return render.Result(args.Format, newVerifyClaimOutput(claim, queries, read, framings))
+	})
+}
+
+// interleaveResults takes up to n results from the responses in turn, skipping
+// URLs already taken, and records the framings of the queries returning each one
+func interleaveResults(queries []ClaimQuery, responses []*searxng.SearchResponse, n int) ([]searxng.SearchResult, [][]claims.Framing) {
+	var results []searxng.SearchResult
+	var framings [][]claims.Framing
+	index := make(map[string]int)
+
+	// Every response is scanned in full so framings are complete, but only
+	// the first n distinct URLs in turn order are kept
+	for rank := 0; ; rank++ {
+		more := false
+		for i, response := range responses {
+			if response == nil || rank >= len(response.Results) {
+				continue
+			}
+			more = true
+			result := response.Results[rank]
+			if result.URL == "" {
+				continue
+			}
+			key := searxng.NormalizeURL(result.URL)
+			if j, ok := index[key]; ok {
+				if !slices.Contains(framings[j], queries[i].Framing) {
+					framings[j] = append(framings[j], queries[i].Framing)
+				}
+				continue
+			}
+			if len(results) >= n {
+				continue
+			}
+			index[key] = len(results)
+			results = append(results, result)
+			framings = append(framings, []claims.Framing{queries[i].Framing})
+		}
+		if !more {
+			return results, framings
+		}
+	}
+}
+
+// newVerifyClaimOutput assesses the passages of the read sources against the
+// claim and builds the evidence table, ordered by stance then source
+func newVerifyClaimOutput(claim claims.Claim, queries []ClaimQuery, read []ReadSource, framings [][]claims.Framing) VerifyClaimOutput {
+	out := VerifyClaimOutput{
+		Claim:    claim.Text(),
+		Queries:  queries,
+		Sources:  make([]ClaimSource, len(read)),
+		Evidence: []ClaimEvidence{},
+	}
+
+	byStance := make(map[claims.Stance][]ClaimEvidence)
+	for i, s := range read {
+		out.Sources[i] = ClaimSource{SimplifiedResult: s.SimplifiedResult, Framings: framings[i], Error: s.Error}
+		for _, p := range s.Passages {
+			a := claim.Assess(p.Text)
+			byStance[a.Stance] = append(byStance[a.Stance], ClaimEvidence{
+				Stance:  a.Stance,
+				Passage: p.Text,
+				Overlap: a.Overlap,
+				Cues:    a.Cues,
+				Source:  s.Rank,
+				Title:   s.Title,
+				URL:     s.URL,
+				Date:    s.Date,
+			})
+		}
+	}
+	for _, stance := range claims.Stances {
+		out.Evidence = append(out.Evidence, byStance[stance]...)
+	}
+
+	out.Supporting = len(byStance[claims.StanceSupporting])
+	out.Contradicting = len(byStance[claims.StanceContradicting])
+	out.Unrelated = len(byStance[claims.StanceUnrelated])
+	return out
+}
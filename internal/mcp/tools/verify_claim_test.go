@@ -0,0 +1,142 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Verify Claim", func() {
+	var (
+		server   *httptest.Server
+		session  *mcp.ClientSession
+		ctx      context.Context
+		mu       sync.Mutex
+		searches []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		searches = nil
+
+		mux := http.NewServeMux()
+		server = httptest.NewServer(mux)
+		mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+			query, category := r.FormValue("q"), r.FormValue("categories")
+			mu.Lock()
+			searches = append(searches, category+": "+query)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case category == "news":
+				fmt.Fprintf(w, `{"query": %q, "results": [
+					{"url": "%s/myth", "title": "Space myths", "publishedDate": "2025-04-01T00:00:00"}
+				]}`, query, server.URL)
+			case strings.Contains(query, " not "):
+				fmt.Fprintf(w, `{"query": %q, "results": [
+					{"url": "%[2]s/myth", "title": "Space myths"},
+					{"url": "%[2]s/missing", "title": "Missing page"}
+				]}`, query, server.URL)
+			default:
+				fmt.Fprintf(w, `{"query": %q, "results": [
+					{"url": "%[2]s/travel", "title": "Visiting the wall"},
+					{"url": "%[2]s/travel/", "title": "Visiting the wall (duplicate)"}
+				]}`, query, server.URL)
+			}
+		})
+		mux.HandleFunc("/travel", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><meta property="article:published_time" content="2024-06-01"></head><body><article>
+				<p>Guides often repeat that the Great Wall of China is visible from space, and tour operators say it is true.</p>
+				<p>The Mutianyu section of the wall has a cable car and a toboggan ride down to the car park.</p>
+			</article></body></html>`))
+		})
+		mux.HandleFunc("/myth", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><article>
+				<p>Astronauts report that the Great Wall of China is not visible from space with the naked eye.</p>
+			</article></body></html>`))
+		})
+		mux.HandleFunc("/missing", http.NotFound)
+
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewVerifyClaimTool(s, searxng.NewClient(server.URL), localFetcher(), tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should search every framing in general and news and sort passages by stance", func() {
+		out := callToolJSON(ctx, session, "verify_claim", map[string]any{
+			"claim": "The Great Wall of China is visible from space.",
+		})
+
+		Expect(out["claim"]).To(Equal("The Great Wall of China is visible from space"))
+		Expect(searches).To(ConsistOf(
+			"general: great wall china visible space",
+			"news: great wall china visible space",
+			"general: The Great Wall of China is visible from space evidence",
+			"news: The Great Wall of China is visible from space evidence",
+			"general: The Great Wall of China is not visible from space",
+			"news: The Great Wall of China is not visible from space",
+		))
+		Expect(out["queries"]).To(HaveLen(6))
+
+		sources := out["sources"].([]any)
+		Expect(sources).To(HaveLen(3))
+		myth := sources[1].(map[string]any)
+		Expect(myth["url"]).To(HaveSuffix("/myth"))
+		Expect(myth["framings"]).To(ConsistOf("neutral", "affirming", "negating"))
+		Expect(sources[2].(map[string]any)["error"]).To(ContainSubstring("404"))
+
+		Expect(out["supporting"]).To(BeNumerically("==", 1))
+		Expect(out["contradicting"]).To(BeNumerically("==", 1))
+
+		evidence := out["evidence"].([]any)
+		first := evidence[0].(map[string]any)
+		Expect(first["stance"]).To(Equal("supporting"))
+		Expect(first["url"]).To(HaveSuffix("/travel"))
+		Expect(first["date"]).To(Equal("2024-06-01"))
+		Expect(first["cues"]).To(ContainElement("restates the claim"))
+
+		second := evidence[1].(map[string]any)
+		Expect(second["stance"]).To(Equal("contradicting"))
+		Expect(second["source"]).To(BeNumerically("==", 2))
+		Expect(second["date"]).To(Equal("2025-04-01T00:00:00"))
+		Expect(second["cues"]).To(ContainElement("opposite polarity: not"))
+	})
+
+	It("should render an evidence table without a verdict", func() {
+		text, isError := callToolText(ctx, session, "verify_claim", map[string]any{
+			"claim": "The Great Wall of China is visible from space",
+			"pages": 2,
+		})
+
+		Expect(isError).To(BeFalse())
+		Expect(text).To(ContainSubstring("| Stance | Passage | Source | Date | Cues |"))
+		Expect(text).To(ContainSubstring("this is not a verdict"))
+		Expect(text).To(ContainSubstring("- negating, news: `The Great Wall of China is not visible from space` (1 result)"))
+		Expect(text).NotTo(ContainSubstring("Unread sources"))
+	})
+
+	It("should reject an empty claim", func() {
+		text, isError := callToolText(ctx, session, "verify_claim", map[string]any{"claim": " . "})
+
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("claim parameter is required"))
+	})
+})
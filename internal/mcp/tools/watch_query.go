@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/watch"
+)
+
+// Limits for the watch query tool
+const (
+	defaultWatchMinutes = 60
+	minWatchMinutes     = 5
+	maxWatchMinutes     = 7 * 24 * 60
+)
+
+// WatchOutput is the structured result of the watch_query and unwatch_query tools
+type WatchOutput struct {
+	ID              string   `json:"id"`
+	URI             string   `json:"uri"`
+	Query           string   `json:"query"`
+	Categories      []string `json:"categories,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	TimeRange       string   `json:"time_range,omitempty"`
+	IntervalMinutes int      `json:"interval_minutes"`
+	// Status is watching, or removed after unwatch_query
+	Status string `json:"status"`
+	// Results holds the current results when the watch is added, which later
+	// refreshes are compared against, and the new results found when it is removed
+	Results []watch.Item `json:"results"`
+}
+
+// NewWatchQueryTool creates and registers a tool watching a query in the background
+func NewWatchQueryTool(server *mcp.Server, watcher *watch.Watcher, render Renderers) {
+	availableCategories := strings.Join(getAllCategoryNames(), ", ")
+	availableTimeRanges := strings.Join(getAllTimeRangeNames(), ", ")
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "watch_query",
+		Description: "Watch a query: the server re-runs it in the background at the given interval and collects the results earlier runs had not returned, compared by canonical URL. The new results are exposed as the searxng://watch/{id} resource, which clients can subscribe to for resources/updated notifications. Watches survive restarts; watching the same query again changes its interval. Returns the current results.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "The query to watch",
+				},
+				"categories": {
+					Type:        "array",
+					Description: fmt.Sprintf("Categories to search in (default general). Available: %s", availableCategories),
+					Items: &jsonschema.Schema{
+						Type: "string",
+						Enum: interfaceSlice(getAllCategoryNames()),
+					},
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code for search results (e.g., 'en', 'fr', 'es')",
+				},
+				"time_range": {
+					Type:        "string",
+					Description: fmt.Sprintf("Time range for search results. Available: %s", availableTimeRanges),
+					Enum:        interfaceSliceFromStringSlice(getAllTimeRangeNames()),
+				},
+				"interval_minutes": {
+					Type:        "integer",
+					Description: fmt.Sprintf("How often the query is re-run, in minutes (default %d)", defaultWatchMinutes),
+					Minimum:     floatPtr(minWatchMinutes),
+					Maximum:     floatPtr(maxWatchMinutes),
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"query"},
+		},
+		OutputSchema: outputSchema[WatchOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args WatchQueryArgs) (*mcp.CallToolResult, any, error) {
+		// Validate query
+		if strings.TrimSpace(args.Query) == "" {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: query parameter is required and must be a non-empty string"},
+				},
+			}, nil, nil
+		}
+
+		categories, err := validateCategories(args.Categories)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		if args.TimeRange != "" {
+			if _, err := searxng.ValidateTimeRange(args.TimeRange); err != nil {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("Error: invalid time_range '%s'. Valid options are: %s",
+							args.TimeRange, strings.Join(getAllTimeRangeNames(), ", "))},
+					},
+				}, nil, nil
+			}
+		}
+
+		minutes := max(clampArg(ctx, "interval_minutes", args.IntervalMinutes, defaultWatchMinutes, maxWatchMinutes), minWatchMinutes)
+		spec := watch.Watch{
+			Query:           strings.TrimSpace(args.Query),
+			Language:        args.Language,
+			TimeRange:       args.TimeRange,
+			IntervalSeconds: minutes * 60,
+		}
+		for _, category := range categories {
+			spec.Categories = append(spec.Categories, string(category))
+		}
+
+		// Run the query once so later refreshes have a result set to compare against
+		added, results, err := watcher.Add(ctx, spec)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Watch failed: %v", err)},
+				},
+			}, nil, nil
+		}
+
+		return render.Result(args.Format, newWatchOutput(added, "watching", results))
+	})
+}
+
+// NewUnwatchQueryTool creates and registers a tool removing a watched query
+func NewUnwatchQueryTool(server *mcp.Server, watcher *watch.Watcher, render Renderers) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "unwatch_query",
+		Description: "Stop watching a query added with watch_query and remove its resource. Returns the new results the watch had found.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"id": {
+					Type:        "string",
+					Description: "The id or searxng://watch/ resource URI of the watch",
+				},
+				"format": formatProperty(),
+			},
+			Required: []string{"id"},
+		},
+		OutputSchema: outputSchema[WatchOutput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args UnwatchQueryArgs) (*mcp.CallToolResult, any, error) {
+		id := strings.TrimSpace(args.ID)
+		if parsed, ok := watch.ParseURI(id); ok {
+			id = parsed
+		}
+
+		removed, ok, err := watcher.Remove(id)
+		if err != nil {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Unwatch failed: %v", err)},
+				},
+			}, nil, nil
+		}
+		if !ok {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("Error: unknown watch '%s'", args.ID)},
+				},
+			}, nil, nil
+		}
+
+		return render.Result(args.Format, newWatchOutput(removed, "removed", removed.New))
+	})
+}
+
+// newWatchOutput builds the structured result of a watch
+func newWatchOutput(w watch.Watch, status string, results []watch.Item) WatchOutput {
+	if results == nil {
+		results = []watch.Item{}
+	}
+	return WatchOutput{
+		ID:              w.ID,
+		URI:             w.URI(),
+		Query:           w.Query,
+		Categories:      w.Categories,
+		Language:        w.Language,
+		TimeRange:       w.TimeRange,
+		IntervalMinutes: int(w.Interval().Minutes()),
+		Status:          status,
+		Results:         results,
+	}
+}
@@ -0,0 +1,84 @@
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"searxng-mcp/internal/mcp/tools"
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/watch"
+)
+
+var _ = Describe("Watch Query", func() {
+	var (
+		server  *httptest.Server
+		session *mcp.ClientSession
+		watcher *watch.Watcher
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"query": %q, "results": [{"url": "https://example.com/notes", "title": "Release notes"}]}`, r.FormValue("q"))
+		}))
+
+		var err error
+		watcher, err = watch.NewWatcher(searxng.NewClient(server.URL), watch.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		session = connectTools(ctx, func(s *mcp.Server) {
+			tools.NewWatchQueryTool(s, watcher, tools.Renderers{})
+			tools.NewUnwatchQueryTool(s, watcher, tools.Renderers{})
+		})
+	})
+
+	AfterEach(func() {
+		session.Close()
+		server.Close()
+	})
+
+	It("should watch a query and return its current results", func() {
+		out := callToolJSON(ctx, session, "watch_query", map[string]any{
+			"query":            "go release notes",
+			"categories":       []string{"it"},
+			"interval_minutes": 30,
+		})
+
+		Expect(out["status"]).To(Equal("watching"))
+		Expect(out["interval_minutes"]).To(BeNumerically("==", 30))
+		Expect(out["uri"]).To(HavePrefix("searxng://watch/"))
+		Expect(out["results"]).To(HaveLen(1))
+
+		watches := watcher.List()
+		Expect(watches).To(HaveLen(1))
+		Expect(watches[0].Categories).To(Equal([]string{"it"}))
+	})
+
+	It("should remove a watch by its resource URI", func() {
+		added := callToolJSON(ctx, session, "watch_query", map[string]any{"query": "go release notes"})
+		Expect(added["interval_minutes"]).To(BeNumerically("==", 60))
+
+		out := callToolJSON(ctx, session, "unwatch_query", map[string]any{"id": added["uri"]})
+		Expect(out["status"]).To(Equal("removed"))
+		Expect(watcher.List()).To(BeEmpty())
+
+		text, isError := callToolText(ctx, session, "unwatch_query", map[string]any{"id": added["id"]})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("unknown watch"))
+	})
+
+	It("should reject a blank query", func() {
+		text, isError := callToolText(ctx, session, "watch_query", map[string]any{"query": "  "})
+		Expect(isError).To(BeTrue())
+		Expect(text).To(ContainSubstring("query parameter is required"))
+		Expect(watcher.List()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,385 @@
+// Package claims derives search queries from a factual statement and sorts
+// passages into likely supporting, contradicting or unrelated evidence using
+// lexical cues. It never decides whether the statement is true.
+package claims
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"searxng-mcp/pkg/webpage"
+)
+
+// Framing is the stance a query variant takes towards the claim
+type Framing string
+
+const (
+	// FramingNeutral searches the key terms of the claim
+	FramingNeutral Framing = "neutral"
+	// FramingAffirming searches the claim as stated
+	FramingAffirming Framing = "affirming"
+	// FramingNegating searches the opposite of the claim
+	FramingNegating Framing = "negating"
+)
+
+// Stance is how a passage likely relates to the claim
+type Stance string
+
+const (
+	StanceSupporting    Stance = "supporting"
+	StanceContradicting Stance = "contradicting"
+	StanceUnrelated     Stance = "unrelated"
+)
+
+// Stances lists the stances in display order
+var Stances = []Stance{StanceSupporting, StanceContradicting, StanceUnrelated}
+
+// Thresholds of the share of claim terms a passage or sentence must contain
+const (
+	// minOverlap is the share below which a passage is about something else
+	minOverlap = 0.5
+	// restateOverlap is the share above which a sentence restates the claim
+	restateOverlap = 0.7
+)
+
+// Variant is a search query derived from the claim
+type Variant struct {
+	Framing Framing `json:"framing"`
+	Query   string  `json:"query"`
+}
+
+// Assessment is the likely stance of a passage with the cues behind it
+type Assessment struct {
+	Stance Stance `json:"stance"`
+	// Overlap is the share of the claim's terms found in the passage
+	Overlap float64 `json:"overlap"`
+	// Cues lists the lexical cues the stance rests on
+	Cues []string `json:"cues,omitempty"`
+}
+
+// Claim is a parsed factual statement
+type Claim struct {
+	text     string
+	terms    []string
+	entities []string
+	numbers  []string
+	negated  bool
+}
+
+var (
+	// numberPattern matches integers and decimals, with thousands separators
+	numberPattern = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+	// sentencePattern matches sentence boundaries
+	sentencePattern = regexp.MustCompile(`[.!?]+\s+|\n+`)
+)
+
+// negations are the words that negate a sentence
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "none": true, "neither": true, "nor": true,
+	"cannot": true, "nothing": true, "nobody": true, "without": true,
+}
+
+// refutations are the words that mark a statement as false. They flip the
+// polarity of a sentence like negations do.
+var refutations = map[string]bool{
+	"false": true, "myth": true, "myths": true, "debunked": true, "debunk": true, "debunks": true,
+	"hoax": true, "misleading": true, "incorrect": true, "untrue": true, "inaccurate": true,
+	"disproved": true, "disproven": true, "refuted": true, "refutes": true, "misconception": true,
+	"fake": true, "fabricated": true, "wrong": true,
+}
+
+// confirmations are the words that mark a statement as established
+var confirmations = map[string]bool{
+	"confirmed": true, "confirms": true, "confirm": true, "proven": true, "proved": true,
+	"verified": true, "verifies": true, "demonstrated": true, "true": true, "accurate": true,
+	"correct": true, "indeed": true, "evidence": true,
+}
+
+// auxiliaries are the verbs a negation attaches to
+var auxiliaries = map[string]bool{
+	"is": true, "are": true, "was": true, "were": true, "has": true, "have": true, "had": true,
+	"can": true, "could": true, "will": true, "would": true, "does": true, "did": true, "do": true,
+	"should": true, "may": true, "might": true, "must": true,
+}
+
+// contractions maps negated auxiliaries to their positive form
+var contractions = map[string]string{
+	"isn't": "is", "aren't": "are", "wasn't": "was", "weren't": "were", "hasn't": "has",
+	"haven't": "have", "hadn't": "had", "can't": "can", "cannot": "can", "couldn't": "could",
+	"won't": "will", "wouldn't": "would", "doesn't": "does", "didn't": "did", "don't": "do",
+	"shouldn't": "should", "mustn't": "must",
+}
+
+// Parse parses a factual statement, dropping its final punctuation
+func Parse(text string) Claim {
+	text = strings.TrimRight(strings.TrimSpace(text), ".!? ")
+	c := Claim{text: text, numbers: numbers(text)}
+	for _, t := range webpage.Terms(text) {
+		if !negations[t] && !isNumber(t) {
+			c.terms = append(c.terms, t)
+		}
+	}
+
+	// Capitalized words are taken as the entities the claim is about
+	for _, word := range strings.Fields(text) {
+		if r := []rune(word); len(r) > 0 && unicode.IsUpper(r[0]) {
+			for _, t := range webpage.Terms(word) {
+				if !negations[t] && !isNumber(t) {
+					c.entities = append(c.entities, t)
+				}
+			}
+		}
+	}
+
+	c.negated = polarity(words(text)) > 0
+	return c
+}
+
+// Text returns the statement
+func (c Claim) Text() string {
+	return c.text
+}
+
+// Variants returns the neutral, affirming and negating queries of the claim,
+// skipping duplicates
+func (c Claim) Variants() []Variant {
+	neutral := strings.Join(c.terms, " ")
+	if neutral == "" {
+		neutral = c.text
+	}
+	negating := negate(c.text)
+	if negating == "" {
+		negating = c.text + " myth"
+	}
+
+	var variants []Variant
+	seen := make(map[string]bool)
+	for _, v := range []Variant{
+		{Framing: FramingNeutral, Query: neutral},
+		{Framing: FramingAffirming, Query: c.text + " evidence"},
+		{Framing: FramingNegating, Query: negating},
+	} {
+		key := strings.ToLower(v.Query)
+		if !seen[key] {
+			seen[key] = true
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// Assess sorts a passage into likely supporting, contradicting or unrelated
+// evidence. Polarity and number cues are read from the sentence of the
+// passage sharing the most terms with the claim.
+func (c Claim) Assess(passage string) Assessment {
+	if len(c.terms) == 0 {
+		return Assessment{Stance: StanceUnrelated}
+	}
+
+	overlap := c.overlap(webpage.Terms(passage))
+	a := Assessment{Stance: StanceUnrelated, Overlap: math.Round(overlap*100) / 100}
+	if len(c.entities) > 0 && c.shared(webpage.Terms(passage), c.entities) == 0 {
+		a.Cues = append(a.Cues, "no shared entities")
+		return a
+	}
+	if overlap < minOverlap {
+		a.Cues = append(a.Cues, fmt.Sprintf("shares %d of %d claim terms", c.shared(webpage.Terms(passage), c.terms), len(c.terms)))
+		return a
+	}
+
+	sentence := c.bestSentence(passage)
+	sentenceWords := words(sentence)
+	support, contradict := 0, 0
+
+	// A sentence of the opposite polarity contradicts the claim, whether
+	// through a negation or a word calling the statement false
+	if flips := polarity(sentenceWords); (flips > 0) != c.negated {
+		contradict++
+		if found := polarityWords(sentenceWords); len(found) > 0 {
+			a.Cues = append(a.Cues, "opposite polarity: "+strings.Join(found, ", "))
+		} else {
+			a.Cues = append(a.Cues, "opposite polarity: no negation")
+		}
+	} else {
+		if found := matching(sentenceWords, confirmations); len(found) > 0 {
+			support++
+			a.Cues = append(a.Cues, "confirmation: "+strings.Join(found, ", "))
+		}
+		if c.overlap(webpage.Terms(sentence)) >= restateOverlap {
+			support++
+			a.Cues = append(a.Cues, "restates the claim")
+		}
+	}
+
+	// Numbers of the claim must appear where the sentence gives numbers. A
+	// sentence restating the claim with other figures contradicts it, so a
+	// mismatch outweighs a restatement.
+	if len(c.numbers) > 0 {
+		found := numbers(sentence)
+		if missing := difference(c.numbers, found); len(missing) == 0 {
+			support++
+			a.Cues = append(a.Cues, "numbers match: "+strings.Join(c.numbers, ", "))
+		} else if len(found) > 0 && len(difference(found, c.numbers)) > 0 {
+			contradict += 2
+			a.Cues = append(a.Cues, fmt.Sprintf("numeric mismatch: %s vs %s", strings.Join(found, ", "), strings.Join(missing, ", ")))
+		}
+	}
+
+	switch {
+	case support > contradict:
+		a.Stance = StanceSupporting
+	case contradict > support:
+		a.Stance = StanceContradicting
+	case support > 0:
+		a.Cues = append(a.Cues, "conflicting cues")
+	}
+	return a
+}
+
+// overlap returns the share of claim terms among terms
+func (c Claim) overlap(terms []string) float64 {
+	return float64(c.shared(terms, c.terms)) / float64(len(c.terms))
+}
+
+// shared counts the distinct wanted terms present in terms
+func (c Claim) shared(terms, wanted []string) int {
+	present := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		present[t] = true
+	}
+	count := 0
+	seen := make(map[string]bool)
+	for _, t := range wanted {
+		if present[t] && !seen[t] {
+			count++
+		}
+		seen[t] = true
+	}
+	return count
+}
+
+// bestSentence returns the sentence of passage sharing the most terms with
+// the claim, the first one on ties
+func (c Claim) bestSentence(passage string) string {
+	best, bestCount := passage, -1
+	for _, s := range sentencePattern.Split(passage, -1) {
+		if count := c.shared(webpage.Terms(s), c.terms); count > bestCount {
+			best, bestCount = s, count
+		}
+	}
+	return best
+}
+
+// words splits text into lowercase words, keeping apostrophes
+func words(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
+
+// isNegation reports whether word negates its sentence
+func isNegation(word string) bool {
+	return negations[word] || strings.HasSuffix(word, "n't")
+}
+
+// polarity counts the negations and refutations of a sentence modulo two:
+// 1 for a negated sentence, 0 for a positive one
+func polarity(words []string) int {
+	return len(polarityWords(words)) % 2
+}
+
+// polarityWords returns the negations and refutations of a sentence
+func polarityWords(words []string) []string {
+	var found []string
+	for _, w := range words {
+		if isNegation(w) || refutations[w] {
+			found = append(found, w)
+		}
+	}
+	return found
+}
+
+// matching returns the distinct words found in set
+func matching(words []string, set map[string]bool) []string {
+	var found []string
+	seen := make(map[string]bool)
+	for _, w := range words {
+		if set[w] && !seen[w] {
+			seen[w] = true
+			found = append(found, w)
+		}
+	}
+	return found
+}
+
+// negate returns the statement with the polarity of its first auxiliary
+// flipped, or "" when it has none
+func negate(text string) string {
+	fields := strings.Fields(text)
+	for i, field := range fields {
+		word := strings.ReplaceAll(strings.ToLower(field), "’", "'")
+		if positive, ok := contractions[word]; ok {
+			fields[i] = positive
+			return strings.Join(fields, " ")
+		}
+		if !auxiliaries[word] {
+			continue
+		}
+		if i+1 < len(fields) && isNegation(strings.ToLower(fields[i+1])) {
+			return strings.Join(append(fields[:i+1:i+1], fields[i+2:]...), " ")
+		}
+		return strings.Join(append(fields[:i+1:i+1], append([]string{"not"}, fields[i+1:]...)...), " ")
+	}
+	return ""
+}
+
+// numbers returns the distinct numbers of text without thousands separators
+func numbers(text string) []string {
+	var found []string
+	seen := make(map[string]bool)
+	for _, n := range numberPattern.FindAllString(text, -1) {
+		n = normalizeNumber(n)
+		if !seen[n] {
+			seen[n] = true
+			found = append(found, n)
+		}
+	}
+	return found
+}
+
+// normalizeNumber drops thousands separators, keeping a trailing decimal part
+func normalizeNumber(n string) string {
+	if i := strings.LastIndexAny(n, ".,"); i >= 0 && len(n)-i-1 != 3 {
+		return strings.ReplaceAll(n[:i], ",", "") + "." + n[i+1:]
+	}
+	return strings.ReplaceAll(n, ",", "")
+}
+
+// difference returns the values of a missing from b
+func difference(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, v := range b {
+		present[v] = true
+	}
+	var missing []string
+	for _, v := range a {
+		if !present[v] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// isNumber reports whether a term is made of digits
+func isNumber(t string) bool {
+	for _, r := range t {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return t != ""
+}
@@ -0,0 +1,69 @@
+package claims_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/claims"
+)
+
+var _ = Describe("Claim", func() {
+	Describe("Variants", func() {
+		It("should derive neutral, affirming and negating queries", func() {
+			claim := claims.Parse("The Great Wall of China is visible from space.")
+			Expect(claim.Text()).To(Equal("The Great Wall of China is visible from space"))
+			Expect(claim.Variants()).To(Equal([]claims.Variant{
+				{Framing: claims.FramingNeutral, Query: "great wall china visible space"},
+				{Framing: claims.FramingAffirming, Query: "The Great Wall of China is visible from space evidence"},
+				{Framing: claims.FramingNegating, Query: "The Great Wall of China is not visible from space"},
+			}))
+		})
+
+		It("should drop the negation of a negated claim", func() {
+			Expect(claims.Parse("Goldfish don't have a three-second memory").Variants()[2].Query).
+				To(Equal("Goldfish do have a three-second memory"))
+			Expect(claims.Parse("Lightning never strikes twice").Variants()[2].Query).
+				To(Equal("Lightning never strikes twice myth"))
+		})
+	})
+
+	Describe("Assess", func() {
+		claim := claims.Parse("Mount Everest is 8,848 metres tall")
+
+		It("should support a passage restating the claim", func() {
+			a := claim.Assess("Surveyors confirmed that Mount Everest is 8848 metres tall. The summit is often crowded.")
+			Expect(a.Stance).To(Equal(claims.StanceSupporting))
+			Expect(a.Cues).To(ContainElements("confirmation: confirmed", "restates the claim", "numbers match: 8848"))
+			Expect(a.Overlap).To(Equal(1.0))
+		})
+
+		It("should contradict a passage with other numbers", func() {
+			a := claim.Assess("A 2020 survey measured Mount Everest at 8,849 metres, so it is 8,849 metres tall.")
+			Expect(a.Stance).To(Equal(claims.StanceContradicting))
+			Expect(a.Cues).To(ContainElement(HavePrefix("numeric mismatch: ")))
+		})
+
+		It("should contradict a passage of the opposite polarity", func() {
+			a := claims.Parse("The Great Wall of China is visible from space").
+				Assess("It is a myth that the Great Wall of China is visible from space with the naked eye.")
+			Expect(a.Stance).To(Equal(claims.StanceContradicting))
+			Expect(a.Cues).To(ContainElement("opposite polarity: myth"))
+		})
+
+		It("should support a passage sharing the polarity of a negated claim", func() {
+			a := claims.Parse("The Great Wall of China is not visible from space").
+				Assess("Astronauts report the Great Wall of China is not visible from space.")
+			Expect(a.Stance).To(Equal(claims.StanceSupporting))
+		})
+
+		It("should leave passages about something else unrelated", func() {
+			a := claim.Assess("Kilimanjaro is the highest mountain in Africa.")
+			Expect(a.Stance).To(Equal(claims.StanceUnrelated))
+			Expect(a.Cues).To(Equal([]string{"no shared entities"}))
+
+			a = claim.Assess("Everest attracts climbers every spring.")
+			Expect(a.Stance).To(Equal(claims.StanceUnrelated))
+			Expect(a.Cues).To(Equal([]string{"shares 1 of 4 claim terms"}))
+		})
+	})
+})
@@ -0,0 +1,204 @@
+// Package compare measures how the result lists of several variants of one
+// search differ: the results they share, the results unique to each, rank
+// changes and the similarity of their domains. Results are matched by
+// normalized URL.
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Set is the result list of one variant, in rank order
+type Set struct {
+	Label   string
+	Results []searxng.SearchResult
+}
+
+// Ranked is a result with its rank in each variant, 0 where it is absent
+type Ranked struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Ranks []int  `json:"ranks"`
+}
+
+// Unique is a result only one variant returned
+type Unique struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Rank  int    `json:"rank"`
+}
+
+// Variant summarizes the results of one variant
+type Variant struct {
+	Label   string `json:"label"`
+	Results int    `json:"results"`
+	Domains int    `json:"domains"`
+	// Unique lists the results no other variant returned, in rank order
+	Unique []Unique `json:"unique"`
+}
+
+// RankChange is a result two variants returned at different ranks
+type RankChange struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	// Delta is From minus To: positive when the result ranks higher in the second variant
+	Delta int `json:"delta"`
+}
+
+// Pair compares the results of two variants
+type Pair struct {
+	A      string `json:"a"`
+	B      string `json:"b"`
+	Shared int    `json:"shared"`
+	// URLJaccard is the Jaccard similarity of the normalized URLs of both variants
+	URLJaccard float64 `json:"url_jaccard"`
+	// DomainJaccard is the Jaccard similarity of the domains of both variants
+	DomainJaccard float64 `json:"domain_jaccard"`
+	// RankChanges lists the shared results whose rank differs, largest moves first
+	RankChanges []RankChange `json:"rank_changes"`
+}
+
+// Comparison is the comparison of the result lists of several variants
+type Comparison struct {
+	Variants []Variant `json:"variants"`
+	// Common lists the results every variant returned, in the order of the first variant
+	Common []Ranked `json:"common"`
+	// Pairs compares every two variants, in variant order
+	Pairs []Pair `json:"pairs"`
+}
+
+// ranking is a set deduplicated by normalized URL
+type ranking struct {
+	label   string
+	keys    []string
+	results map[string]searxng.SearchResult
+	ranks   map[string]int
+	domains map[string]bool
+}
+
+// Compare compares the result lists of sets. Within a set, a result repeating
+// the URL of a higher one is dropped and ranks count from 1 without it.
+func Compare(sets []Set) Comparison {
+	rankings := make([]ranking, len(sets))
+	for i, set := range sets {
+		rankings[i] = rank(set)
+	}
+
+	c := Comparison{Variants: make([]Variant, len(sets)), Common: []Ranked{}, Pairs: []Pair{}}
+	for i, r := range rankings {
+		v := Variant{Label: r.label, Results: len(r.keys), Domains: len(r.domains), Unique: []Unique{}}
+		for _, key := range r.keys {
+			if count(rankings, key) == 1 {
+				v.Unique = append(v.Unique, Unique{URL: r.results[key].URL, Title: r.results[key].Title, Rank: r.ranks[key]})
+			}
+		}
+		c.Variants[i] = v
+	}
+
+	if len(rankings) > 0 {
+		for _, key := range rankings[0].keys {
+			if count(rankings, key) < len(rankings) {
+				continue
+			}
+			ranked := Ranked{URL: rankings[0].results[key].URL, Title: rankings[0].results[key].Title, Ranks: make([]int, len(rankings))}
+			for i, r := range rankings {
+				ranked.Ranks[i] = r.ranks[key]
+			}
+			c.Common = append(c.Common, ranked)
+		}
+	}
+
+	for i := range rankings {
+		for j := i + 1; j < len(rankings); j++ {
+			c.Pairs = append(c.Pairs, comparePair(rankings[i], rankings[j]))
+		}
+	}
+	return c
+}
+
+// rank deduplicates a set and numbers its results
+func rank(set Set) ranking {
+	r := ranking{
+		label:   set.Label,
+		results: make(map[string]searxng.SearchResult),
+		ranks:   make(map[string]int),
+		domains: make(map[string]bool),
+	}
+	for _, result := range set.Results {
+		if result.URL == "" {
+			continue
+		}
+		key := searxng.NormalizeURL(result.URL)
+		if _, ok := r.ranks[key]; ok {
+			continue
+		}
+		r.keys = append(r.keys, key)
+		r.results[key] = result
+		r.ranks[key] = len(r.keys)
+		if domain := searxng.Domain(result.URL); domain != "" {
+			r.domains[domain] = true
+		}
+	}
+	return r
+}
+
+// count returns the number of rankings holding key
+func count(rankings []ranking, key string) int {
+	n := 0
+	for _, r := range rankings {
+		if _, ok := r.ranks[key]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// comparePair compares two rankings
+func comparePair(a, b ranking) Pair {
+	p := Pair{A: a.label, B: b.label, RankChanges: []RankChange{}}
+	for _, key := range a.keys {
+		to, ok := b.ranks[key]
+		if !ok {
+			continue
+		}
+		p.Shared++
+		if from := a.ranks[key]; from != to {
+			p.RankChanges = append(p.RankChanges, RankChange{URL: a.results[key].URL, Title: a.results[key].Title, From: from, To: to, Delta: from - to})
+		}
+	}
+	sort.SliceStable(p.RankChanges, func(i, j int) bool {
+		return abs(p.RankChanges[i].Delta) > abs(p.RankChanges[j].Delta)
+	})
+
+	p.URLJaccard = jaccard(p.Shared, len(a.keys)+len(b.keys)-p.Shared)
+	sharedDomains := 0
+	for domain := range a.domains {
+		if b.domains[domain] {
+			sharedDomains++
+		}
+	}
+	p.DomainJaccard = jaccard(sharedDomains, len(a.domains)+len(b.domains)-sharedDomains)
+	return p
+}
+
+// jaccard returns the size of an intersection over the size of the union,
+// rounded to three decimals, and 0 when both sets are empty
+func jaccard(intersection, union int) float64 {
+	if union == 0 {
+		return 0
+	}
+	return math.Round(float64(intersection)/float64(union)*1000) / 1000
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
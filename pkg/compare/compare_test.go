@@ -0,0 +1,67 @@
+package compare_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/compare"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Compare", func() {
+	english := compare.Set{Label: "en", Results: []searxng.SearchResult{
+		{URL: "https://example.com/a", Title: "A"},
+		{URL: "https://www.example.com/a/", Title: "A (duplicate)"},
+		{URL: "https://news.org/b", Title: "B"},
+		{URL: "https://blog.net/c", Title: "C"},
+		{URL: "", Title: "No URL"},
+	}}
+	french := compare.Set{Label: "fr", Results: []searxng.SearchResult{
+		{URL: "https://blog.net/c?utm_source=x", Title: "C"},
+		{URL: "http://example.com/a", Title: "A"},
+		{URL: "https://exemple.fr/d", Title: "D"},
+	}}
+
+	It("should report shared and unique results by normalized URL", func() {
+		c := compare.Compare([]compare.Set{english, french})
+
+		Expect(c.Variants).To(HaveLen(2))
+		Expect(c.Variants[0].Results).To(Equal(3))
+		Expect(c.Variants[0].Domains).To(Equal(3))
+		Expect(c.Variants[0].Unique).To(Equal([]compare.Unique{{URL: "https://news.org/b", Title: "B", Rank: 2}}))
+		Expect(c.Variants[1].Unique).To(Equal([]compare.Unique{{URL: "https://exemple.fr/d", Title: "D", Rank: 3}}))
+
+		Expect(c.Common).To(Equal([]compare.Ranked{
+			{URL: "https://example.com/a", Title: "A", Ranks: []int{1, 2}},
+			{URL: "https://blog.net/c", Title: "C", Ranks: []int{3, 1}},
+		}))
+	})
+
+	It("should report rank changes and Jaccard similarities per pair", func() {
+		c := compare.Compare([]compare.Set{english, french})
+
+		Expect(c.Pairs).To(HaveLen(1))
+		pair := c.Pairs[0]
+		Expect(pair.A).To(Equal("en"))
+		Expect(pair.B).To(Equal("fr"))
+		Expect(pair.Shared).To(Equal(2))
+		Expect(pair.URLJaccard).To(Equal(0.5))
+		Expect(pair.DomainJaccard).To(Equal(0.5))
+		Expect(pair.RankChanges).To(Equal([]compare.RankChange{
+			{URL: "https://blog.net/c", Title: "C", From: 3, To: 1, Delta: 2},
+			{URL: "https://example.com/a", Title: "A", From: 1, To: 2, Delta: -1},
+		}))
+	})
+
+	It("should compare every pair of three variants", func() {
+		empty := compare.Set{Label: "empty"}
+		c := compare.Compare([]compare.Set{english, french, empty})
+
+		Expect(c.Pairs).To(HaveLen(3))
+		Expect(c.Pairs[2].A).To(Equal("fr"))
+		Expect(c.Pairs[2].B).To(Equal("empty"))
+		Expect(c.Pairs[2].URLJaccard).To(BeZero())
+		Expect(c.Common).To(BeEmpty())
+		Expect(c.Variants[0].Unique).To(HaveLen(1))
+	})
+})
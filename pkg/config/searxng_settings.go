@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Settings holds the parts of the SearXNG settings.yml used by the MCP server
+type Settings struct {
+	// DOIResolvers maps resolver names to base URLs a DOI is appended to
+	DOIResolvers map[string]string `yaml:"doi_resolvers"`
+	// DefaultDOIResolver names the resolver used for open-access links
+	DefaultDOIResolver string `yaml:"default_doi_resolver"`
+}
+
+// ParseSettings parses a SearXNG settings.yml document
+func ParseSettings(data []byte) (*Settings, error) {
+	var settings Settings
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// LoadSettings reads settings.yml from the configuration directory,
+// falling back to the embedded default settings when it does not exist
+func LoadSettings() (*Settings, error) {
+	path, err := Path("settings.yml")
+	if err != nil {
+		return ParseSettings([]byte(DefaultSettings))
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ParseSettings([]byte(DefaultSettings))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	settings, err := ParseSettings(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, nil
+}
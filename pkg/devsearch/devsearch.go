@@ -0,0 +1,256 @@
+// Package devsearch classifies developer search results by source type and
+// extracts the structured fields engines report for them.
+package devsearch
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Kind is the source type of a developer search result
+type Kind string
+
+const (
+	KindRepository Kind = "repository"
+	KindPackage    Kind = "package"
+	KindQA         Kind = "qa"
+	KindDocs       Kind = "docs"
+	KindOther      Kind = "other"
+)
+
+// Kinds lists the source types in display order
+var Kinds = []Kind{KindRepository, KindPackage, KindQA, KindDocs, KindOther}
+
+// engineKinds maps the it-category engines of settings.yml to source types
+var engineKinds = map[string]Kind{
+	"github":    KindRepository,
+	"gitlab":    KindRepository,
+	"codeberg":  KindRepository,
+	"bitbucket": KindRepository,
+	"sourcehut": KindRepository,
+	"gitea":     KindRepository,
+
+	"pypi":                  KindPackage,
+	"npm":                   KindPackage,
+	"docker hub":            KindPackage,
+	"crates.io":             KindPackage,
+	"pkg.go.dev":            KindPackage,
+	"packagist":             KindPackage,
+	"pub.dev":               KindPackage,
+	"rubygems":              KindPackage,
+	"anaconda":              KindPackage,
+	"hoogle":                KindPackage,
+	"metacpan":              KindPackage,
+	"hex":                   KindPackage,
+	"nuget":                 KindPackage,
+	"alpine linux packages": KindPackage,
+
+	"stackoverflow":     KindQA,
+	"askubuntu":         KindQA,
+	"superuser":         KindQA,
+	"discuss.python":    KindQA,
+	"caddy.community":   KindQA,
+	"pi-hole.community": KindQA,
+	"lobste.rs":         KindQA,
+
+	"mdn":                     KindDocs,
+	"mankier":                 KindDocs,
+	"arch linux wiki":         KindDocs,
+	"gentoo":                  KindDocs,
+	"nixos wiki":              KindDocs,
+	"free software directory": KindDocs,
+	"microsoft learn":         KindDocs,
+}
+
+// hostKinds classifies results from engines not listed in engineKinds
+var hostKinds = map[string]Kind{
+	"github.com":            KindRepository,
+	"gitlab.com":            KindRepository,
+	"codeberg.org":          KindRepository,
+	"bitbucket.org":         KindRepository,
+	"sr.ht":                 KindRepository,
+	"pypi.org":              KindPackage,
+	"npmjs.com":             KindPackage,
+	"hub.docker.com":        KindPackage,
+	"crates.io":             KindPackage,
+	"pkg.go.dev":            KindPackage,
+	"packagist.org":         KindPackage,
+	"rubygems.org":          KindPackage,
+	"stackoverflow.com":     KindQA,
+	"superuser.com":         KindQA,
+	"serverfault.com":       KindQA,
+	"askubuntu.com":         KindQA,
+	"developer.mozilla.org": KindDocs,
+	"docs.python.org":       KindDocs,
+	"docs.rs":               KindDocs,
+	"learn.microsoft.com":   KindDocs,
+	"wiki.archlinux.org":    KindDocs,
+}
+
+// Result is a developer search result with its structured fields
+type Result struct {
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Engine      string     `json:"engine"`
+	Kind        Kind       `json:"kind"`
+	Summary     string     `json:"summary,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Language    string     `json:"language,omitempty"`
+	Stars       *int64     `json:"stars,omitempty"`
+	Downloads   *int64     `json:"downloads,omitempty"`
+	License     string     `json:"license,omitempty"`
+	Homepage    string     `json:"homepage,omitempty"`
+	SourceURL   string     `json:"source_url,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Answered    *bool      `json:"answered,omitempty"`
+	Score       *int       `json:"score,omitempty"`
+	UpdatedDate *time.Time `json:"updated_date,omitempty"`
+}
+
+// Group holds the results of one source type
+type Group struct {
+	Kind    Kind     `json:"kind"`
+	Results []Result `json:"results"`
+}
+
+// Classify returns the source type of a search result
+func Classify(r searxng.SearchResult) Kind {
+	if kind, ok := engineKinds[strings.ToLower(r.Engine)]; ok {
+		return kind
+	}
+	domain := searxng.Domain(r.URL)
+	for host, kind := range hostKinds {
+		if domain == host || strings.HasSuffix(domain, "."+host) {
+			return kind
+		}
+	}
+	if r.PackageName != "" || r.Template == "packages.html" {
+		return KindPackage
+	}
+	return KindOther
+}
+
+// FromResult classifies a search result and extracts its structured fields
+func FromResult(r searxng.SearchResult) Result {
+	res := Result{
+		Title:     strings.TrimSpace(r.Title),
+		URL:       r.URL,
+		Engine:    r.Engine,
+		Kind:      Classify(r),
+		Summary:   strings.Join(strings.Fields(r.Content), " "),
+		Name:      r.PackageName,
+		Owner:     r.Maintainer,
+		Version:   string(r.Version),
+		License:   r.LicenseName,
+		Homepage:  r.Homepage,
+		SourceURL: r.SourceCodeURL,
+		Tags:      r.Tags,
+	}
+	if t, ok := r.PublishedTime(); ok {
+		res.UpdatedDate = &t
+	}
+
+	switch res.Kind {
+	case KindRepository:
+		// GitHub reports stars as popularity and the language in the content
+		if n, ok := parseCount(string(r.Popularity)); ok {
+			res.Stars = &n
+		}
+		res.Language = repositoryLanguage(r.Content)
+	case KindPackage:
+		if n, ok := parseCount(string(r.Popularity)); ok {
+			res.Downloads = &n
+		}
+	case KindQA:
+		parseQAContent(&res)
+	}
+
+	return res
+}
+
+// GroupResults classifies results and groups them by source type in display order,
+// omitting empty groups
+func GroupResults(results []searxng.SearchResult) []Group {
+	byKind := make(map[Kind][]Result)
+	for _, r := range results {
+		res := FromResult(r)
+		byKind[res.Kind] = append(byKind[res.Kind], res)
+	}
+
+	var groups []Group
+	for _, kind := range Kinds {
+		if len(byKind[kind]) > 0 {
+			groups = append(groups, Group{Kind: kind, Results: byKind[kind]})
+		}
+	}
+	return groups
+}
+
+// parseCount parses a count such as "1234" or "1,234"
+func parseCount(s string) (int64, bool) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0, false
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(f), true
+	}
+	return 0, false
+}
+
+// languages are programming languages recognized in repository summaries
+var languages = map[string]bool{
+	"c": true, "c#": true, "c++": true, "clojure": true, "css": true, "dart": true, "elixir": true,
+	"erlang": true, "go": true, "haskell": true, "html": true, "java": true, "javascript": true,
+	"julia": true, "jupyter notebook": true, "kotlin": true, "lua": true, "nix": true, "ocaml": true,
+	"perl": true, "php": true, "python": true, "r": true, "ruby": true, "rust": true, "scala": true,
+	"shell": true, "swift": true, "typescript": true, "vue": true, "zig": true,
+}
+
+// repositoryLanguage returns the language segment of a "language / description" summary
+func repositoryLanguage(content string) string {
+	for _, part := range strings.Split(content, " / ") {
+		part = strings.TrimSpace(part)
+		if languages[strings.ToLower(part)] {
+			return part
+		}
+	}
+	return ""
+}
+
+// qaScorePattern matches the score the stackexchange engine appends to its content
+var qaScorePattern = regexp.MustCompile(`//\s*score:\s*(-?\d+)`)
+
+// qaTagsPattern matches the leading "[tag, tag]" list of stackexchange content
+var qaTagsPattern = regexp.MustCompile(`^\[([^\]]*)\]`)
+
+// parseQAContent extracts tags, answered state and score from stackexchange
+// content of the form "[go, generics] author // is answered // score: 12"
+func parseQAContent(res *Result) {
+	if !strings.Contains(res.Summary, "//") {
+		return
+	}
+	if m := qaScorePattern.FindStringSubmatch(res.Summary); m != nil {
+		if score, err := strconv.Atoi(m[1]); err == nil {
+			res.Score = &score
+		}
+	}
+	answered := strings.Contains(res.Summary, "// is answered")
+	res.Answered = &answered
+	if m := qaTagsPattern.FindStringSubmatch(res.Summary); m != nil && len(res.Tags) == 0 {
+		for _, tag := range strings.Split(m[1], ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				res.Tags = append(res.Tags, tag)
+			}
+		}
+	}
+}
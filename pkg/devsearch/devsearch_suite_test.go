@@ -0,0 +1,13 @@
+package devsearch_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDevsearch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Devsearch Suite")
+}
@@ -0,0 +1,80 @@
+package devsearch_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/devsearch"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Devsearch", func() {
+	var results []searxng.SearchResult
+
+	BeforeEach(func() {
+		Expect(json.Unmarshal([]byte(`[
+			{"url": "https://github.com/golang/go", "title": "golang/go", "engine": "github",
+			 "template": "packages.html", "content": "Go / The Go programming language",
+			 "package_name": "go", "maintainer": "golang", "popularity": 125000,
+			 "license_name": "BSD 3-Clause", "tags": ["go", "language"], "publishedDate": "2026-10-01T09:00:00"},
+			{"url": "https://pypi.org/project/requests/", "title": "requests", "engine": "pypi",
+			 "package_name": "requests", "version": "2.32.3", "maintainer": "psf"},
+			{"url": "https://stackoverflow.com/q/1", "title": "How do generics work in Go?", "engine": "stackoverflow",
+			 "content": "[go, generics] gopher // is answered // score: 42"},
+			{"url": "https://stackoverflow.com/q/2", "title": "Unanswered question", "engine": "stackoverflow",
+			 "content": "[go] someone // score: -1"},
+			{"url": "https://developer.mozilla.org/en-US/docs/Web/API/fetch", "title": "fetch()", "engine": "mdn"},
+			{"url": "https://gitlab.com/foo/bar", "title": "foo/bar", "engine": "duckduckgo"},
+			{"url": "https://example.com/blog", "title": "A blog post", "engine": "bing"}
+		]`), &results)).To(Succeed())
+	})
+
+	It("should classify results by engine and host", func() {
+		kinds := make([]devsearch.Kind, len(results))
+		for i, r := range results {
+			kinds[i] = devsearch.Classify(r)
+		}
+		Expect(kinds).To(Equal([]devsearch.Kind{
+			devsearch.KindRepository, devsearch.KindPackage, devsearch.KindQA, devsearch.KindQA,
+			devsearch.KindDocs, devsearch.KindRepository, devsearch.KindOther,
+		}))
+	})
+
+	It("should extract repository fields", func() {
+		repo := devsearch.FromResult(results[0])
+		Expect(*repo.Stars).To(Equal(int64(125000)))
+		Expect(repo.Language).To(Equal("Go"))
+		Expect(repo.Owner).To(Equal("golang"))
+		Expect(repo.License).To(Equal("BSD 3-Clause"))
+		Expect(repo.UpdatedDate).NotTo(BeNil())
+	})
+
+	It("should extract package fields", func() {
+		pkg := devsearch.FromResult(results[1])
+		Expect(pkg.Name).To(Equal("requests"))
+		Expect(pkg.Version).To(Equal("2.32.3"))
+		Expect(pkg.Downloads).To(BeNil())
+	})
+
+	It("should extract Q&A fields", func() {
+		answered := devsearch.FromResult(results[2])
+		Expect(*answered.Answered).To(BeTrue())
+		Expect(*answered.Score).To(Equal(42))
+		Expect(answered.Tags).To(Equal([]string{"go", "generics"}))
+
+		open := devsearch.FromResult(results[3])
+		Expect(*open.Answered).To(BeFalse())
+		Expect(*open.Score).To(Equal(-1))
+	})
+
+	It("should group results in display order", func() {
+		groups := devsearch.GroupResults(results)
+		Expect(groups).To(HaveLen(5))
+		Expect(groups[0].Kind).To(Equal(devsearch.KindRepository))
+		Expect(groups[0].Results).To(HaveLen(2))
+		Expect(groups[2].Kind).To(Equal(devsearch.KindQA))
+		Expect(groups[4].Kind).To(Equal(devsearch.KindOther))
+	})
+})
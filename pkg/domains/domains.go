@@ -0,0 +1,264 @@
+// Package domains matches result URLs against domain allow and block lists
+package domains
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Pattern matches the host of a URL. A plain domain such as "example.com"
+// matches the domain and all of its subdomains; a pattern with "*" wildcards
+// such as "*.example.com" or "seo-*.net" matches hosts as a whole.
+type Pattern struct {
+	pattern string
+	glob    bool
+}
+
+// ParsePattern parses a domain pattern. URLs are reduced to their host, and
+// "www." and a leading "." are dropped.
+func ParsePattern(s string) (Pattern, error) {
+	p := strings.ToLower(strings.TrimSpace(s))
+	if _, rest, ok := strings.Cut(p, "://"); ok {
+		p = rest
+	}
+	p, _, _ = strings.Cut(p, "/")
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "."), "www.")
+	if host, port, ok := strings.Cut(p, ":"); ok && port != "" && !strings.ContainsFunc(port, isNotDigit) {
+		p = host
+	}
+
+	if p == "" || strings.Trim(p, "*.") == "" {
+		return Pattern{}, fmt.Errorf("invalid domain pattern %q", s)
+	}
+	if strings.ContainsFunc(p, func(r rune) bool { return !isHostRune(r) && r != '*' }) {
+		return Pattern{}, fmt.Errorf("invalid domain pattern %q", s)
+	}
+	return Pattern{pattern: p, glob: strings.Contains(p, "*")}, nil
+}
+
+// Match reports whether host, or the host of a URL, matches the pattern
+func (p Pattern) Match(host string) bool {
+	host = Host(host)
+	if host == "" {
+		return false
+	}
+	if p.glob {
+		ok, _ := path.Match(p.pattern, host)
+		return ok
+	}
+	return host == p.pattern || strings.HasSuffix(host, "."+p.pattern)
+}
+
+// Site returns the domain usable in a site: search operator, which only plain domains have
+func (p Pattern) Site() (string, bool) {
+	return p.pattern, !p.glob
+}
+
+// String returns the normalized pattern
+func (p Pattern) String() string {
+	return p.pattern
+}
+
+// List is a list of domain patterns
+type List []Pattern
+
+// ParseList parses domain patterns, skipping empty ones
+func ParseList(patterns []string) (List, error) {
+	var list List
+	for _, s := range patterns {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		p, err := ParsePattern(s)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// LoadList reads a list file with one pattern per line. Blank lines and text
+// after "#" are ignored. A missing file is an empty list.
+func LoadList(path string) (List, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read domain list: %w", err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if _, err := ParsePattern(text); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		patterns = append(patterns, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read domain list: %w", err)
+	}
+	return ParseList(patterns)
+}
+
+// Match reports whether any pattern matches host
+func (l List) Match(host string) bool {
+	return slices.ContainsFunc(l, func(p Pattern) bool { return p.Match(host) })
+}
+
+// Policy decides which result URLs are kept
+type Policy struct {
+	// Allow keeps only the URLs it matches, when not empty
+	Allow List
+	// Block drops the URLs it matches, even when Allow matches them too
+	Block List
+}
+
+// IsZero reports whether the policy keeps every URL
+func (p Policy) IsZero() bool {
+	return len(p.Allow) == 0 && len(p.Block) == 0
+}
+
+// Allows reports whether the policy keeps rawURL
+func (p Policy) Allows(rawURL string) bool {
+	host := Host(rawURL)
+	if host != "" && p.Block.Match(host) {
+		return false
+	}
+	return len(p.Allow) == 0 || p.Allow.Match(host)
+}
+
+// Filter returns the results the policy keeps
+func (p Policy) Filter(results []searxng.SearchResult) []searxng.SearchResult {
+	if p.IsZero() {
+		return results
+	}
+	kept := []searxng.SearchResult{}
+	for _, r := range results {
+		if p.Allows(r.URL) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// siteOperatorCategories are the categories whose engines understand site: operators
+var siteOperatorCategories = []searxng.Category{
+	searxng.CategoryGeneral, searxng.CategoryNews, searxng.CategoryScience, searxng.CategoryIT,
+}
+
+// SiteOperators returns the site: and -site: operators that narrow a query in
+// categories to the policy's plain domains, or an empty string when the
+// categories' engines do not understand them
+func (p Policy) SiteOperators(categories []searxng.Category) string {
+	for _, c := range categories {
+		if !slices.Contains(siteOperatorCategories, c) {
+			return ""
+		}
+	}
+
+	var include, operators []string
+	for _, pattern := range p.Allow {
+		if site, ok := pattern.Site(); ok {
+			include = append(include, "site:"+site)
+		}
+	}
+	// A wildcard allow pattern cannot be expressed, so narrowing to the plain ones would hide its matches
+	if len(include) > 0 && len(include) == len(p.Allow) {
+		operators = append(operators, strings.Join(include, " OR "))
+	}
+	for _, pattern := range p.Block {
+		if site, ok := pattern.Site(); ok {
+			operators = append(operators, "-site:"+site)
+		}
+	}
+	return strings.Join(operators, " ")
+}
+
+// Host returns the lowercase host of a URL or host name, without "www." and port
+func Host(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "//" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(u.Hostname()), "."), "www.")
+}
+
+// Client wraps a searxng.Client, dropping results the policy does not allow
+type Client struct {
+	client        searxng.Client
+	policy        Policy
+	siteOperators bool
+}
+
+// NewClient creates a Client enforcing policy on every response. With
+// siteOperators set, the policy is also added to queries as site: operators
+// so that engines return more of the allowed results.
+func NewClient(client searxng.Client, policy Policy, siteOperators bool) *Client {
+	return &Client{
+		client:        client,
+		policy:        policy,
+		siteOperators: siteOperators,
+	}
+}
+
+// Search performs a search and filters its results
+func (c *Client) Search(ctx context.Context, req searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	query := req.Query
+	if c.siteOperators {
+		categories := req.Category
+		if len(categories) == 0 {
+			categories = []searxng.Category{searxng.CategoryGeneral}
+		}
+		if operators := c.policy.SiteOperators(categories); operators != "" {
+			req.Query += " " + operators
+		}
+	}
+
+	resp, err := c.client.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the query as asked, without the operators added to it
+	filtered := *resp
+	if filtered.Query == req.Query {
+		filtered.Query = query
+	}
+	filtered.Results = c.policy.Filter(resp.Results)
+	return &filtered, nil
+}
+
+// isHostRune reports whether r may appear in a host name
+func isHostRune(r rune) bool {
+	return r == '.' || r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127
+}
+
+// isNotDigit reports whether r is not an ASCII digit
+func isNotDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
@@ -0,0 +1,164 @@
+package domains_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/domains"
+	"searxng-mcp/pkg/searxng"
+)
+
+// stubClient records the last request and returns fixed results
+type stubClient struct {
+	request searxng.SearchRequest
+	results []searxng.SearchResult
+}
+
+func (c *stubClient) Search(ctx context.Context, req searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	c.request = req
+	return &searxng.SearchResponse{Query: req.Query, Results: c.results}, nil
+}
+
+// mustList parses patterns, failing the spec on error
+func mustList(patterns ...string) domains.List {
+	list, err := domains.ParseList(patterns)
+	Expect(err).NotTo(HaveOccurred())
+	return list
+}
+
+var _ = Describe("Domains", func() {
+	Describe("ParsePattern", func() {
+		It("should normalize URLs and hosts", func() {
+			for _, s := range []string{"Example.com", "https://www.example.com/path", ".example.com", "example.com:8080"} {
+				p, err := domains.ParsePattern(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p.String()).To(Equal("example.com"), s)
+			}
+		})
+
+		It("should reject invalid patterns", func() {
+			for _, s := range []string{"", "*", "*.", "exa mple.com", "example.com?q=1"} {
+				_, err := domains.ParsePattern(s)
+				Expect(err).To(HaveOccurred(), s)
+			}
+		})
+	})
+
+	Describe("Pattern.Match", func() {
+		It("should match a plain domain and its subdomains", func() {
+			p, _ := domains.ParsePattern("example.com")
+			Expect(p.Match("example.com")).To(BeTrue())
+			Expect(p.Match("https://docs.example.com/page")).To(BeTrue())
+			Expect(p.Match("www.example.com")).To(BeTrue())
+			Expect(p.Match("notexample.com")).To(BeFalse())
+			Expect(p.Match("example.com.evil.net")).To(BeFalse())
+		})
+
+		It("should match wildcards against the whole host", func() {
+			p, _ := domains.ParsePattern("*.example.com")
+			Expect(p.Match("docs.example.com")).To(BeTrue())
+			Expect(p.Match("example.com")).To(BeFalse())
+
+			p, _ = domains.ParsePattern("seo-*.net")
+			Expect(p.Match("https://seo-spam.net/")).To(BeTrue())
+			Expect(p.Match("seo.net")).To(BeFalse())
+		})
+	})
+
+	Describe("Policy", func() {
+		It("should let the block list win over the allow list", func() {
+			policy := domains.Policy{Allow: mustList("example.com"), Block: mustList("ads.example.com")}
+			Expect(policy.Allows("https://example.com/a")).To(BeTrue())
+			Expect(policy.Allows("https://ads.example.com/a")).To(BeFalse())
+			Expect(policy.Allows("https://other.org/a")).To(BeFalse())
+		})
+
+		It("should filter results", func() {
+			policy := domains.Policy{Block: mustList("*.spam.net")}
+			results := policy.Filter([]searxng.SearchResult{
+				{URL: "https://a.spam.net/1"},
+				{URL: "https://example.com/2"},
+			})
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].URL).To(Equal("https://example.com/2"))
+		})
+
+		It("should build site operators for plain domains", func() {
+			policy := domains.Policy{Allow: mustList("go.dev", "golang.org"), Block: mustList("medium.com", "*.spam.net")}
+			Expect(policy.SiteOperators([]searxng.Category{searxng.CategoryGeneral})).To(Equal("site:go.dev OR site:golang.org -site:medium.com"))
+		})
+
+		It("should not narrow to plain domains when a wildcard is allowed", func() {
+			policy := domains.Policy{Allow: mustList("go.dev", "*.golang.org")}
+			Expect(policy.SiteOperators([]searxng.Category{searxng.CategoryGeneral})).To(BeEmpty())
+		})
+
+		It("should not build operators for categories whose engines ignore them", func() {
+			policy := domains.Policy{Allow: mustList("youtube.com")}
+			Expect(policy.SiteOperators([]searxng.Category{searxng.CategoryVideos})).To(BeEmpty())
+		})
+	})
+
+	Describe("LoadList", func() {
+		It("should read patterns and skip comments", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "blocklist.txt")
+			Expect(os.WriteFile(path, []byte("# content farms\nspam.net\n\n*.seo.com  # wildcard\n"), 0644)).To(Succeed())
+
+			list, err := domains.LoadList(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(HaveLen(2))
+			Expect(list.Match("x.seo.com")).To(BeTrue())
+		})
+
+		It("should treat a missing file as an empty list", func() {
+			list, err := domains.LoadList(filepath.Join(GinkgoT().TempDir(), "missing.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(BeEmpty())
+		})
+
+		It("should report the line of an invalid pattern", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "allowlist.txt")
+			Expect(os.WriteFile(path, []byte("example.com\nbad domain\n"), 0644)).To(Succeed())
+
+			_, err := domains.LoadList(path)
+			Expect(err).To(MatchError(ContainSubstring("allowlist.txt:2:")))
+		})
+	})
+
+	Describe("Client", func() {
+		var stub *stubClient
+
+		BeforeEach(func() {
+			stub = &stubClient{results: []searxng.SearchResult{
+				{URL: "https://go.dev/doc"},
+				{URL: "https://medium.com/post"},
+			}}
+		})
+
+		It("should add site operators and report the original query", func() {
+			policy := domains.Policy{Block: mustList("medium.com")}
+			client := domains.NewClient(stub, policy, true)
+
+			resp, err := client.Search(context.Background(), searxng.SearchRequest{Query: "generics"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stub.request.Query).To(Equal("generics -site:medium.com"))
+			Expect(resp.Query).To(Equal("generics"))
+			Expect(resp.Results).To(HaveLen(1))
+		})
+
+		It("should only filter without site operators", func() {
+			policy := domains.Policy{Allow: mustList("go.dev")}
+			client := domains.NewClient(stub, policy, false)
+
+			resp, err := client.Search(context.Background(), searxng.SearchRequest{Query: "generics"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stub.request.Query).To(Equal("generics"))
+			Expect(resp.Results).To(HaveLen(1))
+			Expect(resp.Results[0].URL).To(Equal("https://go.dev/doc"))
+		})
+	})
+})
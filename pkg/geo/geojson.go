@@ -0,0 +1,183 @@
+// Package geo converts map search results to GeoJSON and measures distances.
+package geo
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// earthRadiusKm is the mean Earth radius used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// Point is a WGS84 position
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// FeatureCollection is a GeoJSON feature collection
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature with a point geometry
+type Feature struct {
+	Type       string     `json:"type"`
+	Geometry   Geometry   `json:"geometry"`
+	BBox       []float64  `json:"bbox,omitempty"`
+	Properties Properties `json:"properties"`
+}
+
+// Geometry is a GeoJSON point geometry; coordinates are [longitude, latitude]
+type Geometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// Properties describes the place behind a feature
+type Properties struct {
+	Name       string            `json:"name"`
+	Address    string            `json:"address,omitempty"`
+	Components map[string]string `json:"address_components,omitempty"`
+	OSMType    string            `json:"osm_type,omitempty"`
+	OSMID      string            `json:"osm_id,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Engine     string            `json:"engine,omitempty"`
+	DistanceKm *float64          `json:"distance_km,omitempty"`
+}
+
+// NewFeatureCollection builds a feature collection
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// FromResult converts a map search result to a feature.
+// It reports false when the result has no coordinates.
+func FromResult(r searxng.SearchResult) (Feature, bool) {
+	if r.Latitude == nil || r.Longitude == nil {
+		return Feature{}, false
+	}
+	lat, lon := float64(*r.Latitude), float64(*r.Longitude)
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return Feature{}, false
+	}
+
+	f := Feature{
+		Type:     "Feature",
+		Geometry: Geometry{Type: "Point", Coordinates: [2]float64{lon, lat}},
+		Properties: Properties{
+			Name:   strings.TrimSpace(r.Title),
+			URL:    r.URL,
+			Engine: r.Engine,
+		},
+	}
+
+	// SearXNG bounding boxes are [south, north, west, east]; GeoJSON wants [west, south, east, north]
+	if len(r.BoundingBox) == 4 {
+		box := r.BoundingBox
+		f.BBox = []float64{float64(box[2]), float64(box[0]), float64(box[3]), float64(box[1])}
+	}
+
+	if r.Address != nil {
+		if r.Address.Name != "" {
+			f.Properties.Name = r.Address.Name
+		}
+		f.Properties.Components = addressComponents(r.Address)
+		f.Properties.Address = formatAddress(r.Address)
+	}
+	if r.OSM != nil {
+		f.Properties.OSMType = r.OSM.Type
+		f.Properties.OSMID = string(r.OSM.ID)
+	}
+
+	return f, true
+}
+
+// Position returns the position of a feature
+func (f Feature) Position() Point {
+	return Point{Lat: f.Geometry.Coordinates[1], Lon: f.Geometry.Coordinates[0]}
+}
+
+// DistanceKm returns the great-circle distance between two points in kilometers
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// WithinDistance annotates features with their distance from origin and keeps
+// those within maxKm; a non-positive maxKm keeps all features
+func WithinDistance(features []Feature, origin Point, maxKm float64) []Feature {
+	kept := features[:0:0]
+	for _, f := range features {
+		d := math.Round(DistanceKm(origin, f.Position())*1000) / 1000
+		if maxKm > 0 && d > maxKm {
+			continue
+		}
+		f.Properties.DistanceKm = &d
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// SortByDistance orders features by their annotated distance, nearest first.
+// Features without a distance keep their relative order at the end.
+func SortByDistance(features []Feature) {
+	sort.SliceStable(features, func(i, j int) bool {
+		a, b := features[i].Properties.DistanceKm, features[j].Properties.DistanceKm
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a < *b
+	})
+}
+
+// addressComponents returns the non-empty address fields
+func addressComponents(a *searxng.Address) map[string]string {
+	components := make(map[string]string)
+	for key, value := range map[string]string{
+		"road":         a.Road,
+		"house_number": string(a.HouseNumber),
+		"locality":     a.Locality,
+		"postcode":     string(a.Postcode),
+		"country":      a.Country,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			components[key] = value
+		}
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	return components
+}
+
+// formatAddress renders an address on one line, e.g. "Rue de Rivoli 1, 75001 Paris, France"
+func formatAddress(a *searxng.Address) string {
+	var parts []string
+	if street := strings.TrimSpace(a.Road + " " + string(a.HouseNumber)); street != "" {
+		parts = append(parts, street)
+	}
+	if city := strings.TrimSpace(string(a.Postcode) + " " + a.Locality); city != "" {
+		parts = append(parts, city)
+	}
+	if a.Country != "" {
+		parts = append(parts, a.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// radians converts degrees to radians
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
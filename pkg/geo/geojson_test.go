@@ -0,0 +1,81 @@
+package geo_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/geo"
+	"searxng-mcp/pkg/searxng"
+)
+
+// decodeResults parses SearXNG map results
+func decodeResults(data string) []searxng.SearchResult {
+	var results []searxng.SearchResult
+	Expect(json.Unmarshal([]byte(data), &results)).To(Succeed())
+	return results
+}
+
+var _ = Describe("GeoJSON", func() {
+	results := decodeResults(`[
+		{"title": "Louvre Museum", "url": "https://openstreetmap.org/way/1", "engine": "openstreetmap",
+		 "latitude": "48.8611", "longitude": "2.3358",
+		 "boundingbox": ["48.8596", "48.8626", "2.3305", "2.3400"],
+		 "address": {"name": "Musée du Louvre", "road": "Rue de Rivoli", "house_number": 99, "postcode": "75001", "locality": "Paris", "country": "France"},
+		 "osm": {"type": "way", "id": 1}},
+		{"title": "Eiffel Tower", "url": "https://openstreetmap.org/way/2", "engine": "photon",
+		 "latitude": 48.8584, "longitude": 2.2945, "osm": {"type": "way", "id": "2"}},
+		{"title": "No coordinates", "url": "https://example.com"}
+	]`)
+
+	It("should convert map results to features", func() {
+		feature, ok := geo.FromResult(results[0])
+		Expect(ok).To(BeTrue())
+		Expect(feature.Geometry.Coordinates).To(Equal([2]float64{2.3358, 48.8611}))
+		Expect(feature.BBox).To(Equal([]float64{2.3305, 48.8596, 2.3400, 48.8626}))
+		Expect(feature.Properties.Name).To(Equal("Musée du Louvre"))
+		Expect(feature.Properties.Address).To(Equal("Rue de Rivoli 99, 75001 Paris, France"))
+		Expect(feature.Properties.Components).To(HaveKeyWithValue("house_number", "99"))
+		Expect(feature.Properties.OSMType).To(Equal("way"))
+		Expect(feature.Properties.OSMID).To(Equal("1"))
+
+		_, ok = geo.FromResult(results[2])
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should compute great-circle distances", func() {
+		paris := geo.Point{Lat: 48.8566, Lon: 2.3522}
+		london := geo.Point{Lat: 51.5074, Lon: -0.1278}
+		Expect(geo.DistanceKm(paris, london)).To(BeNumerically("~", 343.5, 1))
+		Expect(geo.DistanceKm(paris, paris)).To(BeZero())
+	})
+
+	It("should filter and sort by distance", func() {
+		var features []geo.Feature
+		for _, r := range results {
+			if f, ok := geo.FromResult(r); ok {
+				features = append(features, f)
+			}
+		}
+
+		// Reference point next to the Eiffel Tower
+		origin := geo.Point{Lat: 48.8580, Lon: 2.2950}
+		near := geo.WithinDistance(features, origin, 1)
+		Expect(near).To(HaveLen(1))
+		Expect(near[0].Properties.Name).To(Equal("Eiffel Tower"))
+
+		all := geo.WithinDistance(features, origin, 0)
+		geo.SortByDistance(all)
+		Expect(all).To(HaveLen(2))
+		Expect(all[0].Properties.Name).To(Equal("Eiffel Tower"))
+		Expect(*all[1].Properties.DistanceKm).To(BeNumerically(">", 2))
+		Expect(features[0].Properties.DistanceKm).To(BeNil())
+	})
+
+	It("should encode an empty collection with an empty feature list", func() {
+		data, err := json.Marshal(geo.NewFeatureCollection(nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"type":"FeatureCollection","features":[]}`))
+	})
+})
@@ -0,0 +1,363 @@
+// Package history keeps a local, file-based record of the searches made through
+// the server's tools: each call with its arguments, its time and the results it returned.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Defaults for history retention
+const (
+	DefaultMaxAge     = 90 * 24 * time.Hour
+	DefaultMaxEntries = 2000
+)
+
+// Result is a result returned by a recorded search
+type Result struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+// Entry is a recorded search
+type Entry struct {
+	ID   int64     `json:"id"`
+	Time time.Time `json:"time"`
+	// Tool and Args are the name and arguments of the tool call that made the search
+	Tool       string          `json:"tool"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	Query      string          `json:"query"`
+	Categories []string        `json:"categories,omitempty"`
+	Language   string          `json:"language,omitempty"`
+	TimeRange  string          `json:"time_range,omitempty"`
+	Page       int             `json:"page,omitempty"`
+	Results    []Result        `json:"results"`
+}
+
+// SearchResults returns the recorded results as search results
+func (e Entry) SearchResults() []searxng.SearchResult {
+	results := make([]searxng.SearchResult, len(e.Results))
+	for i, r := range e.Results {
+		results[i] = searxng.SearchResult{URL: r.URL, Title: r.Title}
+	}
+	return results
+}
+
+// Filter selects entries. Zero fields match every entry.
+type Filter struct {
+	// Query matches entries whose query contains it, ignoring case
+	Query string
+	// Category matches entries searching in it
+	Category string
+	// Language matches entries with that language
+	Language string
+	// URL matches entries with a result URL containing it, such as a domain
+	URL string
+	// Since and Until bound the time of entries
+	Since time.Time
+	Until time.Time
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Entry) bool {
+	if f.Query != "" && !strings.Contains(strings.ToLower(e.Query), strings.ToLower(f.Query)) {
+		return false
+	}
+	if f.Category != "" && !containsFold(e.Categories, f.Category) {
+		return false
+	}
+	if f.Language != "" && !strings.EqualFold(e.Language, f.Language) {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.URL != "" {
+		for _, r := range e.Results {
+			if strings.Contains(strings.ToLower(r.URL), strings.ToLower(f.URL)) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Options configures a history store
+type Options struct {
+	// Path is the JSON Lines file holding the history; empty keeps it in memory
+	Path string
+	// MaxAge is how long entries are kept; 0 keeps them until MaxEntries is reached
+	MaxAge time.Duration
+	// MaxEntries is the number of most recent entries kept; 0 keeps all
+	MaxEntries int
+	// Now returns the current time; nil means time.Now
+	Now func() time.Time
+}
+
+// Store records searches and persists them to a JSON Lines file. New entries
+// are appended to the file, which is rewritten when entries are purged or
+// once it holds as many expired entries as kept ones.
+type Store struct {
+	opts Options
+
+	mu      sync.Mutex
+	entries []Entry
+	nextID  int64
+	// lines is the number of entries in the file, expired ones included
+	lines int
+}
+
+// Open loads the history at opts.Path, dropping entries past retention.
+// A missing file yields an empty history.
+func Open(opts Options) (*Store, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	s := &Store{opts: opts, nextID: 1}
+	if opts.Path == "" {
+		return s, nil
+	}
+
+	entries, skipped, err := load(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+	s.entries = entries
+	s.lines = len(entries)
+	for _, e := range entries {
+		s.nextID = max(s.nextID, e.ID+1)
+	}
+
+	// Rewriting drops a line cut short by a crash, which the next entry
+	// appended would otherwise be joined to
+	if s.prune() > 0 || skipped > 0 {
+		if err := s.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Record adds a search made by a call of tool with args, and the results the
+// call returned. Persistence is best effort: a failed write must not fail the search.
+func (s *Store) Record(tool string, args json.RawMessage, req searxng.SearchRequest, results []searxng.SearchResult) Entry {
+	entry := Entry{
+		Time:      s.opts.Now().UTC(),
+		Tool:      tool,
+		Args:      args,
+		Query:     req.Query,
+		Language:  req.Language,
+		TimeRange: string(req.TimeRange),
+		Page:      req.PageNo,
+		Results:   []Result{},
+	}
+	for _, c := range req.Category {
+		entry.Categories = append(entry.Categories, string(c))
+	}
+	for _, r := range results {
+		if r.URL != "" {
+			entry.Results = append(entry.Results, Result{URL: r.URL, Title: r.Title})
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.ID = s.nextID
+	s.nextID++
+	s.entries = append(s.entries, entry)
+	s.prune()
+
+	if s.opts.Path != "" {
+		if s.lines+1 > 2*len(s.entries) {
+			_ = s.rewrite()
+		} else if err := s.append(entry); err == nil {
+			s.lines++
+		}
+	}
+	return entry
+}
+
+// Get returns the entry with the given id
+func (s *Store) Get(id int64) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// List returns the entries matching filter, newest first, and how many matched
+// in total. limit caps the entries returned; 0 returns all.
+func (s *Store) List(filter Filter, limit int) ([]Entry, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	var matched []Entry
+	total := 0
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if !filter.Match(s.entries[i]) {
+			continue
+		}
+		total++
+		if limit <= 0 || len(matched) < limit {
+			matched = append(matched, s.entries[i])
+		}
+	}
+	return matched, total
+}
+
+// Purge removes the entries matching filter and returns how many were removed
+func (s *Store) Purge(filter Filter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.entries[:0]
+	removed := 0
+	for _, e := range s.entries {
+		if filter.Match(e) {
+			removed++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.entries = kept
+	if removed == 0 || s.opts.Path == "" {
+		return removed, nil
+	}
+	return removed, s.rewrite()
+}
+
+// prune drops entries past retention and returns how many were dropped.
+// Callers must hold s.mu or own s exclusively.
+func (s *Store) prune() int {
+	start := 0
+	if s.opts.MaxAge > 0 {
+		cutoff := s.opts.Now().Add(-s.opts.MaxAge)
+		for start < len(s.entries) && s.entries[start].Time.Before(cutoff) {
+			start++
+		}
+	}
+	if s.opts.MaxEntries > 0 && len(s.entries)-start > s.opts.MaxEntries {
+		start = len(s.entries) - s.opts.MaxEntries
+	}
+	if start > 0 {
+		s.entries = append([]Entry(nil), s.entries[start:]...)
+	}
+	return start
+}
+
+// append writes one entry at the end of the file. Callers must hold s.mu.
+func (s *Store) append(entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.opts.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(s.opts.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// rewrite replaces the file with the kept entries. Callers must hold s.mu or
+// own s exclusively.
+func (s *Store) rewrite() error {
+	if err := Save(s.opts.Path, s.entries); err != nil {
+		return err
+	}
+	s.lines = len(s.entries)
+	return nil
+}
+
+// Load reads a history file, skipping lines that do not decode, such as one
+// cut short by a crash. A missing file yields no entries.
+func Load(path string) ([]Entry, error) {
+	entries, _, err := load(path)
+	return entries, err
+}
+
+// load reads a history file, also returning the number of lines skipped
+func load(path string) ([]Entry, int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ID == 0 {
+			skipped++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, skipped, nil
+}
+
+// Save writes entries to path atomically, one JSON object per line
+func Save(path string, entries []Entry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// containsFold reports whether values holds value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
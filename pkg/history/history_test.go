@@ -0,0 +1,163 @@
+package history_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/history"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		path string
+		now  time.Time
+		opts history.Options
+	)
+
+	request := func(query string) searxng.SearchRequest {
+		return searxng.SearchRequest{Query: query, Language: "en", Category: []searxng.Category{searxng.CategoryNews}, PageNo: 1}
+	}
+	results := func(urls ...string) []searxng.SearchResult {
+		var r []searxng.SearchResult
+		for _, u := range urls {
+			r = append(r, searxng.SearchResult{URL: u, Title: "Title of " + u})
+		}
+		return r
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "history.jsonl")
+		now = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+		opts = history.Options{Path: path, Now: func() time.Time { return now }}
+	})
+
+	open := func() *history.Store {
+		store, err := history.Open(opts)
+		Expect(err).NotTo(HaveOccurred())
+		return store
+	}
+
+	It("should record searches and reload them from the file", func() {
+		store := open()
+		first := store.Record("search_category", json.RawMessage(`{"query":"rust release","categories":["news"]}`), request("rust release"), results("https://example.com/a", ""))
+		now = now.Add(time.Minute)
+		second := store.Record("search_category", nil, request("go release"), results("https://example.org/b"))
+
+		Expect(first.ID).To(Equal(int64(1)))
+		Expect(second.ID).To(Equal(int64(2)))
+		Expect(first.Results).To(Equal([]history.Result{{URL: "https://example.com/a", Title: "Title of https://example.com/a"}}))
+		Expect(first.Categories).To(Equal([]string{"news"}))
+
+		reopened := open()
+		entries, total := reopened.List(history.Filter{}, 0)
+		Expect(total).To(Equal(2))
+		Expect(entries[0].Query).To(Equal("go release"))
+		Expect(entries[1].Time).To(Equal(now.Add(-time.Minute)))
+		Expect(entries[1].Tool).To(Equal("search_category"))
+		Expect(string(entries[1].Args)).To(MatchJSON(`{"query":"rust release","categories":["news"]}`))
+		Expect(reopened.Record("search_category", nil, request("zig"), nil).ID).To(Equal(int64(3)))
+	})
+
+	It("should filter entries newest first", func() {
+		store := open()
+		store.Record("search_category", nil, request("Rust release"), results("https://blog.rust-lang.org/x"))
+		now = now.Add(time.Hour)
+		store.Record("search_advanced", nil, searxng.SearchRequest{Query: "rust borrow checker", Language: "fr"}, results("https://doc.rust-lang.org/y"))
+		now = now.Add(time.Hour)
+		store.Record("search_category", nil, request("go generics"), results("https://go.dev/z"))
+
+		entries, total := store.List(history.Filter{Query: "RUST"}, 1)
+		Expect(total).To(Equal(2))
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Query).To(Equal("rust borrow checker"))
+
+		entries, _ = store.List(history.Filter{Category: "news", URL: "go.dev"}, 0)
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Query).To(Equal("go generics"))
+
+		entries, _ = store.List(history.Filter{Since: now.Add(-90 * time.Minute), Until: now}, 0)
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Language).To(Equal("fr"))
+	})
+
+	It("should drop entries past the retention limits", func() {
+		opts.MaxAge = 24 * time.Hour
+		opts.MaxEntries = 2
+		store := open()
+		for _, q := range []string{"one", "two", "three"} {
+			store.Record("search_category", nil, request(q), nil)
+			now = now.Add(time.Hour)
+		}
+		entries, _ := store.List(history.Filter{}, 0)
+		Expect(entries).To(HaveLen(2))
+
+		now = now.Add(24 * time.Hour)
+		store.Record("search_category", nil, request("four"), nil)
+		entries, _ = store.List(history.Filter{}, 0)
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Query).To(Equal("four"))
+
+		reopened, err := history.Open(opts)
+		Expect(err).NotTo(HaveOccurred())
+		entries, _ = reopened.List(history.Filter{}, 0)
+		Expect(entries).To(HaveLen(1))
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(data), "\n")).To(Equal(1))
+	})
+
+	It("should purge matching entries from memory and disk", func() {
+		store := open()
+		store.Record("search_category", nil, request("secret project"), nil)
+		store.Record("search_category", nil, request("weather"), nil)
+
+		removed, err := store.Purge(history.Filter{Query: "secret"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal(1))
+		_, ok := store.Get(1)
+		Expect(ok).To(BeFalse())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("secret"))
+
+		removed, err = store.Purge(history.Filter{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal(1))
+		_, total := open().List(history.Filter{}, 0)
+		Expect(total).To(BeZero())
+	})
+
+	It("should skip broken lines", func() {
+		store := open()
+		store.Record("search_category", nil, request("ok"), results("https://example.com/a"))
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		Expect(err).NotTo(HaveOccurred())
+		f.WriteString(`{"id": 2, "query": "cut sho`)
+		f.Close()
+
+		reopened := open()
+		reopened.Record("search_category", nil, request("after crash"), nil)
+		entries, total := open().List(history.Filter{}, 0)
+		Expect(total).To(Equal(2))
+		Expect(entries[0].Query).To(Equal("after crash"))
+		Expect(entries[1].Query).To(Equal("ok"))
+	})
+
+	It("should keep the history in memory without a path", func() {
+		opts.Path = ""
+		store := open()
+		store.Record("search_category", nil, request("ephemeral"), nil)
+		_, total := store.List(history.Filter{}, 0)
+		Expect(total).To(Equal(1))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+})
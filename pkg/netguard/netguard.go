@@ -0,0 +1,66 @@
+// Package netguard keeps outgoing fetches off loopback, private and link-local networks.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, which Tailscale
+// and cloud VPC services also use for internal hosts
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ErrBlocked is returned when a connection targets a loopback, private,
+// shared, link-local or unspecified address
+var ErrBlocked = errors.New("address not allowed")
+
+// Blocked reports whether addr is a loopback, private, shared, link-local or
+// unspecified address, which covers cloud metadata endpoints such as 169.254.169.254
+func Blocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	// 0.0.0.0/8 reaches the local host on most systems
+	if addr.Is4() && addr.As4()[0] == 0 {
+		return true
+	}
+	return addr.IsLoopback() || addr.IsPrivate() || sharedAddressSpace.Contains(addr) || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast()
+}
+
+// Control is a net.Dialer Control function refusing blocked addresses. It runs
+// on the resolved address of every connection, so redirects and host names
+// resolving to internal addresses are caught as well.
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlocked, address)
+	}
+	if Blocked(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlocked, addrPort.Addr())
+	}
+	return nil
+}
+
+// Transport returns an HTTP transport refusing to connect to blocked addresses
+// unless allow reports true. It ignores proxy settings, since a proxy resolves
+// hosts beyond the reach of the check.
+func Transport(allow func() bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			if allow != nil && allow() {
+				return nil
+			}
+			return Control(network, address, c)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
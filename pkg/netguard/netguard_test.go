@@ -0,0 +1,40 @@
+package netguard_test
+
+import (
+	"errors"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/netguard"
+)
+
+var _ = Describe("Netguard", func() {
+	DescribeTable("Blocked",
+		func(addr string, blocked bool) {
+			Expect(netguard.Blocked(netip.MustParseAddr(addr))).To(Equal(blocked))
+		},
+		Entry("loopback", "127.0.0.1", true),
+		Entry("IPv6 loopback", "::1", true),
+		Entry("private", "10.1.2.3", true),
+		Entry("private 192.168", "192.168.1.1", true),
+		Entry("unique local", "fd00::1", true),
+		Entry("shared address space", "100.100.100.100", true),
+		Entry("IPv4-mapped shared address space", "::ffff:100.64.0.1", true),
+		Entry("public next to shared address space", "100.128.0.1", false),
+		Entry("metadata endpoint", "169.254.169.254", true),
+		Entry("IPv6 link-local", "fe80::1", true),
+		Entry("unspecified", "0.0.0.0", true),
+		Entry("this network", "0.1.2.3", true),
+		Entry("IPv4-mapped loopback", "::ffff:127.0.0.1", true),
+		Entry("public", "93.184.216.34", false),
+		Entry("public IPv6", "2606:2800:220:1::", false),
+	)
+
+	It("should refuse blocked addresses when dialing", func() {
+		err := netguard.Control("tcp4", "127.0.0.1:80", nil)
+		Expect(errors.Is(err, netguard.ErrBlocked)).To(BeTrue())
+		Expect(netguard.Control("tcp4", "93.184.216.34:443", nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,243 @@
+// Package news clusters news search results into stories.
+package news
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/webpage"
+)
+
+// DefaultThreshold is the similarity above which two articles belong to the same story
+const DefaultThreshold = 0.45
+
+// Options configures story clustering
+type Options struct {
+	// Threshold is the minimum similarity for joining a story (default DefaultThreshold)
+	Threshold float64
+	// Now is the reference time for recency ranking (default time.Now)
+	Now time.Time
+}
+
+// Article is a news result assigned to a story
+type Article struct {
+	Title         string     `json:"title"`
+	URL           string     `json:"url"`
+	Outlet        string     `json:"outlet"`
+	PublishedDate *time.Time `json:"published_date,omitempty"`
+}
+
+// Story is a cluster of articles covering the same event
+type Story struct {
+	Headline string     `json:"headline"`
+	Snippet  string     `json:"snippet"`
+	Outlets  []string   `json:"outlets"`
+	Earliest *time.Time `json:"earliest,omitempty"`
+	Latest   *time.Time `json:"latest,omitempty"`
+	Articles []Article  `json:"articles"`
+	// Score combines coverage breadth and recency; higher ranks first
+	Score float64 `json:"score"`
+}
+
+// item is a result prepared for clustering
+type item struct {
+	result     searxng.SearchResult
+	outlet     string
+	published  *time.Time
+	titleTerms map[string]bool
+	allTerms   map[string]bool
+}
+
+// Cluster groups results into stories by title and content similarity and
+// orders them by coverage breadth and recency. Duplicate URLs are merged.
+func Cluster(results []searxng.SearchResult, opts Options) []Story {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	items := prepare(results)
+
+	// Single-link clustering: an article joins the story holding its most similar article
+	var clusters [][]*item
+	for _, it := range items {
+		best, bestSim := -1, 0.0
+		for c, members := range clusters {
+			for _, m := range members {
+				if sim := similarity(it, m); sim > bestSim {
+					best, bestSim = c, sim
+				}
+			}
+		}
+		if best >= 0 && bestSim >= opts.Threshold {
+			clusters[best] = append(clusters[best], it)
+		} else {
+			clusters = append(clusters, []*item{it})
+		}
+	}
+
+	stories := make([]Story, 0, len(clusters))
+	for _, members := range clusters {
+		stories = append(stories, newStory(members, opts.Now))
+	}
+	sort.SliceStable(stories, func(i, j int) bool { return stories[i].Score > stories[j].Score })
+
+	return stories
+}
+
+// prepare builds clustering items, skipping results without a URL or title and duplicate URLs
+func prepare(results []searxng.SearchResult) []*item {
+	seen := make(map[string]bool)
+	var items []*item
+	for _, r := range results {
+		if r.URL == "" || strings.TrimSpace(r.Title) == "" {
+			continue
+		}
+		key := searxng.NormalizeURL(r.URL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		it := &item{
+			result:     r,
+			outlet:     searxng.Domain(r.URL),
+			titleTerms: termSet(r.Title),
+			allTerms:   termSet(r.Title + " " + r.Content),
+		}
+		if t, ok := r.PublishedTime(); ok {
+			it.published = &t
+		}
+		items = append(items, it)
+	}
+	return items
+}
+
+// similarity scores two articles. Titles dominate: headlines of the same story
+// share most of their words, while snippets vary more between outlets.
+func similarity(a, b *item) float64 {
+	return 0.6*overlap(a.titleTerms, b.titleTerms) + 0.4*jaccard(a.allTerms, b.allTerms)
+}
+
+// newStory summarizes a cluster of articles
+func newStory(members []*item, now time.Time) Story {
+	story := Story{Articles: make([]Article, 0, len(members))}
+	outlets := make(map[string]bool)
+
+	for _, m := range members {
+		story.Articles = append(story.Articles, Article{
+			Title:         m.result.Title,
+			URL:           m.result.URL,
+			Outlet:        m.outlet,
+			PublishedDate: m.published,
+		})
+		if m.outlet != "" && !outlets[m.outlet] {
+			outlets[m.outlet] = true
+			story.Outlets = append(story.Outlets, m.outlet)
+		}
+		if m.published != nil {
+			if story.Earliest == nil || m.published.Before(*story.Earliest) {
+				story.Earliest = m.published
+			}
+			if story.Latest == nil || m.published.After(*story.Latest) {
+				story.Latest = m.published
+			}
+		}
+	}
+
+	// Articles within a story read oldest first; undated ones go last
+	sort.SliceStable(story.Articles, func(i, j int) bool {
+		a, b := story.Articles[i].PublishedDate, story.Articles[j].PublishedDate
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	rep := representative(members)
+	story.Headline = rep.result.Title
+	story.Snippet = webpage.Truncate(strings.Join(strings.Fields(rep.result.Content), " "), 300)
+	story.Score = score(len(story.Outlets), story.Latest, now)
+
+	return story
+}
+
+// representative returns the member most similar to the rest of its story.
+// Ties go to the highest-ranked article, with a non-empty snippet preferred.
+func representative(members []*item) *item {
+	best, bestScore := members[0], -1.0
+	for _, m := range members {
+		total := 0.0
+		for _, other := range members {
+			if other != m {
+				total += similarity(m, other)
+			}
+		}
+		if strings.TrimSpace(m.result.Content) == "" {
+			total -= 1
+		}
+		if total > bestScore {
+			best, bestScore = m, total
+		}
+	}
+	return best
+}
+
+// score ranks a story by the number of outlets covering it, decayed by the age
+// of its latest article with a half-life of one day. Undated stories count as
+// one week old.
+func score(outlets int, latest *time.Time, now time.Time) float64 {
+	age := 7 * 24 * time.Hour
+	if latest != nil {
+		age = max(now.Sub(*latest), 0)
+	}
+	s := float64(outlets) * math.Pow(0.5, age.Hours()/24)
+	return math.Round(s*1000) / 1000
+}
+
+// termSet returns the distinct terms of text
+func termSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range webpage.Terms(text) {
+		set[t] = true
+	}
+	return set
+}
+
+// overlap returns the overlap coefficient of two sets. Single-term sets fall
+// back to the Jaccard index so one shared word cannot make a full match.
+func overlap(a, b map[string]bool) float64 {
+	if min(len(a), len(b)) < 2 {
+		return jaccard(a, b)
+	}
+	return float64(intersection(a, b)) / float64(min(len(a), len(b)))
+}
+
+// jaccard returns the Jaccard index of two sets
+func jaccard(a, b map[string]bool) float64 {
+	shared := intersection(a, b)
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// intersection counts the elements shared by two sets
+func intersection(a, b map[string]bool) int {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	n := 0
+	for t := range a {
+		if b[t] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package news_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/news"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Cluster", func() {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	results := []searxng.SearchResult{
+		{URL: "https://www.reuters.com/a", Title: "Central bank raises interest rates by half a point",
+			Content: "The central bank raised interest rates on Tuesday to fight inflation.", PublishedDate: "2026-03-10T08:00:00"},
+		{URL: "https://apnews.com/b", Title: "Central bank hikes interest rates half point amid inflation",
+			Content: "Policymakers raised rates by half a point.", PublishedDate: "2026-03-10T06:30:00+00:00"},
+		{URL: "https://bbc.co.uk/c", Title: "Interest rates raised by central bank",
+			Content: "Borrowing costs climb after the central bank decision.", PublishedDate: "2026-03-10T10:15:00"},
+		{URL: "https://example.org/d", Title: "Local team wins championship final",
+			Content: "Fans celebrated downtown after the final whistle.", PublishedDate: "2026-03-10T11:00:00"},
+		{URL: "https://reuters.com/a/", Title: "Central bank raises interest rates by half a point (dup)"},
+		{URL: "https://example.net/e", Title: "Museum reopens after renovation", Content: "Undated story."},
+	}
+
+	It("should group articles about the same story", func() {
+		stories := news.Cluster(results, news.Options{Now: now})
+		Expect(stories).To(HaveLen(3))
+
+		top := stories[0]
+		Expect(top.Articles).To(HaveLen(3))
+		Expect(top.Outlets).To(ConsistOf("reuters.com", "apnews.com", "bbc.co.uk"))
+		Expect(top.Earliest.Equal(time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC))).To(BeTrue())
+		Expect(top.Latest.Equal(time.Date(2026, 3, 10, 10, 15, 0, 0, time.UTC))).To(BeTrue())
+		Expect(top.Headline).To(ContainSubstring("interest rates"))
+		Expect(top.Snippet).NotTo(BeEmpty())
+	})
+
+	It("should order articles within a story chronologically", func() {
+		top := news.Cluster(results, news.Options{Now: now})[0]
+		Expect(top.Articles[0].Outlet).To(Equal("apnews.com"))
+		Expect(top.Articles[2].Outlet).To(Equal("bbc.co.uk"))
+	})
+
+	It("should rank broad recent coverage above single-outlet and undated stories", func() {
+		stories := news.Cluster(results, news.Options{Now: now})
+		Expect(stories[1].Headline).To(Equal("Local team wins championship final"))
+		Expect(stories[2].Headline).To(Equal("Museum reopens after renovation"))
+		Expect(stories[2].Latest).To(BeNil())
+		Expect(stories[0].Score).To(BeNumerically(">", stories[1].Score))
+	})
+
+	It("should return no stories for no results", func() {
+		Expect(news.Cluster(nil, news.Options{})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,13 @@
+package news_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestNews(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "News Suite")
+}
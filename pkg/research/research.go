@@ -0,0 +1,340 @@
+// Package research runs iterative multi-query research on top of a SearXNG client.
+package research
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/webpage"
+)
+
+// Query origins recorded in the ledger
+const (
+	OriginQuestion   = "question"
+	OriginCorrection = "correction"
+	OriginSuggestion = "suggestion"
+	OriginExpansion  = "expansion"
+)
+
+// Stop reasons recorded in the ledger
+const (
+	StopQueryBudget  = "query budget exhausted"
+	StopTimeBudget   = "time budget exhausted"
+	StopSourceBudget = "source budget reached"
+	StopExhausted    = "no more follow-up queries"
+	StopCancelled    = "cancelled"
+)
+
+// Budget bounds a research run
+type Budget struct {
+	MaxQueries  int
+	MaxDuration time.Duration
+	MaxSources  int
+}
+
+// Options configures a research run
+type Options struct {
+	Budget
+	Language   string
+	TimeRange  searxng.TimeRange
+	Categories []searxng.Category
+	// ResultsPerQuery is the number of leading results considered per query
+	ResultsPerQuery int
+	// SnippetsPerSource caps the key snippets kept per source
+	SnippetsPerSource int
+	// ExpansionsPerQuery caps the term-based follow-up queries generated per query;
+	// a negative value disables term expansion
+	ExpansionsPerQuery int
+	// OnQuery, when set, is called after each query with its log entry
+	OnQuery func(QueryLog)
+}
+
+// QueryLog records one query issued during research
+type QueryLog struct {
+	Query      string `json:"query"`
+	Origin     string `json:"origin"`
+	Results    int    `json:"results"`
+	NewSources int    `json:"new_sources"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Source is an entry of the source ledger
+type Source struct {
+	ID            int      `json:"id"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	Domain        string   `json:"domain"`
+	PublishedDate string   `json:"published_date,omitempty"`
+	Queries       []string `json:"queries"`
+	Snippets      []string `json:"snippets"`
+	BestRank      int      `json:"best_rank"`
+}
+
+// Ledger is the consolidated outcome of a research run
+type Ledger struct {
+	Question   string        `json:"question"`
+	StopReason string        `json:"stop_reason"`
+	Elapsed    time.Duration `json:"-"`
+	Queries    []QueryLog    `json:"queries"`
+	Sources    []Source      `json:"sources"`
+}
+
+// DefaultOptions returns the default research options
+func DefaultOptions() Options {
+	return Options{
+		Budget: Budget{
+			MaxQueries:  6,
+			MaxDuration: 60 * time.Second,
+			MaxSources:  25,
+		},
+		ResultsPerQuery:    8,
+		SnippetsPerSource:  3,
+		ExpansionsPerQuery: 2,
+	}
+}
+
+// pendingQuery is a query waiting in the research queue
+type pendingQuery struct {
+	query  string
+	origin string
+}
+
+// Run researches question by searching, reading suggestions, corrections and
+// frequent result terms, and issuing follow-up queries until a budget is hit.
+func Run(ctx context.Context, client searxng.Client, question string, opts Options) (*Ledger, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, errors.New("question cannot be empty")
+	}
+	opts = withDefaults(opts)
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, opts.MaxDuration)
+	defer cancel()
+
+	ledger := &Ledger{Question: question}
+	sources := make(map[string]*Source)
+	var order []string
+
+	queue := []pendingQuery{{query: question, origin: OriginQuestion}}
+	issued := map[string]bool{normalizeQuery(question): true}
+	usedTerms := make(map[string]bool)
+	for _, t := range webpage.Terms(question) {
+		usedTerms[t] = true
+	}
+
+	for {
+		if len(queue) == 0 {
+			ledger.StopReason = StopExhausted
+			break
+		}
+		if len(ledger.Queries) >= opts.MaxQueries {
+			ledger.StopReason = StopQueryBudget
+			break
+		}
+		if len(sources) >= opts.MaxSources {
+			ledger.StopReason = StopSourceBudget
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			ledger.StopReason = stopReason(err)
+			break
+		}
+
+		next := queue[0]
+		queue = queue[1:]
+
+		response, err := searxng.SearchWithOptions(ctx, client, next.query, searxng.SearchOptions{
+			Language:   opts.Language,
+			TimeRange:  opts.TimeRange,
+			PageNo:     1,
+			Categories: opts.Categories,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				ledger.StopReason = stopReason(ctx.Err())
+				break
+			}
+			failed := QueryLog{Query: next.query, Origin: next.origin, Error: err.Error()}
+			ledger.Queries = append(ledger.Queries, failed)
+			if opts.OnQuery != nil {
+				opts.OnQuery(failed)
+			}
+			continue
+		}
+
+		results := response.Results
+		if len(results) > opts.ResultsPerQuery {
+			results = results[:opts.ResultsPerQuery]
+		}
+
+		log := QueryLog{Query: next.query, Origin: next.origin, Results: len(results)}
+		for rank, r := range results {
+			if r.URL == "" {
+				continue
+			}
+			key := searxng.NormalizeURL(r.URL)
+			source, ok := sources[key]
+			if !ok {
+				if len(sources) >= opts.MaxSources {
+					continue
+				}
+				source = &Source{
+					Title:    r.Title,
+					URL:      r.URL,
+					Domain:   searxng.Domain(r.URL),
+					BestRank: rank + 1,
+					Snippets: []string{},
+				}
+				if date, ok := r.PublishedDate.(string); ok {
+					source.PublishedDate = date
+				}
+				sources[key] = source
+				order = append(order, key)
+				log.NewSources++
+			}
+			if !slices.Contains(source.Queries, next.query) {
+				source.Queries = append(source.Queries, next.query)
+			}
+			source.BestRank = min(source.BestRank, rank+1)
+			addSnippet(source, r.Content, opts.SnippetsPerSource)
+		}
+		ledger.Queries = append(ledger.Queries, log)
+		if opts.OnQuery != nil {
+			opts.OnQuery(log)
+		}
+
+		// Queue follow-ups: corrections first, then suggestions, then term expansions
+		enqueue := func(query, origin string) {
+			query = strings.TrimSpace(query)
+			key := normalizeQuery(query)
+			if query == "" || issued[key] {
+				return
+			}
+			issued[key] = true
+			queue = append(queue, pendingQuery{query: query, origin: origin})
+		}
+		for _, c := range response.Corrections {
+			enqueue(c, OriginCorrection)
+		}
+		for _, s := range response.Suggestions {
+			enqueue(s, OriginSuggestion)
+		}
+		for _, term := range expansionTerms(results, usedTerms, opts.ExpansionsPerQuery) {
+			usedTerms[term] = true
+			enqueue(question+" "+term, OriginExpansion)
+		}
+	}
+
+	ledger.Sources = rankSources(sources, order)
+	ledger.Elapsed = time.Since(start)
+	return ledger, nil
+}
+
+// withDefaults fills unset options with defaults
+func withDefaults(opts Options) Options {
+	def := DefaultOptions()
+	if opts.MaxQueries <= 0 {
+		opts.MaxQueries = def.MaxQueries
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = def.MaxDuration
+	}
+	if opts.MaxSources <= 0 {
+		opts.MaxSources = def.MaxSources
+	}
+	if opts.ResultsPerQuery <= 0 {
+		opts.ResultsPerQuery = def.ResultsPerQuery
+	}
+	if opts.SnippetsPerSource <= 0 {
+		opts.SnippetsPerSource = def.SnippetsPerSource
+	}
+	if opts.ExpansionsPerQuery < 0 {
+		opts.ExpansionsPerQuery = 0
+	} else if opts.ExpansionsPerQuery == 0 {
+		opts.ExpansionsPerQuery = def.ExpansionsPerQuery
+	}
+	return opts
+}
+
+// expansionTerms returns the most frequent new terms across result titles and snippets.
+// A term must appear in at least two results to be considered.
+func expansionTerms(results []searxng.SearchResult, used map[string]bool, n int) []string {
+	df := make(map[string]int)
+	var order []string
+	for _, r := range results {
+		seen := make(map[string]bool)
+		for _, t := range webpage.Terms(r.Title + " " + r.Content) {
+			if used[t] || seen[t] || len([]rune(t)) < 4 || isNumber(t) {
+				continue
+			}
+			seen[t] = true
+			if df[t] == 0 {
+				order = append(order, t)
+			}
+			df[t]++
+		}
+	}
+
+	var terms []string
+	for _, t := range order {
+		if df[t] >= 2 {
+			terms = append(terms, t)
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return df[terms[i]] > df[terms[j]] })
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+// rankSources orders sources by how many queries found them, then by best rank, and numbers them
+func rankSources(sources map[string]*Source, order []string) []Source {
+	ranked := make([]Source, 0, len(order))
+	for _, key := range order {
+		ranked = append(ranked, *sources[key])
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if len(ranked[i].Queries) != len(ranked[j].Queries) {
+			return len(ranked[i].Queries) > len(ranked[j].Queries)
+		}
+		return ranked[i].BestRank < ranked[j].BestRank
+	})
+	for i := range ranked {
+		ranked[i].ID = i + 1
+	}
+	return ranked
+}
+
+// addSnippet records a distinct snippet for a source
+func addSnippet(source *Source, snippet string, limit int) {
+	snippet = webpage.Truncate(strings.Join(strings.Fields(snippet), " "), 300)
+	if snippet == "" || len(source.Snippets) >= limit || slices.Contains(source.Snippets, snippet) {
+		return
+	}
+	source.Snippets = append(source.Snippets, snippet)
+}
+
+// stopReason maps a context error to a stop reason
+func stopReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return StopTimeBudget
+	}
+	return StopCancelled
+}
+
+// normalizeQuery lowercases a query and collapses whitespace for duplicate detection
+func normalizeQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// isNumber reports whether a term consists of digits only
+func isNumber(t string) bool {
+	return strings.Trim(t, "0123456789") == ""
+}
@@ -0,0 +1,93 @@
+package research_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/research"
+	"searxng-mcp/pkg/searxng"
+)
+
+// fakeClient answers searches from a map of canned responses
+type fakeClient struct {
+	responses map[string]*searxng.SearchResponse
+	queries   []string
+}
+
+func (c *fakeClient) Search(ctx context.Context, req searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	c.queries = append(c.queries, req.Query)
+	if resp, ok := c.responses[req.Query]; ok {
+		return resp, nil
+	}
+	return &searxng.SearchResponse{Query: req.Query}, nil
+}
+
+var _ = Describe("Research", func() {
+	var client *fakeClient
+
+	BeforeEach(func() {
+		client = &fakeClient{responses: map[string]*searxng.SearchResponse{
+			"rust async": {
+				Results: []searxng.SearchResult{
+					{URL: "https://rust-lang.org/async", Title: "Async Rust", Content: "Futures and executors in tokio"},
+					{URL: "https://tokio.rs", Title: "Tokio runtime", Content: "Tokio executors drive futures"},
+				},
+				Suggestions: []string{"rust async await"},
+				Corrections: []string{"rust asynchronous"},
+			},
+			"rust asynchronous": {
+				Results: []searxng.SearchResult{
+					{URL: "https://www.rust-lang.org/async/", Title: "Async Rust", Content: "The async book"},
+				},
+			},
+		}}
+	})
+
+	It("should follow corrections, suggestions and frequent terms", func() {
+		ledger, err := research.Run(context.Background(), client, "rust async", research.Options{
+			Budget: research.Budget{MaxQueries: 10, MaxDuration: time.Minute, MaxSources: 10},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ledger.StopReason).To(Equal(research.StopExhausted))
+		Expect(client.queries[:3]).To(Equal([]string{"rust async", "rust asynchronous", "rust async await"}))
+		Expect(client.queries).To(ContainElement("rust async futures"))
+
+		origins := make(map[string]string)
+		for _, q := range ledger.Queries {
+			origins[q.Query] = q.Origin
+		}
+		Expect(origins["rust asynchronous"]).To(Equal(research.OriginCorrection))
+		Expect(origins["rust async await"]).To(Equal(research.OriginSuggestion))
+		Expect(origins["rust async futures"]).To(Equal(research.OriginExpansion))
+	})
+
+	It("should merge sources found by several queries", func() {
+		ledger, err := research.Run(context.Background(), client, "rust async", research.Options{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ledger.Sources).To(HaveLen(2))
+		Expect(ledger.Sources[0].ID).To(Equal(1))
+		Expect(ledger.Sources[0].URL).To(Equal("https://rust-lang.org/async"))
+		Expect(ledger.Sources[0].Queries).To(Equal([]string{"rust async", "rust asynchronous"}))
+		Expect(ledger.Sources[0].Snippets).To(HaveLen(2))
+	})
+
+	It("should stop on the query budget", func() {
+		ledger, err := research.Run(context.Background(), client, "rust async", research.Options{
+			Budget: research.Budget{MaxQueries: 1},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ledger.Queries).To(HaveLen(1))
+		Expect(ledger.StopReason).To(Equal(research.StopQueryBudget))
+	})
+
+	It("should reject an empty question", func() {
+		_, err := research.Run(context.Background(), client, "  ", research.Options{})
+		Expect(err).To(HaveOccurred())
+	})
+})
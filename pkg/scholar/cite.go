@@ -0,0 +1,308 @@
+package scholar
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Citation formats
+const (
+	FormatBibTeX  = "bibtex"
+	FormatRIS     = "ris"
+	FormatCSLJSON = "csl-json"
+)
+
+// Formats lists the supported citation formats
+var Formats = []string{FormatBibTeX, FormatRIS, FormatCSLJSON}
+
+// Cite renders papers in the given citation format
+func Cite(papers []Paper, format string) (string, error) {
+	switch format {
+	case FormatBibTeX:
+		entries := make([]string, len(papers))
+		keys := make(map[string]int)
+		for i, p := range papers {
+			entries[i] = BibTeX(p, uniqueKey(CiteKey(p), keys))
+		}
+		return strings.Join(entries, "\n\n"), nil
+	case FormatRIS:
+		entries := make([]string, len(papers))
+		for i, p := range papers {
+			entries[i] = RIS(p)
+		}
+		return strings.Join(entries, "\n\n"), nil
+	case FormatCSLJSON:
+		items := make([]CSLItem, len(papers))
+		keys := make(map[string]int)
+		for i, p := range papers {
+			items[i] = NewCSLItem(p, uniqueKey(CiteKey(p), keys))
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode CSL-JSON: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unsupported citation format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// CiteKey builds a citation key from the first author's surname, the year and
+// the first significant title word, e.g. "vaswani2017attention"
+func CiteKey(p Paper) string {
+	var key strings.Builder
+	if len(p.Authors) > 0 {
+		key.WriteString(keyWord(surname(p.Authors[0])))
+	}
+	if p.Year > 0 {
+		key.WriteString(strconv.Itoa(p.Year))
+	}
+	for _, w := range strings.Fields(p.Title) {
+		w = keyWord(w)
+		if len(w) > 3 && !titleStopwords[w] {
+			key.WriteString(w)
+			break
+		}
+	}
+	if key.Len() == 0 {
+		return "paper"
+	}
+	return key.String()
+}
+
+// titleStopwords are skipped when picking the title word of a citation key
+var titleStopwords = map[string]bool{"about": true, "from": true, "into": true, "that": true, "their": true, "this": true, "towards": true, "with": true}
+
+// uniqueKey disambiguates repeated keys with a letter suffix
+func uniqueKey(key string, seen map[string]int) string {
+	n := seen[key]
+	seen[key] = n + 1
+	if n == 0 {
+		return key
+	}
+	return key + string(rune('a'+(n-1)%26))
+}
+
+// keyWord lowercases a word and keeps ASCII letters and digits only
+func keyWord(w string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, w)
+}
+
+// surname returns the family name of an author written "Given Family" or "Family, Given"
+func surname(author string) string {
+	if family, _, ok := strings.Cut(author, ","); ok {
+		return strings.TrimSpace(family)
+	}
+	fields := strings.Fields(author)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// givenName returns the given names of an author
+func givenName(author string) string {
+	if _, given, ok := strings.Cut(author, ","); ok {
+		return strings.TrimSpace(given)
+	}
+	fields := strings.Fields(author)
+	if len(fields) < 2 {
+		return ""
+	}
+	return strings.Join(fields[:len(fields)-1], " ")
+}
+
+// entryType maps a paper to its BibTeX entry type
+func entryType(p Paper) string {
+	switch strings.ToLower(p.Type) {
+	case "book":
+		return "book"
+	case "book-chapter", "chapter":
+		return "incollection"
+	case "proceedings-article", "conference", "inproceedings":
+		return "inproceedings"
+	case "posted-content", "preprint":
+		return "misc"
+	}
+	if p.Journal != "" {
+		return "article"
+	}
+	return "misc"
+}
+
+// bibtexEscaper escapes characters with special meaning in BibTeX values
+var bibtexEscaper = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`)
+
+// verbatimEscaper percent-encodes the braces of doi and url values, which BibTeX
+// styles print verbatim, so their other characters stay as they are
+var verbatimEscaper = strings.NewReplacer("{", "%7B", "}", "%7D")
+
+// BibTeX renders a paper as a BibTeX entry with the given key
+func BibTeX(p Paper, key string) string {
+	kind := entryType(p)
+	var fields [][2]string
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{name, bibtexEscaper.Replace(value)})
+		}
+	}
+	addVerbatim := func(name, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{name, verbatimEscaper.Replace(value)})
+		}
+	}
+
+	add("title", p.Title)
+	if len(p.Authors) > 0 {
+		add("author", strings.Join(p.Authors, " and "))
+	}
+	switch kind {
+	case "inproceedings", "incollection":
+		add("booktitle", p.Journal)
+	default:
+		add("journal", p.Journal)
+	}
+	add("publisher", p.Publisher)
+	add("volume", p.Volume)
+	add("number", p.Issue)
+	add("pages", strings.ReplaceAll(p.Pages, "-", "--"))
+	if p.Year > 0 {
+		add("year", strconv.Itoa(p.Year))
+	}
+	if len(p.ISSN) > 0 {
+		add("issn", p.ISSN[0])
+	}
+	addVerbatim("doi", p.DOI)
+	addVerbatim("url", p.URL)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@%s{%s", kind, key)
+	for _, f := range fields {
+		fmt.Fprintf(&sb, ",\n  %s = {%s}", f[0], f[1])
+	}
+	sb.WriteString("\n}")
+	return sb.String()
+}
+
+// RIS renders a paper as an RIS record
+func RIS(p Paper) string {
+	kind := map[string]string{
+		"article":       "JOUR",
+		"book":          "BOOK",
+		"incollection":  "CHAP",
+		"inproceedings": "CPAPER",
+		"misc":          "GEN",
+	}[entryType(p)]
+
+	var lines []string
+	add := func(tag, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf("%s  - %s", tag, value))
+		}
+	}
+
+	add("TY", kind)
+	add("TI", p.Title)
+	for _, a := range p.Authors {
+		add("AU", a)
+	}
+	add("T2", p.Journal)
+	add("PB", p.Publisher)
+	add("VL", p.Volume)
+	add("IS", p.Issue)
+	if start, end, ok := strings.Cut(p.Pages, "-"); ok {
+		add("SP", strings.TrimSpace(start))
+		add("EP", strings.Trim(end, "- "))
+	} else {
+		add("SP", p.Pages)
+	}
+	if p.Year > 0 {
+		add("PY", strconv.Itoa(p.Year))
+	}
+	for _, issn := range p.ISSN {
+		add("SN", issn)
+	}
+	add("DO", p.DOI)
+	add("UR", p.URL)
+	add("AB", p.Abstract)
+	lines = append(lines, "ER  - ")
+
+	return strings.Join(lines, "\n")
+}
+
+// CSLName is an author name in CSL-JSON
+type CSLName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+// CSLDate is a date in CSL-JSON
+type CSLDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// CSLItem is a CSL-JSON bibliography item
+type CSLItem struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	Author         []CSLName `json:"author,omitempty"`
+	ContainerTitle string    `json:"container-title,omitempty"`
+	Publisher      string    `json:"publisher,omitempty"`
+	Volume         string    `json:"volume,omitempty"`
+	Issue          string    `json:"issue,omitempty"`
+	Page           string    `json:"page,omitempty"`
+	Issued         *CSLDate  `json:"issued,omitempty"`
+	ISSN           string    `json:"ISSN,omitempty"`
+	DOI            string    `json:"DOI,omitempty"`
+	URL            string    `json:"URL,omitempty"`
+	Abstract       string    `json:"abstract,omitempty"`
+}
+
+// NewCSLItem converts a paper to a CSL-JSON item with the given id
+func NewCSLItem(p Paper, id string) CSLItem {
+	item := CSLItem{
+		ID: id,
+		Type: map[string]string{
+			"article":       "article-journal",
+			"book":          "book",
+			"incollection":  "chapter",
+			"inproceedings": "paper-conference",
+			"misc":          "article",
+		}[entryType(p)],
+		Title:          p.Title,
+		ContainerTitle: p.Journal,
+		Publisher:      p.Publisher,
+		Volume:         p.Volume,
+		Issue:          p.Issue,
+		Page:           p.Pages,
+		DOI:            p.DOI,
+		URL:            p.URL,
+		Abstract:       p.Abstract,
+	}
+	for _, a := range p.Authors {
+		family, given := surname(a), givenName(a)
+		if given == "" {
+			item.Author = append(item.Author, CSLName{Literal: a})
+			continue
+		}
+		item.Author = append(item.Author, CSLName{Family: family, Given: given})
+	}
+	if p.Year > 0 {
+		item.Issued = &CSLDate{DateParts: [][]int{{p.Year}}}
+	}
+	if len(p.ISSN) > 0 {
+		item.ISSN = p.ISSN[0]
+	}
+	return item
+}
@@ -0,0 +1,159 @@
+// Package scholar extracts bibliographic metadata from paper search results
+// and exports it as citations.
+package scholar
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Paper holds the bibliographic metadata of a paper search result
+type Paper struct {
+	Title     string   `json:"title"`
+	URL       string   `json:"url"`
+	DOI       string   `json:"doi,omitempty"`
+	Authors   []string `json:"authors,omitempty"`
+	Journal   string   `json:"journal,omitempty"`
+	Publisher string   `json:"publisher,omitempty"`
+	Volume    string   `json:"volume,omitempty"`
+	Issue     string   `json:"issue,omitempty"`
+	Pages     string   `json:"pages,omitempty"`
+	Year      int      `json:"year,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	ISSN      []string `json:"issn,omitempty"`
+	PDFURL    string   `json:"pdf_url,omitempty"`
+	Abstract  string   `json:"abstract,omitempty"`
+	Engine    string   `json:"engine"`
+}
+
+// doiPattern matches a DOI; trailing punctuation is trimmed separately
+var doiPattern = regexp.MustCompile(`(?i)\b10\.\d{4,9}/[^\s"'<>]+`)
+
+// yearPattern matches a plausible publication year
+var yearPattern = regexp.MustCompile(`\b(1[5-9]\d\d|20\d\d)\b`)
+
+// FromResult builds a paper from a search result, extracting the DOI from the
+// URL or abstract when the engine does not report it
+func FromResult(r searxng.SearchResult) Paper {
+	p := Paper{
+		Title:     strings.TrimSpace(r.Title),
+		URL:       r.URL,
+		DOI:       NormalizeDOI(r.DOI),
+		Authors:   cleanAuthors(r.Authors),
+		Journal:   strings.TrimSpace(r.Journal),
+		Publisher: strings.TrimSpace(r.Publisher),
+		Volume:    string(r.Volume),
+		Issue:     string(r.Number),
+		Pages:     string(r.Pages),
+		Type:      r.Type,
+		ISSN:      r.ISSN,
+		PDFURL:    r.PDFURL,
+		Abstract:  strings.Join(strings.Fields(r.Content), " "),
+		Engine:    r.Engine,
+	}
+	if p.DOI == "" {
+		p.DOI = firstDOI(r.URL, r.PDFURL, r.Content)
+	}
+	if t, ok := r.PublishedTime(); ok {
+		p.Year = t.Year()
+	} else if date, ok := r.PublishedDate.(string); ok {
+		if m := yearPattern.FindString(date); m != "" {
+			p.Year, _ = strconv.Atoi(m)
+		}
+	}
+	return p
+}
+
+// ExtractDOI returns the first DOI found in text, or an empty string
+func ExtractDOI(text string) string {
+	if unescaped, err := url.PathUnescape(text); err == nil {
+		text = unescaped
+	}
+	return NormalizeDOI(doiPattern.FindString(text))
+}
+
+// NormalizeDOI strips resolver prefixes and trailing punctuation and lowercases a DOI
+func NormalizeDOI(doi string) string {
+	doi = strings.TrimSpace(doi)
+	lower := strings.ToLower(doi)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		if strings.HasPrefix(lower, prefix) {
+			doi = doi[len(prefix):]
+			break
+		}
+	}
+	doi = strings.TrimRight(doi, ".,;:)]}")
+	// Links to landing pages often append a version or format suffix
+	for _, suffix := range []string{"/abstract", "/full", "/pdf", ".pdf"} {
+		doi = strings.TrimSuffix(doi, suffix)
+	}
+	if !strings.HasPrefix(doi, "10.") {
+		return ""
+	}
+	return strings.ToLower(doi)
+}
+
+// firstDOI returns the first DOI found in any of the texts
+func firstDOI(texts ...string) string {
+	for _, text := range texts {
+		if doi := ExtractDOI(text); doi != "" {
+			return doi
+		}
+	}
+	return ""
+}
+
+// cleanAuthors trims author names and drops empty entries
+func cleanAuthors(authors []string) []string {
+	var cleaned []string
+	for _, a := range authors {
+		if a = strings.Join(strings.Fields(a), " "); a != "" {
+			cleaned = append(cleaned, a)
+		}
+	}
+	return cleaned
+}
+
+// Resolvers builds DOI resolver links from the SearXNG doi_resolvers setting
+type Resolvers struct {
+	// URLs maps resolver names to base URLs
+	URLs map[string]string
+	// Default names the resolver used for the open-access link
+	Default string
+}
+
+// Links returns the resolver links for a DOI, keyed by resolver name
+func (r Resolvers) Links(doi string) map[string]string {
+	if doi == "" || len(r.URLs) == 0 {
+		return nil
+	}
+	links := make(map[string]string, len(r.URLs))
+	for name, base := range r.URLs {
+		links[name] = base + doi
+	}
+	return links
+}
+
+// OpenAccessURL returns the default resolver link for a DOI
+func (r Resolvers) OpenAccessURL(doi string) string {
+	base, ok := r.URLs[r.Default]
+	if doi == "" || !ok {
+		return ""
+	}
+	return base + doi
+}
+
+// Names returns the configured resolver names in sorted order
+func (r Resolvers) Names() []string {
+	names := make([]string, 0, len(r.URLs))
+	for name := range r.URLs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,13 @@
+package scholar_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestScholar(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scholar Suite")
+}
@@ -0,0 +1,126 @@
+package scholar_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/config"
+	"searxng-mcp/pkg/scholar"
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Scholar", func() {
+	var result searxng.SearchResult
+
+	BeforeEach(func() {
+		Expect(json.Unmarshal([]byte(`{
+			"url": "https://arxiv.org/abs/1706.03762",
+			"title": "Attention Is All You Need",
+			"content": "The dominant sequence transduction models are based on complex recurrent networks.",
+			"engine": "crossref",
+			"doi": "https://doi.org/10.48550/arXiv.1706.03762",
+			"authors": ["Ashish Vaswani", "Noam Shazeer", "Niki Parmar"],
+			"journal": "Advances in Neural Information Processing Systems",
+			"volume": 30,
+			"pages": "5998-6008",
+			"issn": "1049-5258",
+			"publishedDate": "2017-06-12T00:00:00"
+		}`), &result)).To(Succeed())
+	})
+
+	Describe("FromResult", func() {
+		It("should extract bibliographic metadata", func() {
+			paper := scholar.FromResult(result)
+			Expect(paper.DOI).To(Equal("10.48550/arxiv.1706.03762"))
+			Expect(paper.Authors).To(HaveLen(3))
+			Expect(paper.Journal).To(Equal("Advances in Neural Information Processing Systems"))
+			Expect(paper.Volume).To(Equal("30"))
+			Expect(paper.ISSN).To(Equal([]string{"1049-5258"}))
+			Expect(paper.Year).To(Equal(2017))
+		})
+
+		It("should extract the DOI from the URL when the engine omits it", func() {
+			paper := scholar.FromResult(searxng.SearchResult{
+				URL:   "https://link.springer.com/article/10.1007%2Fs00453-019-00634-0",
+				Title: "Some Paper",
+			})
+			Expect(paper.DOI).To(Equal("10.1007/s00453-019-00634-0"))
+		})
+	})
+
+	Describe("ExtractDOI", func() {
+		It("should trim trailing punctuation and landing page suffixes", func() {
+			Expect(scholar.ExtractDOI("see doi:10.1145/3292500.3330701).")).To(Equal("10.1145/3292500.3330701"))
+			Expect(scholar.ExtractDOI("https://dl.acm.org/doi/pdf/10.1145/3292500.3330701/pdf")).To(Equal("10.1145/3292500.3330701"))
+			Expect(scholar.ExtractDOI("no identifier here")).To(BeEmpty())
+		})
+	})
+
+	Describe("Resolvers", func() {
+		It("should build links from the embedded settings", func() {
+			settings, err := config.ParseSettings([]byte(config.DefaultSettings))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.DefaultDOIResolver).To(Equal("oadoi.org"))
+
+			resolvers := scholar.Resolvers{URLs: settings.DOIResolvers, Default: settings.DefaultDOIResolver}
+			Expect(resolvers.OpenAccessURL("10.1000/xyz")).To(Equal("https://oadoi.org/10.1000/xyz"))
+			Expect(resolvers.Links("10.1000/xyz")).To(HaveKeyWithValue("doi.org", "https://doi.org/10.1000/xyz"))
+			Expect(resolvers.Links("")).To(BeNil())
+		})
+	})
+
+	Describe("Cite", func() {
+		It("should render BibTeX", func() {
+			out, err := scholar.Cite([]scholar.Paper{scholar.FromResult(result), scholar.FromResult(result)}, scholar.FormatBibTeX)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring("@article{vaswani2017attention,"))
+			Expect(out).To(ContainSubstring("@article{vaswani2017attentiona,"))
+			Expect(out).To(ContainSubstring("author = {Ashish Vaswani and Noam Shazeer and Niki Parmar}"))
+			Expect(out).To(ContainSubstring("pages = {5998--6008}"))
+			Expect(out).To(ContainSubstring("doi = {10.48550/arxiv.1706.03762}"))
+		})
+
+		It("should keep doi and url values verbatim in BibTeX", func() {
+			paper := scholar.Paper{
+				Title: "Q&A at 50%",
+				DOI:   "10.1002/(SICI)1097-4636_{199}",
+				URL:   "https://example.com/paper_1?a=1&b=50%25#sec",
+			}
+
+			out, err := scholar.Cite([]scholar.Paper{paper}, scholar.FormatBibTeX)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring(`title = {Q\&A at 50\%}`))
+			Expect(out).To(ContainSubstring("doi = {10.1002/(SICI)1097-4636_%7B199%7D}"))
+			Expect(out).To(ContainSubstring("url = {https://example.com/paper_1?a=1&b=50%25#sec}"))
+		})
+
+		It("should render RIS", func() {
+			out, err := scholar.Cite([]scholar.Paper{scholar.FromResult(result)}, scholar.FormatRIS)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(HavePrefix("TY  - JOUR\n"))
+			Expect(out).To(ContainSubstring("AU  - Noam Shazeer\n"))
+			Expect(out).To(ContainSubstring("SP  - 5998\nEP  - 6008\n"))
+			Expect(out).To(HaveSuffix("ER  - "))
+		})
+
+		It("should render CSL-JSON", func() {
+			out, err := scholar.Cite([]scholar.Paper{scholar.FromResult(result)}, scholar.FormatCSLJSON)
+			Expect(err).NotTo(HaveOccurred())
+
+			var items []map[string]any
+			Expect(json.Unmarshal([]byte(out), &items)).To(Succeed())
+			Expect(items).To(HaveLen(1))
+			Expect(items[0]["type"]).To(Equal("article-journal"))
+			Expect(items[0]["DOI"]).To(Equal("10.48550/arxiv.1706.03762"))
+			Expect(items[0]["author"]).To(ContainElement(map[string]any{"family": "Vaswani", "given": "Ashish"}))
+			Expect(items[0]["issued"]).To(Equal(map[string]any{"date-parts": []any{[]any{2017.0}}}))
+		})
+
+		It("should reject unknown formats", func() {
+			_, err := scholar.Cite(nil, "endnote")
+			Expect(err).To(MatchError(ContainSubstring("unsupported citation format")))
+		})
+	})
+})
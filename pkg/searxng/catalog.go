@@ -0,0 +1,83 @@
+package searxng
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Catalog describes the categories, engines and locales a SearXNG instance
+// offers, as reported by its /config endpoint
+type Catalog struct {
+	Categories []string          `json:"categories"`
+	Engines    []EngineInfo      `json:"engines"`
+	Locales    map[string]string `json:"locales"`
+	// Autocomplete names the autocompleter backend; empty when autocompletion is disabled
+	Autocomplete string `json:"autocomplete"`
+}
+
+// EngineInfo describes an engine of a SearXNG instance
+type EngineInfo struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+	Shortcut   string   `json:"shortcut"`
+	Enabled    bool     `json:"enabled"`
+}
+
+// Catalog fetches the catalog of the SearXNG instance
+func (c *HTTPClient) Catalog(ctx context.Context) (*Catalog, error) {
+	var catalog Catalog
+	if err := c.getJSON(ctx, c.BaseURL+"/config", &catalog); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// Autocomplete returns the query completions of the instance's autocompleter,
+// which are empty when the instance has none configured
+func (c *HTTPClient) Autocomplete(ctx context.Context, query string) ([]string, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	// Without an X-Requested-With header SearXNG answers in the OpenSearch
+	// suggestions format: [query, [completion, ...]]
+	var response []json.RawMessage
+	if err := c.getJSON(ctx, c.BaseURL+"/autocompleter?q="+url.QueryEscape(query), &response); err != nil {
+		return nil, err
+	}
+	if len(response) < 2 {
+		return nil, nil
+	}
+	var completions []string
+	if err := json.Unmarshal(response[1], &completions); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return completions, nil
+}
+
+// getJSON performs a GET request and decodes its JSON response into v
+func (c *HTTPClient) getJSON(ctx context.Context, rawURL string, v any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-Forwarded-For", "127.0.0.1")
+	httpReq.Header.Set("X-Real-IP", "127.0.0.1")
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -65,6 +65,10 @@ func (c *HTTPClient) Search(ctx context.Context, req SearchRequest) (*SearchResp
 		formData.Set("categories", strings.Join(categories, ","))
 	}
 
+	if len(req.DisabledEngines) > 0 {
+		formData.Set("disabled_engines", disabledEnginesValue(req.DisabledEngines, req.Category))
+	}
+
 	if req.PageNo > 0 {
 		formData.Set("pageno", strconv.Itoa(req.PageNo))
 	}
@@ -97,4 +101,19 @@ func (c *HTTPClient) Search(ctx context.Context, req SearchRequest) (*SearchResp
 	}
 
 	return &searchResp, nil
-}
\ No newline at end of file
+}
+
+// disabledEnginesValue builds the SearXNG disabled_engines preference value.
+// SearXNG identifies engines per category as "name__category".
+func disabledEnginesValue(engines []string, categories []Category) string {
+	if len(categories) == 0 {
+		categories = []Category{CategoryGeneral}
+	}
+	ids := make([]string, 0, len(engines)*len(categories))
+	for _, engine := range engines {
+		for _, cat := range categories {
+			ids = append(ids, engine+"__"+string(cat))
+		}
+	}
+	return strings.Join(ids, ",")
+}
@@ -2,6 +2,7 @@ package searxng_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 
@@ -242,4 +243,38 @@ var _ = Describe("SearXNG Client", func() {
 			})
 		})
 	})
-})
\ No newline at end of file
+})
+
+var _ = Describe("URL Helpers", func() {
+	Context("NormalizeURL", func() {
+		It("should treat equivalent URLs as equal", func() {
+			Expect(searxng.NormalizeURL("http://www.Example.com:80/docs/?b=2&a=1&utm_source=x#intro")).
+				To(Equal(searxng.NormalizeURL("https://example.com/docs?a=1&b=2")))
+		})
+
+		It("should keep distinct paths apart", func() {
+			Expect(searxng.NormalizeURL("https://example.com/a")).NotTo(Equal(searxng.NormalizeURL("https://example.com/b")))
+		})
+	})
+
+	Context("Domain", func() {
+		It("should strip www and port", func() {
+			Expect(searxng.Domain("https://www.example.com:8443/path")).To(Equal("example.com"))
+		})
+	})
+})
+
+var _ = Describe("Result Decoding", func() {
+	It("should accept numbers in free-form file and image fields", func() {
+		var response searxng.SearchResponse
+		Expect(json.Unmarshal([]byte(`{"results": [
+			{"url": "https://example.com/iso", "template": "torrent.html", "filesize": 734003200},
+			{"url": "https://example.com/img", "resolution": "800 x 600", "source": 42, "author": null}
+		]}`), &response)).To(Succeed())
+
+		Expect(response.Results[0].Filesize).To(Equal(searxng.Text("734003200")))
+		Expect(response.Results[1].Resolution).To(Equal(searxng.Text("800 x 600")))
+		Expect(response.Results[1].Source).To(Equal(searxng.Text("42")))
+		Expect(response.Results[1].Author).To(BeEmpty())
+	})
+})
@@ -0,0 +1,315 @@
+package searxng
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReliabilityOptions configures how engine failures lead to exclusion
+type ReliabilityOptions struct {
+	// FailureThreshold is the number of consecutive failures before an engine is excluded
+	FailureThreshold int
+	// ProbeInterval is how long an engine stays excluded before it is re-probed
+	ProbeInterval time.Duration
+	// TopN is the number of leading results credited to their engines
+	TopN int
+	// Path is the file used to persist statistics; empty disables persistence
+	Path string
+	// SaveDelay is how long changes are collected before they are written to Path
+	SaveDelay time.Duration
+}
+
+// EngineStats holds the reliability statistics of a single engine
+type EngineStats struct {
+	Engine              string    `json:"engine"`
+	Timeouts            int       `json:"timeouts"`
+	Errors              int       `json:"errors"`
+	TopResults          int       `json:"top_results"`
+	Queries             int       `json:"queries"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailure         time.Time `json:"last_failure"`
+	LastSuccess         time.Time `json:"last_success"`
+	ExcludedUntil       time.Time `json:"excluded_until"`
+}
+
+// Excluded reports whether the engine is excluded at the given time
+func (s EngineStats) Excluded(now time.Time) bool {
+	return now.Before(s.ExcludedUntil)
+}
+
+// ReliabilityTracker records per-engine timeouts, errors and top-result
+// contributions across queries, and decides which engines to exclude.
+type ReliabilityTracker struct {
+	mu      sync.Mutex
+	version uint64      // counts changes to stats, guarded by mu
+	pending *time.Timer // scheduled save, guarded by mu
+	saveMu  sync.Mutex  // serializes writes to opts.Path
+	saved   uint64      // version last written, guarded by saveMu
+	opts    ReliabilityOptions
+	stats   map[string]*EngineStats
+	now     func() time.Time
+}
+
+// NewReliabilityTracker creates a tracker, loading persisted statistics when opts.Path exists
+func NewReliabilityTracker(opts ReliabilityOptions) (*ReliabilityTracker, error) {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 3
+	}
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = 10 * time.Minute
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = 10
+	}
+	if opts.SaveDelay <= 0 {
+		opts.SaveDelay = 5 * time.Second
+	}
+
+	t := &ReliabilityTracker{
+		opts:  opts,
+		stats: make(map[string]*EngineStats),
+		now:   time.Now,
+	}
+
+	if opts.Path != "" {
+		stats, err := LoadEngineStats(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		for i := range stats {
+			t.stats[stats[i].Engine] = &stats[i]
+		}
+	}
+
+	return t, nil
+}
+
+// Record updates engine statistics from a search response
+func (t *ReliabilityTracker) Record(resp *SearchResponse) {
+	if resp == nil {
+		return
+	}
+
+	t.mu.Lock()
+	now := t.now()
+
+	failed := make(map[string]bool)
+	for _, u := range resp.UnresponsiveEngines {
+		if u.Engine == "" {
+			continue
+		}
+		failed[u.Engine] = true
+		s := t.engine(u.Engine)
+		s.Queries++
+		if u.IsTimeout() {
+			s.Timeouts++
+		} else {
+			s.Errors++
+		}
+		s.ConsecutiveFailures++
+		s.LastError = u.Error
+		s.LastFailure = now
+		if s.ConsecutiveFailures >= t.opts.FailureThreshold {
+			s.ExcludedUntil = now.Add(t.opts.ProbeInterval)
+		}
+	}
+
+	contributed := make(map[string]bool)
+	for i, result := range resp.Results {
+		engines := result.Engines
+		if len(engines) == 0 && result.Engine != "" {
+			engines = []string{result.Engine}
+		}
+		for _, name := range engines {
+			if failed[name] {
+				continue
+			}
+			s := t.engine(name)
+			if !contributed[name] {
+				contributed[name] = true
+				s.Queries++
+				s.ConsecutiveFailures = 0
+				s.ExcludedUntil = time.Time{}
+				s.LastSuccess = now
+			}
+			if i < t.opts.TopN {
+				s.TopResults++
+			}
+		}
+	}
+
+	// Searches only schedule a save, so bursts of searches write the file once
+	t.version++
+	if t.opts.Path != "" && t.pending == nil {
+		t.pending = time.AfterFunc(t.opts.SaveDelay, func() {
+			// Persistence is best effort: a failed write must not fail the search
+			_ = t.Flush()
+		})
+	}
+	t.mu.Unlock()
+}
+
+// Excluded returns the names of engines currently excluded
+func (t *ReliabilityTracker) Excluded() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var names []string
+	for name, s := range t.stats {
+		if s.Excluded(now) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats returns a snapshot of all engine statistics, sorted by engine name
+func (t *ReliabilityTracker) Stats() []EngineStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshot()
+}
+
+// Reset clears all statistics
+func (t *ReliabilityTracker) Reset() error {
+	t.mu.Lock()
+	t.stats = make(map[string]*EngineStats)
+	t.version++
+	t.mu.Unlock()
+
+	return t.Flush()
+}
+
+// Flush writes the statistics to opts.Path now rather than after the save delay
+func (t *ReliabilityTracker) Flush() error {
+	t.mu.Lock()
+	if t.pending != nil {
+		t.pending.Stop()
+		t.pending = nil
+	}
+	stats, version := t.snapshot(), t.version
+	t.mu.Unlock()
+
+	return t.save(stats, version)
+}
+
+// save persists the statistics at version to opts.Path if persistence is enabled.
+// Saves run after t.mu is released, so one may find a later version already
+// written and skip its older snapshot.
+func (t *ReliabilityTracker) save(stats []EngineStats, version uint64) error {
+	if t.opts.Path == "" {
+		return nil
+	}
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+	if version <= t.saved {
+		return nil
+	}
+	if err := SaveEngineStats(t.opts.Path, stats); err != nil {
+		return err
+	}
+	t.saved = version
+	return nil
+}
+
+// engine returns the stats entry for name, creating it if needed. Callers must hold t.mu.
+func (t *ReliabilityTracker) engine(name string) *EngineStats {
+	s, ok := t.stats[name]
+	if !ok {
+		s = &EngineStats{Engine: name}
+		t.stats[name] = s
+	}
+	return s
+}
+
+// snapshot copies the statistics. Callers must hold t.mu.
+func (t *ReliabilityTracker) snapshot() []EngineStats {
+	stats := make([]EngineStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Engine < stats[j].Engine })
+	return stats
+}
+
+// LoadEngineStats reads persisted engine statistics; a missing file yields no stats
+func LoadEngineStats(path string) ([]EngineStats, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read engine stats: %w", err)
+	}
+
+	var stats []EngineStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode engine stats: %w", err)
+	}
+	return stats, nil
+}
+
+// SaveEngineStats writes engine statistics to path atomically
+func SaveEngineStats(path string, stats []EngineStats) error {
+	if stats == nil {
+		stats = []EngineStats{}
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode engine stats: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create stats directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write engine stats: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// TrackingClient wraps a Client, recording engine reliability and
+// excluding engines the tracker considers flaky
+type TrackingClient struct {
+	client  Client
+	tracker *ReliabilityTracker
+}
+
+// NewTrackingClient creates a Client that records engine reliability using tracker
+func NewTrackingClient(client Client, tracker *ReliabilityTracker) *TrackingClient {
+	return &TrackingClient{
+		client:  client,
+		tracker: tracker,
+	}
+}
+
+// Tracker returns the reliability tracker used by the client
+func (c *TrackingClient) Tracker() *ReliabilityTracker {
+	return c.tracker
+}
+
+// Search performs a search without excluded engines and records the outcome
+func (c *TrackingClient) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	if excluded := c.tracker.Excluded(); len(excluded) > 0 {
+		req.DisabledEngines = append(append([]string{}, req.DisabledEngines...), excluded...)
+	}
+
+	resp, err := c.client.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.tracker.Record(resp)
+	return resp, nil
+}
@@ -0,0 +1,198 @@
+package searxng_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+var _ = Describe("Engine Reliability", func() {
+	var (
+		tracker *searxng.ReliabilityTracker
+		failing *searxng.SearchResponse
+	)
+
+	BeforeEach(func() {
+		var err error
+		tracker, err = searxng.NewReliabilityTracker(searxng.ReliabilityOptions{
+			FailureThreshold: 2,
+			ProbeInterval:    time.Hour,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		failing = &searxng.SearchResponse{
+			Results: []searxng.SearchResult{
+				{URL: "https://go.dev", Engines: []string{"duckduckgo", "brave"}},
+			},
+			UnresponsiveEngines: []searxng.UnresponsiveEngine{
+				{Engine: "google", Error: "timeout"},
+				{Engine: "bing", Error: "HTTP error"},
+			},
+		}
+	})
+
+	Context("recording responses", func() {
+		It("should count timeouts, errors and top results", func() {
+			tracker.Record(failing)
+
+			stats := tracker.Stats()
+			Expect(stats).To(HaveLen(4))
+
+			byEngine := make(map[string]searxng.EngineStats)
+			for _, s := range stats {
+				byEngine[s.Engine] = s
+			}
+			Expect(byEngine["google"].Timeouts).To(Equal(1))
+			Expect(byEngine["bing"].Errors).To(Equal(1))
+			Expect(byEngine["duckduckgo"].TopResults).To(Equal(1))
+			Expect(byEngine["brave"].TopResults).To(Equal(1))
+		})
+
+		It("should exclude engines after consecutive failures", func() {
+			tracker.Record(failing)
+			Expect(tracker.Excluded()).To(BeEmpty())
+
+			tracker.Record(failing)
+			Expect(tracker.Excluded()).To(Equal([]string{"bing", "google"}))
+		})
+
+		It("should clear exclusion when an engine contributes results again", func() {
+			tracker.Record(failing)
+			tracker.Record(failing)
+
+			tracker.Record(&searxng.SearchResponse{
+				Results: []searxng.SearchResult{{URL: "https://go.dev", Engines: []string{"google"}}},
+			})
+			Expect(tracker.Excluded()).To(Equal([]string{"bing"}))
+		})
+	})
+
+	Context("re-probing", func() {
+		It("should include excluded engines again after the probe interval", func() {
+			var err error
+			tracker, err = searxng.NewReliabilityTracker(searxng.ReliabilityOptions{
+				FailureThreshold: 1,
+				ProbeInterval:    10 * time.Millisecond,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			tracker.Record(failing)
+			Expect(tracker.Excluded()).To(ContainElement("google"))
+			Eventually(tracker.Excluded).Should(BeEmpty())
+		})
+	})
+
+	Context("persistence", func() {
+		It("should reload statistics from disk", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "engine_stats.json")
+			opts := searxng.ReliabilityOptions{Path: path, FailureThreshold: 1}
+
+			first, err := searxng.NewReliabilityTracker(opts)
+			Expect(err).NotTo(HaveOccurred())
+			first.Record(failing)
+			Expect(first.Flush()).To(Succeed())
+
+			second, err := searxng.NewReliabilityTracker(opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Stats()).To(HaveLen(len(first.Stats())))
+			Expect(second.Excluded()).To(Equal([]string{"bing", "google"}))
+		})
+
+		It("should coalesce the saves of searches", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "engine_stats.json")
+			tracker, err := searxng.NewReliabilityTracker(searxng.ReliabilityOptions{Path: path, SaveDelay: 50 * time.Millisecond})
+			Expect(err).NotTo(HaveOccurred())
+
+			tracker.Record(failing)
+			tracker.Record(failing)
+			Expect(path).NotTo(BeAnExistingFile())
+			Eventually(func() ([]searxng.EngineStats, error) { return searxng.LoadEngineStats(path) }).Should(HaveLen(len(tracker.Stats())))
+		})
+
+		It("should leave the latest statistics on disk when searches race a reset", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "engine_stats.json")
+			tracker, err := searxng.NewReliabilityTracker(searxng.ReliabilityOptions{Path: path, SaveDelay: time.Millisecond})
+			Expect(err).NotTo(HaveOccurred())
+
+			var wg sync.WaitGroup
+			for i := range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if i == 10 {
+						Expect(tracker.Reset()).To(Succeed())
+						return
+					}
+					tracker.Record(failing)
+				}()
+			}
+			wg.Wait()
+			Expect(tracker.Flush()).To(Succeed())
+
+			// Compare the counts, since times lose their monotonic reading on disk
+			queries := func(stats []searxng.EngineStats) map[string]int {
+				counts := make(map[string]int)
+				for _, s := range stats {
+					counts[s.Engine] = s.Queries
+				}
+				return counts
+			}
+			stats, err := searxng.LoadEngineStats(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queries(stats)).To(Equal(queries(tracker.Stats())))
+
+			Expect(tracker.Reset()).To(Succeed())
+			Consistently(func() ([]searxng.EngineStats, error) { return searxng.LoadEngineStats(path) }, 50*time.Millisecond).Should(BeEmpty())
+		})
+	})
+
+	Context("TrackingClient", func() {
+		var (
+			server   *httptest.Server
+			disabled string
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.ParseForm()).To(Succeed())
+				disabled = r.FormValue("disabled_engines")
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{
+					"query": "golang",
+					"number_of_results": 1,
+					"results": [{"url": "https://go.dev", "title": "Go", "engines": ["brave"]}],
+					"unresponsive_engines": [["google", "timeout"]]
+				}`))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should decode unresponsive engines and disable excluded ones", func() {
+			client := searxng.NewTrackingClient(searxng.NewClient(server.URL), tracker)
+			ctx := context.Background()
+
+			resp, err := searxng.SearchWithCategory(ctx, client, "golang", searxng.CategoryGeneral, searxng.CategoryIT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.UnresponsiveEngines).To(Equal([]searxng.UnresponsiveEngine{{Engine: "google", Error: "timeout"}}))
+			Expect(disabled).To(BeEmpty())
+
+			_, err = searxng.SearchWithCategory(ctx, client, "golang", searxng.CategoryGeneral, searxng.CategoryIT)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = searxng.SearchWithCategory(ctx, client, "golang", searxng.CategoryGeneral, searxng.CategoryIT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(disabled).To(Equal("google__general,google__it"))
+		})
+	})
+})
@@ -10,6 +10,8 @@ type SearchOptions struct {
 	Language  string
 	TimeRange TimeRange
 	PageNo    int
+	// Categories defaults to the general category when empty
+	Categories []Category
 }
 
 // SimpleSearch performs a basic search with minimal configuration
@@ -18,7 +20,7 @@ func SimpleSearch(ctx context.Context, client Client, query string) (*SearchResp
 		Query:    query,
 		Category: []Category{CategoryGeneral},
 	}
-	
+
 	return client.Search(ctx, req)
 }
 
@@ -28,29 +30,34 @@ func SearchWithCategory(ctx context.Context, client Client, query string, catego
 		Query:    query,
 		Category: categories,
 	}
-	
+
 	return client.Search(ctx, req)
 }
 
 // SearchWithOptions performs a search with advanced options
 func SearchWithOptions(ctx context.Context, client Client, query string, opts SearchOptions) (*SearchResponse, error) {
+	categories := opts.Categories
+	if len(categories) == 0 {
+		categories = []Category{CategoryGeneral}
+	}
+
 	req := SearchRequest{
 		Query:     query,
 		Language:  opts.Language,
 		TimeRange: opts.TimeRange,
 		PageNo:    opts.PageNo,
-		Category:  []Category{CategoryGeneral},
+		Category:  categories,
 	}
-	
+
 	return client.Search(ctx, req)
 }
 
 // ValidateCategory checks if a category string is valid
 func ValidateCategory(category string) (Category, error) {
 	switch Category(category) {
-	case CategoryGeneral, CategoryImages, CategoryVideos, CategoryNews, 
-		 CategoryMap, CategoryMusic, CategoryIT, CategoryScience, 
-		 CategoryFiles, CategorySocialMedia:
+	case CategoryGeneral, CategoryImages, CategoryVideos, CategoryNews,
+		CategoryMap, CategoryMusic, CategoryIT, CategoryScience,
+		CategoryFiles, CategorySocialMedia:
 		return Category(category), nil
 	default:
 		return "", fmt.Errorf("invalid category: %s", category)
@@ -90,4 +97,4 @@ func GetAllTimeRanges() []TimeRange {
 		TimeRangeMonth,
 		TimeRangeYear,
 	}
-}
\ No newline at end of file
+}
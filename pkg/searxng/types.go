@@ -1,5 +1,12 @@
 package searxng
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Category represents search categories available in SearXNG
 type Category string
@@ -33,24 +40,175 @@ type SearchRequest struct {
 	TimeRange TimeRange  `json:"time_range,omitempty"`
 	Category  []Category `json:"categories,omitempty"`
 	PageNo    int        `json:"pageno,omitempty"`
+	// DisabledEngines lists engine names to exclude from this query
+	DisabledEngines []string `json:"disabled_engines,omitempty"`
 }
 
 // SearchResult represents a single search result
 type SearchResult struct {
-	URL           string    `json:"url"`
-	Title         string    `json:"title"`
-	Content       string    `json:"content"`
-	Thumbnail     string    `json:"thumbnail"`
-	Engine        string    `json:"engine"`
-	Template      string    `json:"template"`
-	ParsedURL     []string  `json:"parsed_url"`
-	ImgSrc        string    `json:"img_src"`
-	Priority      string    `json:"priority"`
-	Engines       []string  `json:"engines"`
-	Positions     []int     `json:"positions"`
-	Score         float64   `json:"score"`
-	Category      string    `json:"category"`
-	PublishedDate any `json:"publishedDate"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	Thumbnail     string   `json:"thumbnail"`
+	Engine        string   `json:"engine"`
+	Template      string   `json:"template"`
+	ParsedURL     []string `json:"parsed_url"`
+	ImgSrc        string   `json:"img_src"`
+	Priority      string   `json:"priority"`
+	Engines       []string `json:"engines"`
+	Positions     []int    `json:"positions"`
+	Score         float64  `json:"score"`
+	Category      string   `json:"category"`
+	PublishedDate any      `json:"publishedDate"`
+	// Image result fields, set by engines using the images template
+	ThumbnailSrc string `json:"thumbnail_src,omitempty"`
+	Resolution   Text   `json:"resolution,omitempty"`
+	ImgFormat    Text   `json:"img_format,omitempty"`
+	Filesize     Text   `json:"filesize,omitempty"`
+	Source       Text   `json:"source,omitempty"`
+	Author       Text   `json:"author,omitempty"`
+	License      Text   `json:"license,omitempty"`
+	// Paper result fields, set by engines using the paper template
+	DOI       string   `json:"doi,omitempty"`
+	Authors   TextList `json:"authors,omitempty"`
+	Journal   string   `json:"journal,omitempty"`
+	Publisher string   `json:"publisher,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Volume    Text     `json:"volume,omitempty"`
+	Number    Text     `json:"number,omitempty"`
+	Pages     Text     `json:"pages,omitempty"`
+	ISSN      TextList `json:"issn,omitempty"`
+	PDFURL    string   `json:"pdf_url,omitempty"`
+	// Video result fields, set by engines using the videos template
+	IframeSrc string `json:"iframe_src,omitempty"`
+	Length    Text   `json:"length,omitempty"`
+	Duration  Text   `json:"duration,omitempty"`
+	Views     Text   `json:"views,omitempty"`
+	// Package result fields, set by engines using the packages template
+	PackageName   string   `json:"package_name,omitempty"`
+	Version       Text     `json:"version,omitempty"`
+	Maintainer    string   `json:"maintainer,omitempty"`
+	Tags          TextList `json:"tags,omitempty"`
+	Popularity    Text     `json:"popularity,omitempty"`
+	LicenseName   string   `json:"license_name,omitempty"`
+	LicenseURL    string   `json:"license_url,omitempty"`
+	Homepage      string   `json:"homepage,omitempty"`
+	SourceCodeURL string   `json:"source_code_url,omitempty"`
+	// Map result fields, set by engines using the map template
+	Latitude    *Number  `json:"latitude,omitempty"`
+	Longitude   *Number  `json:"longitude,omitempty"`
+	BoundingBox []Number `json:"boundingbox,omitempty"`
+	Address     *Address `json:"address,omitempty"`
+	OSM         *OSMRef  `json:"osm,omitempty"`
+}
+
+// Address is the postal address of a map result
+type Address struct {
+	Name        string `json:"name,omitempty"`
+	Road        string `json:"road,omitempty"`
+	HouseNumber Text   `json:"house_number,omitempty"`
+	Locality    string `json:"locality,omitempty"`
+	Postcode    Text   `json:"postcode,omitempty"`
+	Country     string `json:"country,omitempty"`
+}
+
+// OSMRef identifies the OpenStreetMap object behind a map result
+type OSMRef struct {
+	Type string `json:"type,omitempty"`
+	ID   Text   `json:"id,omitempty"`
+}
+
+// Text is a string field that engines may also emit as a number
+type Text string
+
+// UnmarshalJSON accepts a string, a number or null
+func (t *Text) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Text(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*t = Text(n.String())
+		return nil
+	}
+	if string(data) == "null" {
+		*t = ""
+		return nil
+	}
+	return fmt.Errorf("invalid text value: %s", data)
+}
+
+// Number is a numeric field that engines may also emit as a string
+type Number float64
+
+// UnmarshalJSON accepts a number or a numeric string
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*n = Number(f)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid number value: %s", data)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return fmt.Errorf("invalid number value: %s", data)
+	}
+	*n = Number(f)
+	return nil
+}
+
+// TextList is a list field that engines may also emit as a single string
+type TextList []string
+
+// UnmarshalJSON accepts a list of strings, a single string or null
+func (l *TextList) UnmarshalJSON(data []byte) error {
+	var list []Text
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = make(TextList, 0, len(list))
+		for _, t := range list {
+			if t != "" {
+				*l = append(*l, string(t))
+			}
+		}
+		return nil
+	}
+	var t Text
+	if err := json.Unmarshal(data, &t); err != nil {
+		return fmt.Errorf("invalid text list value: %s", data)
+	}
+	*l = nil
+	if t != "" {
+		*l = TextList{string(t)}
+	}
+	return nil
+}
+
+// publishedDateLayouts are the date formats SearXNG engines emit
+var publishedDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// PublishedTime parses the published date of the result, if present
+func (r SearchResult) PublishedTime() (time.Time, bool) {
+	date, ok := r.PublishedDate.(string)
+	if !ok || date == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range publishedDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
 }
 
 // SearchResponse represents the complete response from SearXNG API
@@ -58,4 +216,70 @@ type SearchResponse struct {
 	Query           string         `json:"query"`
 	NumberOfResults int            `json:"number_of_results"`
 	Results         []SearchResult `json:"results"`
-}
\ No newline at end of file
+	// Infoboxes holds knowledge panels such as Wikipedia or Wikidata summaries
+	Infoboxes []Infobox `json:"infoboxes,omitempty"`
+	// Suggestions lists alternative queries proposed by SearXNG
+	Suggestions []string `json:"suggestions,omitempty"`
+	// Corrections lists spelling corrections of the query
+	Corrections []string `json:"corrections,omitempty"`
+	// UnresponsiveEngines lists engines that failed or timed out for this query
+	UnresponsiveEngines []UnresponsiveEngine `json:"unresponsive_engines,omitempty"`
+}
+
+// Infobox represents a knowledge panel returned alongside the results
+type Infobox struct {
+	Infobox    string             `json:"infobox"`
+	ID         string             `json:"id,omitempty"`
+	Content    string             `json:"content,omitempty"`
+	ImgSrc     string             `json:"img_src,omitempty"`
+	URLs       []InfoboxURL       `json:"urls,omitempty"`
+	Attributes []InfoboxAttribute `json:"attributes,omitempty"`
+	Engine     string             `json:"engine,omitempty"`
+}
+
+// InfoboxURL is a link of an infobox
+type InfoboxURL struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// InfoboxAttribute is a labelled fact of an infobox. Value is usually a
+// string but some engines emit numbers or image objects.
+type InfoboxAttribute struct {
+	Label string `json:"label"`
+	Value any    `json:"value"`
+}
+
+// UnresponsiveEngine represents an engine reported as failing by SearXNG.
+// SearXNG encodes each entry as a two-element array: [engine, error].
+type UnresponsiveEngine struct {
+	Engine string `json:"engine"`
+	Error  string `json:"error"`
+}
+
+// UnmarshalJSON decodes the [engine, error] array form used by SearXNG
+func (u *UnresponsiveEngine) UnmarshalJSON(data []byte) error {
+	var pair []string
+	if err := json.Unmarshal(data, &pair); err != nil {
+		// Fall back to the object form
+		type plain UnresponsiveEngine
+		var obj plain
+		if objErr := json.Unmarshal(data, &obj); objErr != nil {
+			return fmt.Errorf("invalid unresponsive engine entry: %w", err)
+		}
+		*u = UnresponsiveEngine(obj)
+		return nil
+	}
+	if len(pair) > 0 {
+		u.Engine = pair[0]
+	}
+	if len(pair) > 1 {
+		u.Error = pair[1]
+	}
+	return nil
+}
+
+// IsTimeout reports whether the engine failure was a timeout
+func (u UnresponsiveEngine) IsTimeout() bool {
+	return strings.Contains(strings.ToLower(u.Error), "timeout")
+}
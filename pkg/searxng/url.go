@@ -0,0 +1,75 @@
+package searxng
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// trackingParams are query parameters that do not change the page content
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "mc_cid": true,
+	"mc_eid": true, "ref": true, "ref_src": true, "igshid": true, "yclid": true,
+}
+
+// NormalizeURL returns a canonical form of a result URL for comparison and deduplication.
+// It lowercases the scheme and host, drops "www.", default ports, fragments,
+// tracking parameters and trailing slashes, and sorts the query parameters.
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "http" {
+		// http and https variants of a page are treated as the same result
+		scheme = "https"
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(scheme + "://" + host + strings.TrimRight(u.EscapedPath(), "/"))
+	for i, key := range keys {
+		if i == 0 {
+			sb.WriteByte('?')
+		} else {
+			sb.WriteByte('&')
+		}
+		values := query[key]
+		sort.Strings(values)
+		for j, v := range values {
+			if j > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(url.QueryEscape(key) + "=" + url.QueryEscape(v))
+		}
+	}
+
+	return sb.String()
+}
+
+// Domain returns the host of a URL without "www." and port
+func Domain(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,314 @@
+// Package thumbnail downloads, validates and downscales result images.
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"searxng-mcp/pkg/netguard"
+)
+
+// Default limits
+const (
+	DefaultMaxDownload  = 5 << 20 // 5 MiB
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxDimension = 512
+	// MaxPixels caps the declared size of images decoded: decoding allocates up to
+	// four bytes per pixel, which data of a few MiB can inflate to gigabytes. A
+	// thumbnail needs no more than a 2048x2048 source.
+	MaxPixels = 4 << 20
+)
+
+// ErrNotImage is returned when the downloaded data is not an image
+var ErrNotImage = errors.New("not an image")
+
+// ErrTooLarge is returned when an image cannot be brought within the byte budget
+var ErrTooLarge = errors.New("image exceeds byte budget")
+
+// ErrCorrupt is returned when image data does not decode as its detected format
+var ErrCorrupt = errors.New("corrupt image")
+
+// ErrTooManyPixels is returned when an image declares more than MaxPixels pixels
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+// Image represents a downloaded image ready to be returned to a client
+type Image struct {
+	URL      string
+	Data     []byte
+	MIMEType string
+	Width    int
+	Height   int
+	// OriginalWidth and OriginalHeight hold the size before downscaling
+	OriginalWidth  int
+	OriginalHeight int
+	Resized        bool
+}
+
+// Fetcher downloads images with size and time limits
+type Fetcher struct {
+	HTTPClient  *http.Client
+	MaxDownload int64
+	Timeout     time.Duration
+	// AllowPrivateNetworks lets the fetcher connect to loopback, private and
+	// link-local addresses, which NewFetcher refuses by default
+	AllowPrivateNetworks bool
+}
+
+// NewFetcher creates an image fetcher with default limits that refuses to
+// connect to loopback, private and link-local addresses
+func NewFetcher() *Fetcher {
+	f := &Fetcher{
+		MaxDownload: DefaultMaxDownload,
+		Timeout:     DefaultTimeout,
+	}
+	f.HTTPClient = &http.Client{
+		Transport: netguard.Transport(func() bool { return f.AllowPrivateNetworks }),
+	}
+	return f
+}
+
+// Fetch downloads the image at rawURL and fits it within maxDimension pixels and maxBytes bytes
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string, maxDimension int, maxBytes int) (*Image, error) {
+	data, err := f.download(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := Fit(data, maxDimension, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	img.URL = rawURL
+	return img, nil
+}
+
+// download fetches raw image bytes
+func (f *Fetcher) download(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image url: %w", err)
+	}
+	if u.Scheme == "" {
+		// SearXNG returns protocol-relative thumbnail URLs for some engines
+		u.Scheme = "https"
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported image url scheme: %q", u.Scheme)
+	}
+
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := f.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	maxDownload := f.MaxDownload
+	if maxDownload <= 0 {
+		maxDownload = DefaultMaxDownload
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > maxDownload {
+		return nil, fmt.Errorf("%w: download larger than %d bytes", ErrTooLarge, maxDownload)
+	}
+	return data, nil
+}
+
+// Fit validates image data and downscales it to fit maxDimension and maxBytes.
+// WebP, which the standard library cannot decode, is passed through unchanged
+// when its header is valid and it already fits the byte budget.
+func Fit(data []byte, maxDimension int, maxBytes int) (*Image, error) {
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("%w: detected %s", ErrNotImage, mimeType)
+	}
+	if maxDimension <= 0 {
+		maxDimension = DefaultMaxDimension
+	}
+
+	// Check the declared size before decoding allocates for it
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) && mimeType == "image/webp" {
+		return passThrough(data, mimeType, maxBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if err := checkPixels(config.Width, config.Height); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+
+	bounds := src.Bounds()
+	result := &Image{
+		Data:           data,
+		MIMEType:       mimeType,
+		Width:          bounds.Dx(),
+		Height:         bounds.Dy(),
+		OriginalWidth:  bounds.Dx(),
+		OriginalHeight: bounds.Dy(),
+	}
+	if result.Width <= maxDimension && result.Height <= maxDimension && (maxBytes <= 0 || len(data) <= maxBytes) {
+		return result, nil
+	}
+
+	// Shrink until the JPEG encoding fits the byte budget
+	dimension := maxDimension
+	for dimension >= 32 {
+		scaled := resize(src, dimension)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: 80}); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		if maxBytes <= 0 || buf.Len() <= maxBytes {
+			result.Data = buf.Bytes()
+			result.MIMEType = "image/jpeg"
+			result.Width = scaled.Bounds().Dx()
+			result.Height = scaled.Bounds().Dy()
+			result.Resized = true
+			return result, nil
+		}
+		dimension = dimension * 3 / 4
+	}
+
+	return nil, fmt.Errorf("%w: cannot fit within %d bytes", ErrTooLarge, maxBytes)
+}
+
+// passThrough returns WebP data unchanged once its header checks out
+func passThrough(data []byte, mimeType string, maxBytes int) (*Image, error) {
+	width, height, err := webpSize(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if err := checkPixels(width, height); err != nil {
+		return nil, err
+	}
+	if maxBytes > 0 && len(data) > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes of undecodable %s", ErrTooLarge, len(data), mimeType)
+	}
+	return &Image{Data: data, MIMEType: mimeType, Width: width, Height: height, OriginalWidth: width, OriginalHeight: height}, nil
+}
+
+// checkPixels rejects empty images and images larger than MaxPixels
+func checkPixels(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("%w: %dx%d", ErrCorrupt, width, height)
+	}
+	if int64(width)*int64(height) > MaxPixels {
+		return fmt.Errorf("%w: %dx%d", ErrTooManyPixels, width, height)
+	}
+	return nil
+}
+
+// webpSize reads the dimensions from the first chunk of a WebP file
+func webpSize(data []byte) (int, int, error) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, errors.New("invalid WebP header")
+	}
+	chunk := data[20:]
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// Lossy: a frame tag, a start code, then 14-bit dimensions
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, errors.New("invalid VP8 start code")
+		}
+		return int(binary.LittleEndian.Uint16(chunk[6:]) & 0x3fff), int(binary.LittleEndian.Uint16(chunk[8:]) & 0x3fff), nil
+	case "VP8L":
+		// Lossless: a signature byte, then 14-bit dimensions minus one
+		if chunk[0] != 0x2f {
+			return 0, 0, errors.New("invalid VP8L signature")
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	case "VP8X":
+		// Extended: flags, then 24-bit canvas dimensions minus one
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1, nil
+	}
+	return 0, 0, errors.New("unknown WebP chunk")
+}
+
+// resize scales src so that its longest side is at most maxDimension, using
+// area averaging. Transparent pixels are flattened onto white, one band of
+// source rows at a time, so src is never copied at full size.
+func resize(src image.Image, maxDimension int) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dw, dh := w, h
+	if w >= h && w > maxDimension {
+		dw, dh = maxDimension, max(1, h*maxDimension/w)
+	} else if h > w && h > maxDimension {
+		dw, dh = max(1, w*maxDimension/h), maxDimension
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	var band *image.RGBA
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+
+		// Flatten the rows of this output row onto white so JPEG encoding does
+		// not turn transparency black
+		if band == nil || band.Rect.Dy() < y1-y0 {
+			band = image.NewRGBA(image.Rect(0, 0, w, y1-y0))
+		}
+		rows := image.Rect(0, 0, w, y1-y0)
+		draw.Draw(band, rows, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+		draw.Draw(band, rows, src, image.Pt(bounds.Min.X, bounds.Min.Y+y0), draw.Over)
+
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				offset := band.PixOffset(x0, sy-y0)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(band.Pix[offset])
+					g += uint32(band.Pix[offset+1])
+					b += uint32(band.Pix[offset+2])
+					offset += 4
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 255})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,208 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/netguard"
+	"searxng-mcp/pkg/thumbnail"
+)
+
+// encodePNG returns a PNG of the given size filled with noise so it does not compress well
+func encodePNG(width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rng := rand.New(rand.NewSource(1))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(rng.Intn(256)), G: uint8(rng.Intn(256)), B: uint8(rng.Intn(256)), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	Expect(png.Encode(&buf, img)).To(Succeed())
+	return buf.Bytes()
+}
+
+// declarePNGSize rewrites the dimensions in the header of a PNG, keeping its checksum valid
+func declarePNGSize(data []byte, width, height uint32) []byte {
+	patched := bytes.Clone(data)
+	// The IHDR chunk follows the 8-byte signature: length, type, width, height, ...
+	binary.BigEndian.PutUint32(patched[16:], width)
+	binary.BigEndian.PutUint32(patched[20:], height)
+	binary.BigEndian.PutUint32(patched[29:], crc32.ChecksumIEEE(patched[12:29]))
+	return patched
+}
+
+// webpVP8X returns an extended WebP header declaring the given canvas size
+func webpVP8X(width, height int) []byte {
+	data := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00"), make([]byte, 10)...)
+	for i := 0; i < 3; i++ {
+		data[24+i] = byte((width - 1) >> (8 * i))
+		data[27+i] = byte((height - 1) >> (8 * i))
+	}
+	return append(data, make([]byte, 32)...)
+}
+
+var _ = Describe("Thumbnail", func() {
+	Describe("Fit", func() {
+		It("should return small images unchanged", func() {
+			data := encodePNG(40, 30)
+
+			img, err := thumbnail.Fit(data, 512, 1<<20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(img.Resized).To(BeFalse())
+			Expect(img.MIMEType).To(Equal("image/png"))
+			Expect(img.Data).To(Equal(data))
+			Expect(img.Width).To(Equal(40))
+			Expect(img.Height).To(Equal(30))
+		})
+
+		It("should downscale large images to the maximum dimension", func() {
+			img, err := thumbnail.Fit(encodePNG(400, 200), 100, 1<<20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(img.Resized).To(BeTrue())
+			Expect(img.MIMEType).To(Equal("image/jpeg"))
+			Expect(img.Width).To(Equal(100))
+			Expect(img.Height).To(Equal(50))
+			Expect(img.OriginalWidth).To(Equal(400))
+
+			decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded.Bounds().Dx()).To(Equal(100))
+		})
+
+		It("should shrink further to meet the byte budget", func() {
+			img, err := thumbnail.Fit(encodePNG(300, 300), 300, 8<<10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(img.Data)).To(BeNumerically("<=", 8<<10))
+			Expect(img.Width).To(BeNumerically("<", 300))
+		})
+
+		It("should reject data that is not an image", func() {
+			_, err := thumbnail.Fit([]byte("<html><body>nope</body></html>"), 512, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrNotImage)).To(BeTrue())
+		})
+
+		It("should pass through undecodable images that fit the budget", func() {
+			webp := webpVP8X(640, 480)
+
+			img, err := thumbnail.Fit(webp, 512, 1<<20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(img.MIMEType).To(Equal("image/webp"))
+			Expect(img.Width).To(Equal(640))
+			Expect(img.Height).To(Equal(480))
+
+			_, err = thumbnail.Fit(webp, 512, 16)
+			Expect(errors.Is(err, thumbnail.ErrTooLarge)).To(BeTrue())
+		})
+
+		It("should reject corrupt images", func() {
+			_, err := thumbnail.Fit(append([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "), make([]byte, 32)...), 512, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrCorrupt)).To(BeTrue())
+
+			data := encodePNG(40, 30)
+			_, err = thumbnail.Fit(data[:len(data)/2], 512, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrCorrupt)).To(BeTrue())
+		})
+
+		It("should reject images declaring more pixels than the cap before decoding them", func() {
+			_, err := thumbnail.Fit(declarePNGSize(encodePNG(4, 4), 100000, 100000), 512, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrTooManyPixels)).To(BeTrue())
+
+			_, err = thumbnail.Fit(webpVP8X(1<<16, 1<<16), 512, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrTooManyPixels)).To(BeTrue())
+		})
+
+		It("should downscale images up to the pixel cap and reject larger ones", func() {
+			// A uniform, half transparent image compresses well, keeping the test fast
+			img := image.NewNRGBA(image.Rect(0, 0, 2048, 2048))
+			draw.Draw(img, img.Bounds(), &image.Uniform{C: color.NRGBA{R: 200, A: 128}}, image.Point{}, draw.Src)
+			var buf bytes.Buffer
+			Expect(png.Encode(&buf, img)).To(Succeed())
+
+			fitted, err := thumbnail.Fit(buf.Bytes(), 256, 1<<20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fitted.Width).To(Equal(256))
+			Expect(fitted.OriginalWidth).To(Equal(2048))
+
+			// Transparency is flattened onto white
+			decoded, err := jpeg.Decode(bytes.NewReader(fitted.Data))
+			Expect(err).NotTo(HaveOccurred())
+			_, g, _, _ := decoded.At(128, 128).RGBA()
+			Expect(g >> 8).To(BeNumerically("~", 127, 8))
+
+			_, err = thumbnail.Fit(declarePNGSize(buf.Bytes(), 2049, 2048), 256, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrTooManyPixels)).To(BeTrue())
+		})
+	})
+
+	Describe("Fetcher", func() {
+		var server *httptest.Server
+
+		// newFetcher returns a fetcher allowed to reach the local test server
+		newFetcher := func() *thumbnail.Fetcher {
+			fetcher := thumbnail.NewFetcher()
+			fetcher.AllowPrivateNetworks = true
+			return fetcher
+		}
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+				w.Write(encodePNG(64, 64))
+			})
+			mux.HandleFunc("/big.png", func(w http.ResponseWriter, r *http.Request) {
+				w.Write(make([]byte, 4096))
+			})
+			mux.HandleFunc("/missing.png", http.NotFound)
+			server = httptest.NewServer(mux)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should download and fit an image", func() {
+			img, err := newFetcher().Fetch(context.Background(), server.URL+"/image.png", 32, 1<<20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(img.URL).To(Equal(server.URL + "/image.png"))
+			Expect(img.Width).To(Equal(32))
+		})
+
+		It("should report HTTP errors", func() {
+			_, err := newFetcher().Fetch(context.Background(), server.URL+"/missing.png", 32, 1<<20)
+			Expect(err).To(MatchError(ContainSubstring("404")))
+		})
+
+		It("should enforce the download limit", func() {
+			fetcher := newFetcher()
+			fetcher.MaxDownload = 1024
+
+			_, err := fetcher.Fetch(context.Background(), server.URL+"/big.png", 32, 1<<20)
+			Expect(errors.Is(err, thumbnail.ErrTooLarge)).To(BeTrue())
+		})
+
+		It("should refuse loopback addresses by default", func() {
+			_, err := thumbnail.NewFetcher().Fetch(context.Background(), server.URL+"/image.png", 32, 1<<20)
+			Expect(errors.Is(err, netguard.ErrBlocked)).To(BeTrue())
+		})
+
+		It("should reject unsupported schemes", func() {
+			_, err := thumbnail.NewFetcher().Fetch(context.Background(), "file:///etc/passwd", 32, 1<<20)
+			Expect(err).To(MatchError(ContainSubstring("scheme")))
+		})
+	})
+})
@@ -0,0 +1,181 @@
+// Package video extracts video metadata from video search results.
+package video
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+// Video holds the metadata of a video search result
+type Video struct {
+	Title           string     `json:"title"`
+	URL             string     `json:"url"`
+	EmbedURL        string     `json:"embed_url,omitempty"`
+	Thumbnail       string     `json:"thumbnail,omitempty"`
+	Channel         string     `json:"channel,omitempty"`
+	Duration        string     `json:"duration,omitempty"`
+	DurationSeconds int        `json:"duration_seconds,omitempty"`
+	Views           int64      `json:"views,omitempty"`
+	PublishedDate   *time.Time `json:"published_date,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	Platform        string     `json:"platform"`
+}
+
+// FromResult builds a video from a search result
+func FromResult(r searxng.SearchResult) Video {
+	v := Video{
+		Title:       strings.TrimSpace(r.Title),
+		URL:         r.URL,
+		EmbedURL:    r.IframeSrc,
+		Thumbnail:   firstNonEmpty(r.Thumbnail, r.ThumbnailSrc),
+		Channel:     strings.TrimSpace(string(r.Author)),
+		Description: strings.Join(strings.Fields(r.Content), " "),
+		Platform:    r.Engine,
+	}
+	if d, ok := ParseDuration(firstNonEmpty(string(r.Length), string(r.Duration))); ok {
+		v.DurationSeconds = int(d.Seconds())
+		v.Duration = FormatDuration(d)
+	}
+	if views, ok := ParseViews(string(r.Views)); ok {
+		v.Views = views
+	}
+	if t, ok := r.PublishedTime(); ok {
+		v.PublishedDate = &t
+	}
+	return v
+}
+
+// isoDurationPattern matches ISO 8601 durations such as PT1H2M3S
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?T?(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?$`)
+
+// ParseDuration parses the video lengths emitted by SearXNG engines: seconds
+// ("632" or "632.0"), clock format ("10:32", "1:02:03") and ISO 8601 ("PT10M32S")
+func ParseDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		if secs <= 0 {
+			return 0, false
+		}
+		return time.Duration(math.Round(secs)) * time.Second, true
+	}
+
+	if strings.Contains(s, ":") {
+		var total int
+		for _, part := range strings.Split(s, ":") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 0 {
+				return 0, false
+			}
+			total = total*60 + n
+		}
+		return time.Duration(total) * time.Second, total > 0
+	}
+
+	if m := isoDurationPattern.FindStringSubmatch(strings.ToUpper(s)); m != nil {
+		var total float64
+		for i, unit := range []float64{86400, 3600, 60, 1} {
+			if m[i+1] != "" {
+				n, _ := strconv.ParseFloat(m[i+1], 64)
+				total += n * unit
+			}
+		}
+		return time.Duration(math.Round(total)) * time.Second, total > 0
+	}
+
+	return 0, false
+}
+
+// FormatDuration formats a duration as H:MM:SS, or M:SS under one hour
+func FormatDuration(d time.Duration) string {
+	total := int(d.Round(time.Second).Seconds())
+	h, m, s := total/3600, total%3600/60, total%60
+	if h > 0 {
+		return strconv.Itoa(h) + ":" + pad(m) + ":" + pad(s)
+	}
+	return strconv.Itoa(m) + ":" + pad(s)
+}
+
+// viewsPattern matches view counts such as "1,234 views", "1.2M" or "12K views"
+var viewsPattern = regexp.MustCompile(`(?i)^([\d.,\s]+)\s*([kmb])?`)
+
+// ParseViews parses a view count
+func ParseViews(s string) (int64, bool) {
+	m := viewsPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, false
+	}
+	digits := strings.NewReplacer(",", "", " ", "").Replace(strings.TrimSpace(m[1]))
+	n, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch strings.ToLower(m[2]) {
+	case "k":
+		n *= 1e3
+	case "m":
+		n *= 1e6
+	case "b":
+		n *= 1e9
+	}
+	return int64(math.Round(n)), true
+}
+
+// Filter selects videos by duration and platform
+type Filter struct {
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	// Platforms holds engine names or name fragments such as "youtube"
+	Platforms []string
+}
+
+// Match reports whether a video passes the filter. Videos of unknown length
+// never match a duration bound.
+func (f Filter) Match(v Video) bool {
+	d := time.Duration(v.DurationSeconds) * time.Second
+	if (f.MinDuration > 0 || f.MaxDuration > 0) && d == 0 {
+		return false
+	}
+	if f.MinDuration > 0 && d < f.MinDuration {
+		return false
+	}
+	if f.MaxDuration > 0 && d > f.MaxDuration {
+		return false
+	}
+	if len(f.Platforms) == 0 {
+		return true
+	}
+	platform := strings.ToLower(v.Platform)
+	for _, p := range f.Platforms {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" && strings.Contains(platform, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// pad formats n with two digits
+func pad(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
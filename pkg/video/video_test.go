@@ -0,0 +1,88 @@
+package video_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/video"
+)
+
+var _ = Describe("Video", func() {
+	DescribeTable("ParseDuration",
+		func(input string, expected time.Duration, ok bool) {
+			d, parsed := video.ParseDuration(input)
+			Expect(parsed).To(Equal(ok))
+			Expect(d).To(Equal(expected))
+		},
+		Entry("seconds", "632", 632*time.Second, true),
+		Entry("float seconds", "632.4", 632*time.Second, true),
+		Entry("clock", "10:32", 632*time.Second, true),
+		Entry("clock with hours", "1:02:03", time.Hour+2*time.Minute+3*time.Second, true),
+		Entry("ISO 8601", "PT10M32S", 632*time.Second, true),
+		Entry("ISO 8601 hours", "pt1h", time.Hour, true),
+		Entry("empty", "", time.Duration(0), false),
+		Entry("garbage", "live", time.Duration(0), false),
+	)
+
+	DescribeTable("ParseViews",
+		func(input string, expected int64, ok bool) {
+			n, parsed := video.ParseViews(input)
+			Expect(parsed).To(Equal(ok))
+			Expect(n).To(Equal(expected))
+		},
+		Entry("plain", "12345", int64(12345), true),
+		Entry("grouped", "1,234,567 views", int64(1234567), true),
+		Entry("suffixed", "1.2M views", int64(1200000), true),
+		Entry("thousands", "12K", int64(12000), true),
+		Entry("missing", "", int64(0), false),
+	)
+
+	It("should build videos from results", func() {
+		var result searxng.SearchResult
+		Expect(json.Unmarshal([]byte(`{
+			"url": "https://www.youtube.com/watch?v=abc",
+			"title": "Go Generics Tutorial",
+			"content": "Learn generics",
+			"engine": "youtube",
+			"iframe_src": "https://www.youtube-nocookie.com/embed/abc",
+			"thumbnail": "https://i.ytimg.com/vi/abc/hqdefault.jpg",
+			"author": "Gopher Academy",
+			"length": 605.0,
+			"views": 45210,
+			"publishedDate": "2026-09-30T10:00:00"
+		}`), &result)).To(Succeed())
+
+		v := video.FromResult(result)
+		Expect(v.EmbedURL).To(Equal("https://www.youtube-nocookie.com/embed/abc"))
+		Expect(v.Channel).To(Equal("Gopher Academy"))
+		Expect(v.Duration).To(Equal("10:05"))
+		Expect(v.DurationSeconds).To(Equal(605))
+		Expect(v.Views).To(Equal(int64(45210)))
+		Expect(v.PublishedDate).NotTo(BeNil())
+		Expect(v.Platform).To(Equal("youtube"))
+	})
+
+	Describe("Filter", func() {
+		short := video.Video{DurationSeconds: 120, Platform: "youtube"}
+		tutorial := video.Video{DurationSeconds: 600, Platform: "vimeo"}
+		unknown := video.Video{Platform: "youtube"}
+
+		It("should filter by duration, excluding unknown lengths", func() {
+			f := video.Filter{MinDuration: 5 * time.Minute, MaxDuration: 15 * time.Minute}
+			Expect(f.Match(short)).To(BeFalse())
+			Expect(f.Match(tutorial)).To(BeTrue())
+			Expect(f.Match(unknown)).To(BeFalse())
+		})
+
+		It("should filter by platform", func() {
+			f := video.Filter{Platforms: []string{"YouTube"}}
+			Expect(f.Match(short)).To(BeTrue())
+			Expect(f.Match(unknown)).To(BeTrue())
+			Expect(f.Match(tutorial)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,480 @@
+// Package watch re-runs saved searches in the background and keeps the
+// results each run finds that earlier runs had not returned
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"searxng-mcp/pkg/searxng"
+)
+
+const (
+	// DefaultInterval is how often a watch is refreshed when no interval is given
+	DefaultInterval = time.Hour
+	// DefaultCheckInterval is how often the watcher looks for watches due for a refresh
+	DefaultCheckInterval = time.Minute
+	// refreshTimeout bounds the search of a background refresh
+	refreshTimeout = 30 * time.Second
+	// maxNew is the number of new results kept per watch
+	maxNew = 100
+	// maxSeen is the number of result URLs remembered per watch
+	maxSeen = 1000
+	// uriPrefix is the prefix of watch resource URIs
+	uriPrefix = "searxng://watch/"
+)
+
+// Watch is a saved search refreshed on an interval
+type Watch struct {
+	ID              string    `json:"id"`
+	Query           string    `json:"query"`
+	Categories      []string  `json:"categories,omitempty"`
+	Language        string    `json:"language,omitempty"`
+	TimeRange       string    `json:"time_range,omitempty"`
+	IntervalSeconds int       `json:"interval_seconds"`
+	CreatedAt       time.Time `json:"created_at"`
+	LastRun         time.Time `json:"last_run"`
+	// LastError holds the error of the last refresh, if it failed
+	LastError string `json:"last_error,omitempty"`
+	// New holds the results found by refreshes that earlier runs had not returned, newest first
+	New []Item `json:"new"`
+	// Seen holds the canonical URLs of the results returned so far, oldest first
+	Seen []string `json:"seen"`
+}
+
+// Item is a result of a watched search
+type Item struct {
+	URL           string    `json:"url"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content,omitempty"`
+	Engine        string    `json:"engine,omitempty"`
+	PublishedDate string    `json:"published_date,omitempty"`
+	FoundAt       time.Time `json:"found_at"`
+}
+
+// Interval returns how often the watch is refreshed
+func (w Watch) Interval() time.Duration {
+	if w.IntervalSeconds <= 0 {
+		return DefaultInterval
+	}
+	return time.Duration(w.IntervalSeconds) * time.Second
+}
+
+// URI returns the resource URI of the watch
+func (w Watch) URI() string {
+	return URI(w.ID)
+}
+
+// URI returns the resource URI of the watch with the given ID
+func URI(id string) string {
+	return uriPrefix + id
+}
+
+// ParseURI returns the watch ID of a watch resource URI
+func ParseURI(uri string) (string, bool) {
+	id, ok := strings.CutPrefix(uri, uriPrefix)
+	return id, ok && id != ""
+}
+
+// ID returns the ID of a watch: the same search always has the same ID
+func ID(w Watch) string {
+	categories := append([]string{}, w.Categories...)
+	sort.Strings(categories)
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", strings.ToLower(strings.Join(strings.Fields(w.Query), " ")),
+		strings.Join(categories, ","), w.Language, w.TimeRange)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Options configures a Watcher
+type Options struct {
+	// Path is the file used to persist watches; empty disables persistence
+	Path string
+	// CheckInterval is how often Run looks for watches due for a refresh;
+	// zero means DefaultCheckInterval
+	CheckInterval time.Duration
+}
+
+// Watcher keeps watches and refreshes them in the background
+type Watcher struct {
+	client searxng.Client
+	opts   Options
+	now    func() time.Time
+
+	// OnAdd, when set, is called after a watch is added
+	OnAdd func(w Watch)
+	// OnRemove, when set, is called after a watch is removed
+	OnRemove func(w Watch)
+	// OnUpdate, when set, is called after a refresh finds new results
+	OnUpdate func(ctx context.Context, w Watch, added []Item)
+
+	mu      sync.Mutex
+	watches map[string]*Watch
+	// version counts the changes to watches, guarded by mu
+	version uint64
+
+	saveMu sync.Mutex // serializes writes to opts.Path
+	// saved is the version last written, guarded by saveMu
+	saved uint64
+}
+
+// NewWatcher creates a watcher searching with client, loading persisted
+// watches when opts.Path exists
+func NewWatcher(client searxng.Client, opts Options) (*Watcher, error) {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = DefaultCheckInterval
+	}
+	w := &Watcher{
+		client:  client,
+		opts:    opts,
+		now:     time.Now,
+		watches: make(map[string]*Watch),
+	}
+
+	if opts.Path != "" {
+		watches, err := LoadWatches(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		for i := range watches {
+			w.watches[watches[i].ID] = &watches[i]
+		}
+	}
+	return w, nil
+}
+
+// Add starts watching the search of spec and returns the watch with the
+// current results, which later refreshes are compared against. Adding a
+// search already watched updates its interval.
+func (w *Watcher) Add(ctx context.Context, spec Watch) (Watch, []Item, error) {
+	if strings.TrimSpace(spec.Query) == "" {
+		return Watch{}, nil, fmt.Errorf("query is required")
+	}
+	spec.ID = ID(spec)
+
+	results, err := w.search(ctx, spec)
+	if err != nil {
+		return Watch{}, nil, err
+	}
+
+	w.mu.Lock()
+	now := w.now()
+	existing, ok := w.watches[spec.ID]
+	previousInterval := 0
+	if ok {
+		previousInterval = existing.IntervalSeconds
+		existing.IntervalSeconds = spec.IntervalSeconds
+	} else {
+		spec.CreatedAt = now
+		spec.LastRun = now
+		spec.LastError = ""
+		spec.New = []Item{}
+		spec.Seen = nil
+		w.diff(&spec, results, now, true)
+		existing = &spec
+		w.watches[spec.ID] = existing
+	}
+	watch := clone(*existing)
+	snapshot, version := w.changed()
+	w.mu.Unlock()
+
+	if err := w.save(snapshot, version); err != nil {
+		// Keep the watches as the file still has them
+		w.mu.Lock()
+		if ok {
+			existing.IntervalSeconds = previousInterval
+		} else if w.watches[spec.ID] == existing {
+			delete(w.watches, spec.ID)
+		}
+		w.version++
+		w.mu.Unlock()
+		return Watch{}, nil, err
+	}
+	if !ok && w.OnAdd != nil {
+		w.OnAdd(watch)
+	}
+	return watch, items(results, now), nil
+}
+
+// Remove stops a watch, reporting whether it existed
+func (w *Watcher) Remove(id string) (Watch, bool, error) {
+	w.mu.Lock()
+	existing, ok := w.watches[id]
+	if !ok {
+		w.mu.Unlock()
+		return Watch{}, false, nil
+	}
+	delete(w.watches, id)
+	watch := clone(*existing)
+	snapshot, version := w.changed()
+	w.mu.Unlock()
+
+	if err := w.save(snapshot, version); err != nil {
+		// Keep the watches as the file still has them
+		w.mu.Lock()
+		if _, added := w.watches[id]; !added {
+			w.watches[id] = existing
+		}
+		w.version++
+		w.mu.Unlock()
+		return Watch{}, false, err
+	}
+	if w.OnRemove != nil {
+		w.OnRemove(watch)
+	}
+	return watch, true, nil
+}
+
+// Get returns the watch with the given ID
+func (w *Watcher) Get(id string) (Watch, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	existing, ok := w.watches[id]
+	if !ok {
+		return Watch{}, false
+	}
+	return clone(*existing), true
+}
+
+// List returns the watches, oldest first
+func (w *Watcher) List() []Watch {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.snapshot()
+}
+
+// Refresh re-runs a watch and returns the results earlier runs had not returned.
+// A failed save keeps the results in memory and still reports them, returning
+// them with the error, which the watch also records.
+func (w *Watcher) Refresh(ctx context.Context, id string) ([]Item, error) {
+	watch, ok := w.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown watch: %s", id)
+	}
+	results, searchErr := w.search(ctx, watch)
+
+	w.mu.Lock()
+	existing, ok := w.watches[id]
+	if !ok {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("unknown watch: %s", id)
+	}
+	now := w.now()
+	existing.LastRun = now
+	existing.LastError = ""
+	var added []Item
+	if searchErr != nil {
+		existing.LastError = searchErr.Error()
+	} else {
+		added = w.diff(existing, results, now, false)
+	}
+	watch = clone(*existing)
+	snapshot, version := w.changed()
+	w.mu.Unlock()
+
+	var saveErr error
+	if err := w.save(snapshot, version); err != nil {
+		// The results are marked as seen, so report them now or never
+		saveErr = err
+		watch.LastError = errors.Join(searchErr, saveErr).Error()
+		w.mu.Lock()
+		if existing, ok := w.watches[id]; ok {
+			existing.LastError = watch.LastError
+			w.version++
+		}
+		w.mu.Unlock()
+	}
+	if len(added) > 0 && w.OnUpdate != nil {
+		w.OnUpdate(ctx, watch, added)
+	}
+	return added, errors.Join(searchErr, saveErr)
+}
+
+// Run refreshes the watches as they fall due until ctx ends
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.CheckInterval)
+	defer ticker.Stop()
+	for {
+		w.refreshDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshDue refreshes the watches whose interval has passed since their last run.
+// Refresh records search and save failures on the watch, so they are not returned here.
+func (w *Watcher) refreshDue(ctx context.Context) {
+	now := w.now()
+	for _, watch := range w.List() {
+		if ctx.Err() != nil {
+			return
+		}
+		if now.Sub(watch.LastRun) < watch.Interval() {
+			continue
+		}
+		refreshCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		w.Refresh(refreshCtx, watch.ID)
+		cancel()
+	}
+}
+
+// search runs the search of a watch
+func (w *Watcher) search(ctx context.Context, watch Watch) ([]searxng.SearchResult, error) {
+	opts := searxng.SearchOptions{Language: watch.Language, TimeRange: searxng.TimeRange(watch.TimeRange)}
+	for _, category := range watch.Categories {
+		opts.Categories = append(opts.Categories, searxng.Category(category))
+	}
+	resp, err := searxng.SearchWithOptions(ctx, w.client, watch.Query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// diff records the results of a run on watch and returns those not seen
+// before. A baseline run only records what has been seen.
+func (w *Watcher) diff(watch *Watch, results []searxng.SearchResult, now time.Time, baseline bool) []Item {
+	seen := make(map[string]bool, len(watch.Seen))
+	for _, u := range watch.Seen {
+		seen[u] = true
+	}
+
+	var added []Item
+	for _, r := range results {
+		key := searxng.NormalizeURL(r.URL)
+		if r.URL == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		watch.Seen = append(watch.Seen, key)
+		if !baseline {
+			added = append(added, item(r, now))
+		}
+	}
+	if len(watch.Seen) > maxSeen {
+		watch.Seen = watch.Seen[len(watch.Seen)-maxSeen:]
+	}
+
+	watch.New = append(added, watch.New...)
+	if len(watch.New) > maxNew {
+		watch.New = watch.New[:maxNew]
+	}
+	return added
+}
+
+// snapshot returns copies of the watches, oldest first
+func (w *Watcher) snapshot() []Watch {
+	watches := make([]Watch, 0, len(w.watches))
+	for _, watch := range w.watches {
+		watches = append(watches, clone(*watch))
+	}
+	sort.Slice(watches, func(i, j int) bool {
+		if !watches[i].CreatedAt.Equal(watches[j].CreatedAt) {
+			return watches[i].CreatedAt.Before(watches[j].CreatedAt)
+		}
+		return watches[i].ID < watches[j].ID
+	})
+	return watches
+}
+
+// changed records a change to the watches and returns their snapshot with its
+// version. Callers must hold w.mu.
+func (w *Watcher) changed() ([]Watch, uint64) {
+	w.version++
+	return w.snapshot(), w.version
+}
+
+// save persists the watches at version to opts.Path if persistence is enabled.
+// Saves run after w.mu is released, so one may find a later version already
+// written and skip its older snapshot.
+func (w *Watcher) save(watches []Watch, version uint64) error {
+	if w.opts.Path == "" {
+		return nil
+	}
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	if version <= w.saved {
+		return nil
+	}
+	if err := SaveWatches(w.opts.Path, watches); err != nil {
+		return err
+	}
+	w.saved = version
+	return nil
+}
+
+// clone copies a watch so callers cannot alter the watcher's slices
+func clone(w Watch) Watch {
+	w.Categories = append([]string(nil), w.Categories...)
+	w.New = append([]Item{}, w.New...)
+	w.Seen = append([]string(nil), w.Seen...)
+	return w
+}
+
+// item converts a search result found at now
+func item(r searxng.SearchResult, now time.Time) Item {
+	it := Item{URL: r.URL, Title: r.Title, Content: r.Content, Engine: r.Engine, FoundAt: now}
+	if date, ok := r.PublishedDate.(string); ok {
+		it.PublishedDate = date
+	}
+	return it
+}
+
+// items converts search results found at now
+func items(results []searxng.SearchResult, now time.Time) []Item {
+	out := make([]Item, 0, len(results))
+	for _, r := range results {
+		out = append(out, item(r, now))
+	}
+	return out
+}
+
+// LoadWatches reads persisted watches; a missing file yields no watches
+func LoadWatches(path string) ([]Watch, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watches: %w", err)
+	}
+
+	var watches []Watch
+	if err := json.Unmarshal(data, &watches); err != nil {
+		return nil, fmt.Errorf("failed to decode watches: %w", err)
+	}
+	return watches, nil
+}
+
+// SaveWatches writes watches to path atomically
+func SaveWatches(path string, watches []Watch) error {
+	if watches == nil {
+		watches = []Watch{}
+	}
+	data, err := json.MarshalIndent(watches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watches: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create watches directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write watches: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,233 @@
+package watch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/searxng"
+	"searxng-mcp/pkg/watch"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		server *httptest.Server
+		ctx    context.Context
+		path   string
+
+		mu   sync.Mutex
+		urls []string
+	)
+
+	// serve sets the result URLs the SearXNG server returns
+	serve := func(u ...string) {
+		mu.Lock()
+		defer mu.Unlock()
+		urls = u
+	}
+
+	newWatcher := func(opts watch.Options) *watch.Watcher {
+		watcher, err := watch.NewWatcher(searxng.NewClient(server.URL), opts)
+		Expect(err).NotTo(HaveOccurred())
+		return watcher
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "watches.json")
+		serve("https://example.com/a", "https://example.com/b")
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			resp := searxng.SearchResponse{Query: r.FormValue("q")}
+			for _, u := range urls {
+				resp.Results = append(resp.Results, searxng.SearchResult{URL: u, Title: "Title of " + u})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should take the first run as the baseline and return its results", func() {
+		watcher := newWatcher(watch.Options{Path: path})
+
+		w, results, err := watcher.Add(ctx, watch.Watch{Query: "go release notes", IntervalSeconds: 600})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.ID).To(Equal(watch.ID(watch.Watch{Query: "Go  release notes"})))
+		Expect(w.URI()).To(Equal("searxng://watch/" + w.ID))
+		Expect(w.Interval()).To(Equal(10 * time.Minute))
+		Expect(w.New).To(BeEmpty())
+		Expect(results).To(HaveLen(2))
+	})
+
+	It("should report only results not returned before, comparing canonical URLs", func() {
+		watcher := newWatcher(watch.Options{})
+		var updates [][]watch.Item
+		watcher.OnUpdate = func(ctx context.Context, w watch.Watch, added []watch.Item) {
+			updates = append(updates, added)
+		}
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "cve"})
+		Expect(err).NotTo(HaveOccurred())
+
+		serve("http://www.example.com/a/", "https://example.com/b?utm_source=feed", "https://example.com/c")
+		added, err := watcher.Refresh(ctx, w.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(HaveLen(1))
+		Expect(added[0].URL).To(Equal("https://example.com/c"))
+
+		added, err = watcher.Refresh(ctx, w.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(BeEmpty())
+
+		Expect(updates).To(HaveLen(1))
+		current, ok := watcher.Get(w.ID)
+		Expect(ok).To(BeTrue())
+		Expect(current.New).To(HaveLen(1))
+	})
+
+	It("should update the interval when the same search is watched again", func() {
+		watcher := newWatcher(watch.Options{})
+		first, _, err := watcher.Add(ctx, watch.Watch{Query: "cve", IntervalSeconds: 600})
+		Expect(err).NotTo(HaveOccurred())
+
+		second, _, err := watcher.Add(ctx, watch.Watch{Query: "CVE", IntervalSeconds: 1200})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(second.ID).To(Equal(first.ID))
+		Expect(second.IntervalSeconds).To(Equal(1200))
+		Expect(watcher.List()).To(HaveLen(1))
+	})
+
+	It("should persist watches across restarts", func() {
+		watcher := newWatcher(watch.Options{Path: path})
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "cve", Categories: []string{"it"}})
+		Expect(err).NotTo(HaveOccurred())
+		serve("https://example.com/c")
+		_, err = watcher.Refresh(ctx, w.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		restarted := newWatcher(watch.Options{Path: path})
+		loaded, ok := restarted.Get(w.ID)
+		Expect(ok).To(BeTrue())
+		Expect(loaded.Categories).To(Equal([]string{"it"}))
+		Expect(loaded.New).To(HaveLen(1))
+
+		// Results seen before the restart are not reported again
+		added, err := restarted.Refresh(ctx, w.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(BeEmpty())
+
+		_, removed, err := restarted.Remove(w.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeTrue())
+		Expect(newWatcher(watch.Options{Path: path}).List()).To(BeEmpty())
+	})
+
+	It("should write the latest watches when changes are saved concurrently", func() {
+		watcher := newWatcher(watch.Options{Path: path})
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				_, _, err := watcher.Add(ctx, watch.Watch{Query: fmt.Sprintf("query %d", i)})
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		saved, err := watch.LoadWatches(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved).To(HaveLen(20))
+	})
+
+	It("should leave the watches unchanged when they cannot be saved", func() {
+		watcher := newWatcher(watch.Options{Path: path})
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "cve", IntervalSeconds: 600})
+		Expect(err).NotTo(HaveOccurred())
+
+		// A directory in place of the file makes every save fail
+		Expect(os.Remove(path)).To(Succeed())
+		Expect(os.Mkdir(path, 0755)).To(Succeed())
+
+		_, _, err = watcher.Add(ctx, watch.Watch{Query: "golang"})
+		Expect(err).To(HaveOccurred())
+		_, _, err = watcher.Add(ctx, watch.Watch{Query: "cve", IntervalSeconds: 1200})
+		Expect(err).To(HaveOccurred())
+		_, _, err = watcher.Remove(w.ID)
+		Expect(err).To(HaveOccurred())
+
+		Expect(watcher.List()).To(HaveLen(1))
+		kept, ok := watcher.Get(w.ID)
+		Expect(ok).To(BeTrue())
+		Expect(kept.IntervalSeconds).To(Equal(600))
+	})
+
+	It("should still report refreshed results when they cannot be saved", func() {
+		watcher := newWatcher(watch.Options{Path: path})
+		var updates [][]watch.Item
+		watcher.OnUpdate = func(ctx context.Context, w watch.Watch, added []watch.Item) {
+			updates = append(updates, added)
+		}
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "cve"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Remove(path)).To(Succeed())
+		Expect(os.Mkdir(path, 0755)).To(Succeed())
+		serve("https://example.com/c")
+
+		added, err := watcher.Refresh(ctx, w.ID)
+		Expect(err).To(HaveOccurred())
+		Expect(added).To(HaveLen(1))
+		Expect(updates).To(Equal([][]watch.Item{added}))
+
+		current, _ := watcher.Get(w.ID)
+		Expect(current.New).To(HaveLen(1))
+		Expect(current.LastError).To(Equal(err.Error()))
+	})
+
+	It("should refresh watches as they fall due", func() {
+		watcher := newWatcher(watch.Options{CheckInterval: 50 * time.Millisecond})
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "cve", IntervalSeconds: 1})
+		Expect(err).NotTo(HaveOccurred())
+		serve("https://example.com/c")
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go watcher.Run(runCtx)
+
+		Eventually(func() []watch.Item {
+			current, _ := watcher.Get(w.ID)
+			return current.New
+		}, 3*time.Second).Should(HaveLen(1))
+	})
+
+	It("should record refresh failures on the watch", func() {
+		watcher := newWatcher(watch.Options{})
+		w, _, err := watcher.Add(ctx, watch.Watch{Query: "cve"})
+		Expect(err).NotTo(HaveOccurred())
+		server.Close()
+
+		_, err = watcher.Refresh(ctx, w.ID)
+		Expect(err).To(HaveOccurred())
+
+		current, _ := watcher.Get(w.ID)
+		Expect(current.LastError).NotTo(BeEmpty())
+	})
+})
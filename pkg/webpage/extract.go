@@ -0,0 +1,456 @@
+package webpage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Article represents the readable content extracted from a page
+type Article struct {
+	Title         string
+	Byline        string
+	PublishedDate string
+	SiteName      string
+	Excerpt       string
+	// Markdown holds the main content with headings, lists, links and tables kept
+	Markdown  string
+	WordCount int
+}
+
+var (
+	// unlikelyPattern matches class/id values of boilerplate containers
+	unlikelyPattern = regexp.MustCompile(`(?i)comment|sidebar|share|social|related|newsletter|subscribe|cookie|consent|banner|advert|\bads?\b|promo|popup|modal|footer|masthead|\bnav|menu|breadcrumb|pagination|disqus|sponsor`)
+	// likelyPattern matches class/id values of content containers
+	likelyPattern = regexp.MustCompile(`(?i)article|content|post|entry|main|body|text|story|blog`)
+	// bylinePattern matches class/id/rel values of author bylines
+	bylinePattern = regexp.MustCompile(`(?i)byline|author|writtenby`)
+	// whitespacePattern matches runs of whitespace
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// removedTags are never part of readable content
+var removedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Svg: true, atom.Canvas: true, atom.Form: true,
+	atom.Button: true, atom.Input: true, atom.Select: true, atom.Textarea: true,
+	atom.Nav: true, atom.Footer: true, atom.Aside: true, atom.Dialog: true,
+	atom.Object: true, atom.Embed: true, atom.Link: true, atom.Meta: true,
+}
+
+// ExtractPage extracts the readable content of a fetched page
+func ExtractPage(page *Page) (*Article, error) {
+	if page.ContentType == "text/plain" {
+		text := strings.TrimSpace(string(page.Body))
+		return &Article{
+			Markdown:  text,
+			WordCount: len(strings.Fields(text)),
+			Excerpt:   excerpt(text),
+		}, nil
+	}
+	return Extract(page.Body, page.FinalURL)
+}
+
+// Extract parses an HTML document and extracts its main content as Markdown
+func Extract(body []byte, pageURL string) (*Article, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	article := &Article{}
+	meta := collectMetadata(doc)
+	article.Title = firstNonEmpty(meta["og:title"], meta["twitter:title"], meta["title"])
+	article.Byline = firstNonEmpty(meta["author"], meta["article:author"], meta["ld:author"])
+	article.PublishedDate = firstNonEmpty(meta["article:published_time"], meta["ld:datePublished"],
+		meta["datepublished"], meta["date"], meta["dc.date"], meta["time"])
+	article.SiteName = meta["og:site_name"]
+
+	bodyNode := findFirst(doc, atom.Body)
+	if bodyNode == nil {
+		bodyNode = doc
+	}
+
+	prune(bodyNode)
+
+	if article.Byline == "" {
+		article.Byline = findByline(bodyNode)
+	}
+
+	root := selectContent(bodyNode)
+	article.Markdown = ToMarkdown(root, pageURL)
+	article.WordCount = len(strings.Fields(textContent(root)))
+	article.Excerpt = firstNonEmpty(meta["description"], meta["og:description"], excerpt(firstParagraph(root)))
+
+	if article.Title == "" {
+		if h1 := findFirst(root, atom.H1); h1 != nil {
+			article.Title = collapse(textContent(h1))
+		}
+	}
+
+	return article, nil
+}
+
+// collectMetadata gathers title, author and date metadata from meta tags, <time> and JSON-LD
+func collectMetadata(doc *html.Node) map[string]string {
+	meta := make(map[string]string)
+	set := func(key, value string) {
+		value = collapse(value)
+		if value != "" && meta[key] == "" {
+			meta[key] = value
+		}
+	}
+
+	walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.Title:
+			set("title", textContent(n))
+		case atom.Meta:
+			key := firstNonEmpty(attr(n, "property"), attr(n, "name"), attr(n, "itemprop"))
+			set(strings.ToLower(key), attr(n, "content"))
+		case atom.Time:
+			set("time", attr(n, "datetime"))
+		case atom.Script:
+			if strings.Contains(attr(n, "type"), "ld+json") {
+				var data any
+				if json.Unmarshal([]byte(textContent(n)), &data) == nil {
+					collectJSONLD(data, set)
+				}
+			}
+			return false
+		}
+		return true
+	})
+
+	return meta
+}
+
+// collectJSONLD extracts datePublished and author names from JSON-LD data
+func collectJSONLD(data any, set func(key, value string)) {
+	switch v := data.(type) {
+	case []any:
+		for _, item := range v {
+			collectJSONLD(item, set)
+		}
+	case map[string]any:
+		if date, ok := v["datePublished"].(string); ok {
+			set("ld:datePublished", date)
+		}
+		switch author := v["author"].(type) {
+		case string:
+			set("ld:author", author)
+		case map[string]any:
+			if name, ok := author["name"].(string); ok {
+				set("ld:author", name)
+			}
+		case []any:
+			var names []string
+			for _, a := range author {
+				if m, ok := a.(map[string]any); ok {
+					if name, ok := m["name"].(string); ok {
+						names = append(names, name)
+					}
+				}
+			}
+			set("ld:author", strings.Join(names, ", "))
+		}
+		if graph, ok := v["@graph"]; ok {
+			collectJSONLD(graph, set)
+		}
+	}
+}
+
+// prune removes non-content elements and boilerplate containers
+func prune(root *html.Node) {
+	var remove []*html.Node
+	walk(root, func(n *html.Node) bool {
+		switch n.Type {
+		case html.CommentNode:
+			remove = append(remove, n)
+			return false
+		case html.ElementNode:
+			if removedTags[n.DataAtom] || hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+				remove = append(remove, n)
+				return false
+			}
+			if n != root && n.DataAtom != atom.Body && n.DataAtom != atom.Article && n.DataAtom != atom.Main {
+				hint := attr(n, "class") + " " + attr(n, "id") + " " + attr(n, "role")
+				if unlikelyPattern.MatchString(hint) && !likelyPattern.MatchString(hint) {
+					remove = append(remove, n)
+					return false
+				}
+			}
+		}
+		return true
+	})
+
+	for _, n := range remove {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+}
+
+// findByline looks for a short element marked as the author byline
+func findByline(root *html.Node) string {
+	var byline string
+	walk(root, func(n *html.Node) bool {
+		if byline != "" {
+			return false
+		}
+		if n.Type != html.ElementNode {
+			return true
+		}
+		hint := attr(n, "class") + " " + attr(n, "id") + " " + attr(n, "rel") + " " + attr(n, "itemprop")
+		if bylinePattern.MatchString(hint) {
+			text := collapse(textContent(n))
+			if text != "" && len(text) < 100 {
+				byline = strings.TrimPrefix(strings.TrimPrefix(text, "By "), "by ")
+				return false
+			}
+		}
+		return true
+	})
+	return byline
+}
+
+// selectContent picks the node most likely to hold the main content
+func selectContent(body *html.Node) *html.Node {
+	// Semantic containers win when they hold a reasonable amount of text
+	for _, tag := range []atom.Atom{atom.Article, atom.Main} {
+		if node := largestElement(body, tag); node != nil && len(collapse(textContent(node))) > 200 {
+			return node
+		}
+	}
+	if node := findRoleMain(body); node != nil && len(collapse(textContent(node))) > 200 {
+		return node
+	}
+
+	// Otherwise score containers by the paragraphs they hold
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node // document order, for deterministic ties
+	addScore := func(n *html.Node, score float64) {
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	walk(body, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		default:
+			return true
+		}
+
+		text := collapse(textContent(n))
+		if len(text) < 25 {
+			return true
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+		if parent := n.Parent; parent != nil && parent.Type == html.ElementNode {
+			addScore(parent, score)
+			if grand := parent.Parent; grand != nil && grand.Type == html.ElementNode {
+				addScore(grand, score/2)
+			}
+		}
+		return true
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, node := range candidates {
+		score := scores[node] * (1 - linkDensity(node))
+		if best == nil || score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	if best == nil {
+		return body
+	}
+	return best
+}
+
+// initialScore returns the base score of a candidate container
+func initialScore(n *html.Node) float64 {
+	score := 0.0
+	switch n.DataAtom {
+	case atom.Div, atom.Article, atom.Section, atom.Main:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+
+	hint := attr(n, "class") + " " + attr(n, "id")
+	if likelyPattern.MatchString(hint) {
+		score += 25
+	}
+	if unlikelyPattern.MatchString(hint) {
+		score -= 25
+	}
+	return score
+}
+
+// linkDensity returns the share of a node's text that is inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(collapse(textContent(n)))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linked += len(collapse(textContent(c)))
+			return false
+		}
+		return true
+	})
+	return float64(linked) / float64(total)
+}
+
+// largestElement returns the element with the given tag holding the most text
+func largestElement(root *html.Node, tag atom.Atom) *html.Node {
+	var best *html.Node
+	bestLen := 0
+	walk(root, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n.DataAtom == tag {
+			if l := len(textContent(n)); l > bestLen {
+				best, bestLen = n, l
+			}
+		}
+		return true
+	})
+	return best
+}
+
+// findRoleMain returns the first element with role="main"
+func findRoleMain(root *html.Node) *html.Node {
+	var found *html.Node
+	walk(root, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Type == html.ElementNode && attr(n, "role") == "main" {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// firstParagraph returns the text of the first substantial paragraph
+func firstParagraph(root *html.Node) string {
+	var text string
+	walk(root, func(n *html.Node) bool {
+		if text != "" {
+			return false
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.P {
+			if t := collapse(textContent(n)); len(t) >= 40 {
+				text = t
+			}
+			return false
+		}
+		return true
+	})
+	return text
+}
+
+// excerpt shortens text to a one-paragraph summary
+func excerpt(text string) string {
+	return Truncate(collapse(text), 300)
+}
+
+// walk visits nodes depth-first; fn returns false to skip a node's children
+func walk(n *html.Node, fn func(*html.Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		walk(c, fn)
+		c = next
+	}
+}
+
+// findFirst returns the first element with the given tag
+func findFirst(root *html.Node, tag atom.Atom) *html.Node {
+	var found *html.Node
+	walk(root, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Type == html.ElementNode && n.DataAtom == tag {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// attr returns the value of an attribute, or "" if absent
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether a node has the given attribute
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent returns the concatenated text of a node and its descendants
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+			sb.WriteByte(' ')
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// collapse trims text and collapses whitespace runs to single spaces
+func collapse(s string) string {
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,264 @@
+package webpage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+	"searxng-mcp/pkg/domains"
+	"searxng-mcp/pkg/netguard"
+)
+
+// Default fetch limits
+const (
+	DefaultMaxBytes     = 2 << 20 // 2 MiB
+	DefaultTimeout      = 15 * time.Second
+	DefaultMaxRedirects = 5
+	DefaultUserAgent    = "Mozilla/5.0 (compatible; searxng-mcp/1.0; +https://github.com/intMeric/searxng-mcp)"
+)
+
+// progressInterval is the number of bytes read between two Progress calls
+const progressInterval = 64 << 10
+
+// DefaultContentTypes lists the media types the fetcher accepts
+var DefaultContentTypes = []string{
+	"text/html",
+	"application/xhtml+xml",
+	"text/plain",
+}
+
+// ErrUnsupportedContentType is returned when a page has a media type the fetcher does not accept
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// ErrBlockedDomain is returned when a page, or a redirect it leads to, is on a domain the policy does not allow
+var ErrBlockedDomain = errors.New("domain not allowed")
+
+// Fetcher downloads web pages while enforcing content-type, size and time limits
+type Fetcher struct {
+	HTTPClient   *http.Client
+	MaxBytes     int64
+	Timeout      time.Duration
+	ContentTypes []string
+	UserAgent    string
+	// Policy restricts the domains of the page and of every redirect; the zero
+	// policy allows all
+	Policy domains.Policy
+	// AllowPrivateNetworks lets the fetcher connect to loopback, private and
+	// link-local addresses, which NewFetcher refuses by default
+	AllowPrivateNetworks bool
+	// Progress, when set, is called as the body is read with the bytes read so
+	// far and the expected total, which is 0 when the server does not announce it
+	Progress func(read, total int64)
+}
+
+// Page represents a downloaded web page
+type Page struct {
+	URL         string
+	FinalURL    string
+	StatusCode  int
+	ContentType string
+	// Body holds the page content decoded to UTF-8
+	Body []byte
+	// Truncated is set when the body exceeded MaxBytes and was cut, or when the
+	// fetch was cancelled or timed out after part of the body arrived
+	Truncated bool
+}
+
+// NewFetcher creates a fetcher with default limits that refuses to connect to
+// loopback, private and link-local addresses, including through redirects, and
+// to domains its Policy does not allow
+func NewFetcher() *Fetcher {
+	f := &Fetcher{
+		MaxBytes:     DefaultMaxBytes,
+		Timeout:      DefaultTimeout,
+		ContentTypes: DefaultContentTypes,
+		UserAgent:    DefaultUserAgent,
+	}
+	f.HTTPClient = &http.Client{
+		Transport: netguard.Transport(func() bool { return f.AllowPrivateNetworks }),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= DefaultMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", DefaultMaxRedirects)
+			}
+			if err := checkScheme(req.URL); err != nil {
+				return err
+			}
+			return f.checkDomain(req.URL)
+		},
+	}
+	return f
+}
+
+// Fetch downloads the page at rawURL
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Page, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if err := checkScheme(pageURL); err != nil {
+		return nil, err
+	}
+	if err := f.checkDomain(pageURL); err != nil {
+		return nil, err
+	}
+
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.UserAgent)
+	req.Header.Set("Accept", strings.Join(f.contentTypes(), ", "))
+
+	resp, err := f.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Servers omitting Content-Type are assumed to serve HTML
+		mediaType = "text/html"
+	}
+	if !f.accepts(mediaType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+	}
+
+	maxBytes := f.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if resp.ContentLength > maxBytes*4 {
+		// Far beyond the limit: not worth downloading even partially
+		return nil, fmt.Errorf("page too large: %d bytes (limit %d)", resp.ContentLength, maxBytes)
+	}
+
+	var body io.Reader = io.LimitReader(resp.Body, maxBytes+1)
+	if f.Progress != nil {
+		total := resp.ContentLength
+		if total < 0 {
+			total = 0
+		}
+		body = &progressReader{reader: body, total: min(total, maxBytes), report: f.Progress}
+	}
+	raw, err := io.ReadAll(body)
+	partial := false
+	if err != nil {
+		// A cancelled or timed out read keeps what already arrived
+		if ctx.Err() == nil || len(raw) == 0 {
+			return nil, fmt.Errorf("failed to read page: %w", err)
+		}
+		partial = true
+	}
+	if f.Progress != nil {
+		f.Progress(int64(len(raw)), max(int64(len(raw)), min(resp.ContentLength, maxBytes)))
+	}
+	truncated := partial || int64(len(raw)) > maxBytes
+	if int64(len(raw)) > maxBytes {
+		raw = raw[:maxBytes]
+	}
+
+	decoded, err := decodeBody(raw, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		URL:         rawURL,
+		FinalURL:    resp.Request.URL.String(),
+		StatusCode:  resp.StatusCode,
+		ContentType: mediaType,
+		Body:        decoded,
+		Truncated:   truncated,
+	}, nil
+}
+
+// progressReader reports the bytes read through it every progressInterval bytes
+type progressReader struct {
+	reader   io.Reader
+	total    int64
+	read     int64
+	reported int64
+	report   func(read, total int64)
+}
+
+// Read reads from the underlying reader and reports progress
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read-r.reported >= progressInterval {
+		r.reported = r.read
+		r.report(r.read, r.total)
+	}
+	return n, err
+}
+
+// contentTypes returns the accepted media types
+func (f *Fetcher) contentTypes() []string {
+	if len(f.ContentTypes) == 0 {
+		return DefaultContentTypes
+	}
+	return f.ContentTypes
+}
+
+// accepts reports whether mediaType is one of the accepted content types
+func (f *Fetcher) accepts(mediaType string) bool {
+	for _, t := range f.contentTypes() {
+		if strings.EqualFold(t, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkScheme rejects URLs that are not http or https
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme: %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url has no host")
+	}
+	return nil
+}
+
+// checkDomain rejects URLs whose domain the policy does not allow
+func (f *Fetcher) checkDomain(u *url.URL) error {
+	if !f.Policy.Allows(u.String()) {
+		return fmt.Errorf("%w: %s", ErrBlockedDomain, u.Hostname())
+	}
+	return nil
+}
+
+// decodeBody converts the body to UTF-8 based on the declared or sniffed charset
+func decodeBody(raw []byte, contentType string) ([]byte, error) {
+	reader, err := charset.NewReader(bytes.NewReader(raw), contentType)
+	if err != nil {
+		// Unknown charset: keep the raw bytes
+		return raw, nil
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+	return body, nil
+}
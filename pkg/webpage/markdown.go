@@ -0,0 +1,342 @@
+package webpage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// blockTags are rendered as separate Markdown blocks
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.Header: true, atom.Figure: true, atom.Figcaption: true, atom.Details: true, atom.Summary: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Pre: true, atom.Blockquote: true, atom.Table: true, atom.Hr: true, atom.Address: true,
+	atom.Body: true, atom.Html: true,
+}
+
+// markdownRenderer converts an HTML subtree to Markdown
+type markdownRenderer struct {
+	base *url.URL
+}
+
+// ToMarkdown renders an HTML node as Markdown, resolving links against pageURL
+func ToMarkdown(n *html.Node, pageURL string) string {
+	r := &markdownRenderer{}
+	if u, err := url.Parse(pageURL); err == nil {
+		r.base = u
+	}
+	return strings.TrimSpace(strings.Join(r.blocks(n), "\n\n"))
+}
+
+// blocks renders the children of n as a list of Markdown blocks
+func (r *markdownRenderer) blocks(n *html.Node) []string {
+	var out []string
+	var inline strings.Builder
+
+	flush := func() {
+		if text := collapseInline(inline.String()); text != "" {
+			out = append(out, text)
+		}
+		inline.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockTags[c.DataAtom] {
+			flush()
+			out = append(out, r.block(c)...)
+			continue
+		}
+		inline.WriteString(r.inline(c))
+	}
+	flush()
+
+	return out
+}
+
+// block renders a block-level element
+func (r *markdownRenderer) block(n *html.Node) []string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := collapseInline(r.inlineChildren(n))
+		if text == "" {
+			return nil
+		}
+		level := int(n.Data[1] - '0')
+		return []string{strings.Repeat("#", level) + " " + text}
+
+	case atom.P, atom.Dt, atom.Figcaption, atom.Summary:
+		if text := collapseInline(r.inlineChildren(n)); text != "" {
+			return []string{text}
+		}
+		return nil
+
+	case atom.Ul, atom.Ol:
+		if list := r.list(n, 0); list != "" {
+			return []string{list}
+		}
+		return nil
+
+	case atom.Pre:
+		code := strings.Trim(rawText(n), "\n")
+		if code == "" {
+			return nil
+		}
+		lang := ""
+		if c := findFirst(n, atom.Code); c != nil {
+			for _, class := range strings.Fields(attr(c, "class")) {
+				if strings.HasPrefix(class, "language-") {
+					lang = strings.TrimPrefix(class, "language-")
+				}
+			}
+		}
+		return []string{"```" + lang + "\n" + code + "\n```"}
+
+	case atom.Blockquote:
+		inner := r.blocks(n)
+		if len(inner) == 0 {
+			return nil
+		}
+		lines := strings.Split(strings.Join(inner, "\n\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return []string{strings.Join(lines, "\n")}
+
+	case atom.Table:
+		if table := r.table(n); table != "" {
+			return []string{table}
+		}
+		return nil
+
+	case atom.Hr:
+		return []string{"---"}
+
+	default:
+		return r.blocks(n)
+	}
+}
+
+// list renders ul/ol elements with nesting
+func (r *markdownRenderer) list(n *html.Node, depth int) string {
+	var lines []string
+	indent := strings.Repeat("  ", depth)
+	index := 1
+
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+
+		var inline strings.Builder
+		var nested []string
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Ul || c.DataAtom == atom.Ol) {
+				if sub := r.list(c, depth+1); sub != "" {
+					nested = append(nested, sub)
+				}
+				continue
+			}
+			if c.Type == html.ElementNode && blockTags[c.DataAtom] {
+				inline.WriteString(" " + strings.Join(r.block(c), " ") + " ")
+				continue
+			}
+			inline.WriteString(r.inline(c))
+		}
+
+		text := collapseInline(inline.String())
+		if text == "" && len(nested) == 0 {
+			continue
+		}
+		lines = append(lines, indent+marker+text)
+		lines = append(lines, nested...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// table renders a table as a Markdown pipe table
+func (r *markdownRenderer) table(n *html.Node) string {
+	var rows [][]string
+	walk(n, func(c *html.Node) bool {
+		if c != n && c.Type == html.ElementNode && c.DataAtom == atom.Table {
+			return false // nested tables are flattened into their cell
+		}
+		if c.Type == html.ElementNode && c.DataAtom == atom.Tr {
+			var row []string
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+					text := collapseInline(r.inlineChildren(cell))
+					row = append(row, strings.ReplaceAll(text, "|", "\\|"))
+				}
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+			return false
+		}
+		return true
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	var lines []string
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			sep := make([]string, width)
+			for j := range sep {
+				sep[j] = "---"
+			}
+			lines = append(lines, "| "+strings.Join(sep, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inlineChildren renders the children of n as inline Markdown
+func (r *markdownRenderer) inlineChildren(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockTags[c.DataAtom] {
+			sb.WriteString(" " + strings.Join(r.block(c), " ") + " ")
+			continue
+		}
+		sb.WriteString(r.inline(c))
+	}
+	return sb.String()
+}
+
+// inline renders an inline node
+func (r *markdownRenderer) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeMarkdown(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.A:
+		text := collapseInline(r.inlineChildren(n))
+		href := r.resolve(attr(n, "href"))
+		if text == "" {
+			return ""
+		}
+		if href == "" || strings.HasPrefix(href, "#") {
+			return text
+		}
+		return "[" + text + "](" + href + ")"
+	case atom.Img:
+		src := r.resolve(firstNonEmpty(attr(n, "src"), attr(n, "data-src")))
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return ""
+		}
+		return "![" + collapse(attr(n, "alt")) + "](" + src + ")"
+	case atom.Strong, atom.B:
+		return wrapInline(r.inlineChildren(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(r.inlineChildren(n), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrapInline(r.inlineChildren(n), "~~")
+	case atom.Code, atom.Kbd, atom.Samp:
+		code := collapse(rawText(n))
+		if code == "" {
+			return ""
+		}
+		return "`" + code + "`"
+	default:
+		return r.inlineChildren(n)
+	}
+}
+
+// resolve makes href absolute and drops script links
+func (r *markdownRenderer) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return ""
+	}
+	if r.base == nil || strings.HasPrefix(href, "#") {
+		return href
+	}
+	u, err := r.base.Parse(href)
+	if err != nil {
+		return href
+	}
+	return u.String()
+}
+
+// wrapInline surrounds non-empty text with a Markdown marker
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:len(text)-len(strings.TrimLeft(text, " \t\n"))]
+	trail := text[len(strings.TrimRight(text, " \t\n")):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// rawText returns the text of a node with whitespace preserved
+func rawText(n *html.Node) string {
+	var sb strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+		if c.Type == html.ElementNode && c.DataAtom == atom.Br {
+			sb.WriteByte('\n')
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// collapseInline collapses whitespace while keeping explicit line breaks
+func collapseInline(s string) string {
+	lines := strings.Split(s, "\n")
+	var kept []string
+	for _, line := range lines {
+		if line = collapse(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// markdownEscaper escapes characters that would be read as Markdown syntax
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+)
+
+// escapeMarkdown escapes Markdown syntax in text, joining source line breaks with spaces
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(strings.ReplaceAll(s, "\n", " "))
+}
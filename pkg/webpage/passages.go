@@ -0,0 +1,148 @@
+package webpage
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Passage represents a block of page text scored against a query
+type Passage struct {
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+	// Index is the position of the passage in the page
+	Index int `json:"-"`
+}
+
+// minPassageChars is the size below which blocks are merged with the next one
+const minPassageChars = 80
+
+// stopwords are ignored when matching query terms
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "how": true, "in": true, "is": true, "it": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true, "to": true,
+	"was": true, "what": true, "when": true, "where": true, "which": true, "who": true,
+	"why": true, "will": true, "with": true, "does": true, "do": true, "can": true,
+}
+
+// Terms splits text into lowercase terms, dropping stopwords
+func Terms(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) > 1 && !stopwords[f] {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// SplitPassages splits Markdown content into passages of readable size.
+// Headings are attached to the passage that follows them.
+func SplitPassages(markdown string, maxChars int) []string {
+	var passages []string
+	var current strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			passages = append(passages, Truncate(text, maxChars))
+		}
+		current.Reset()
+	}
+
+	for _, block := range strings.Split(markdown, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" || strings.HasPrefix(block, "```") || strings.HasPrefix(block, "![") {
+			continue
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(block)
+		if strings.HasPrefix(block, "#") || current.Len() < minPassageChars {
+			continue
+		}
+		flush()
+	}
+	flush()
+
+	return passages
+}
+
+// RelevantPassages returns the k passages of markdown most relevant to query,
+// in page order. Passages are scored with BM25 over the page's own passages.
+func RelevantPassages(markdown, query string, k, maxChars int) []Passage {
+	texts := SplitPassages(markdown, maxChars)
+	queryTerms := Terms(query)
+	if len(texts) == 0 || len(queryTerms) == 0 || k <= 0 {
+		return nil
+	}
+
+	// Term frequencies per passage and document frequencies across passages
+	docs := make([]map[string]int, len(texts))
+	lengths := make([]int, len(texts))
+	df := make(map[string]int)
+	totalLen := 0
+	for i, text := range texts {
+		terms := Terms(text)
+		lengths[i] = len(terms)
+		totalLen += len(terms)
+		docs[i] = make(map[string]int)
+		for _, t := range terms {
+			docs[i][t]++
+		}
+		for t := range docs[i] {
+			df[t]++
+		}
+	}
+	avgLen := float64(totalLen) / float64(len(texts))
+	if avgLen == 0 {
+		avgLen = 1
+	}
+
+	const k1, b = 1.2, 0.75
+	passages := make([]Passage, 0, len(texts))
+	for i, text := range texts {
+		score := 0.0
+		for _, q := range queryTerms {
+			tf := float64(docs[i][q])
+			if tf == 0 {
+				continue
+			}
+			n := float64(len(texts))
+			idf := math.Log(1 + (n-float64(df[q])+0.5)/(float64(df[q])+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(lengths[i])/avgLen))
+		}
+		if score > 0 {
+			passages = append(passages, Passage{Text: text, Score: math.Round(score*100) / 100, Index: i})
+		}
+	}
+
+	sort.SliceStable(passages, func(i, j int) bool { return passages[i].Score > passages[j].Score })
+	if len(passages) > k {
+		passages = passages[:k]
+	}
+	sort.Slice(passages, func(i, j int) bool { return passages[i].Index < passages[j].Index })
+
+	return passages
+}
+
+// Truncate shortens text to at most maxChars runes, cutting at a word boundary
+func Truncate(text string, maxChars int) string {
+	if maxChars <= 0 {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	cut := string(runes[:maxChars])
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "..."
+}
@@ -0,0 +1,13 @@
+package webpage_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebpage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webpage Suite")
+}
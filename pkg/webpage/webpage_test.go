@@ -0,0 +1,240 @@
+package webpage_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"searxng-mcp/pkg/domains"
+	"searxng-mcp/pkg/netguard"
+	"searxng-mcp/pkg/webpage"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+const articleHTML = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Fallback Title</title>
+	<meta property="og:title" content="Understanding Go Generics">
+	<meta name="author" content="Jane Doe">
+	<meta property="article:published_time" content="2024-03-01T10:00:00Z">
+	<script>var tracking = true;</script>
+</head>
+<body>
+	<nav><a href="/">Home</a> <a href="/blog">Blog</a></nav>
+	<div class="sidebar">Subscribe to our newsletter for more content like this!</div>
+	<article>
+		<h1>Understanding Go Generics</h1>
+		<p>Generics let you write functions that work with <strong>any type</strong>, which removes a lot of duplicated code in Go programs.</p>
+		<h2>Type parameters</h2>
+		<p>A type parameter list appears in square brackets, and constraints are <a href="/docs/constraints">interfaces</a> that restrict the permitted types.</p>
+		<ul>
+			<li>Functions</li>
+			<li>Types
+				<ul><li>Structs</li></ul>
+			</li>
+		</ul>
+		<table>
+			<tr><th>Feature</th><th>Since</th></tr>
+			<tr><td>Generics</td><td>1.18</td></tr>
+		</table>
+		<pre><code class="language-go">func Map[T any](s []T) {}</code></pre>
+	</article>
+	<footer>Copyright 2024</footer>
+</body>
+</html>`
+
+var _ = Describe("Webpage", func() {
+	Describe("Extract", func() {
+		var article *webpage.Article
+
+		BeforeEach(func() {
+			var err error
+			article, err = webpage.Extract([]byte(articleHTML), "https://blog.example.com/posts/generics")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should extract metadata", func() {
+			Expect(article.Title).To(Equal("Understanding Go Generics"))
+			Expect(article.Byline).To(Equal("Jane Doe"))
+			Expect(article.PublishedDate).To(Equal("2024-03-01T10:00:00Z"))
+			Expect(article.WordCount).To(BeNumerically(">", 30))
+		})
+
+		It("should drop boilerplate", func() {
+			Expect(article.Markdown).NotTo(ContainSubstring("Home"))
+			Expect(article.Markdown).NotTo(ContainSubstring("newsletter"))
+			Expect(article.Markdown).NotTo(ContainSubstring("Copyright"))
+			Expect(article.Markdown).NotTo(ContainSubstring("tracking"))
+		})
+
+		It("should keep headings, lists, links, tables and code", func() {
+			Expect(article.Markdown).To(ContainSubstring("# Understanding Go Generics"))
+			Expect(article.Markdown).To(ContainSubstring("## Type parameters"))
+			Expect(article.Markdown).To(ContainSubstring("**any type**"))
+			Expect(article.Markdown).To(ContainSubstring("[interfaces](https://blog.example.com/docs/constraints)"))
+			Expect(article.Markdown).To(ContainSubstring("- Functions\n- Types\n  - Structs"))
+			Expect(article.Markdown).To(ContainSubstring("| Feature | Since |\n| --- | --- |\n| Generics | 1.18 |"))
+			Expect(article.Markdown).To(ContainSubstring("```go\nfunc Map[T any](s []T) {}\n```"))
+		})
+	})
+
+	Describe("Fetcher", func() {
+		var server *httptest.Server
+
+		// newFetcher returns a fetcher allowed to reach the local test server
+		newFetcher := func() *webpage.Fetcher {
+			fetcher := webpage.NewFetcher()
+			fetcher.AllowPrivateNetworks = true
+			return fetcher
+		}
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/article":
+					w.Header().Set("Content-Type", "text/html; charset=utf-8")
+					w.Write([]byte(articleHTML))
+				case "/redirect":
+					http.Redirect(w, r, "/article", http.StatusFound)
+				case "/away":
+					http.Redirect(w, r, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/article", http.StatusFound)
+				case "/large":
+					w.Header().Set("Content-Type", "text/plain")
+					w.Write([]byte(strings.Repeat("word ", 1000)))
+				case "/slow":
+					// Send part of the body, then stall until the client gives up
+					w.Header().Set("Content-Type", "text/plain")
+					w.Write([]byte(strings.Repeat("word ", 100)))
+					w.(http.Flusher).Flush()
+					<-r.Context().Done()
+				case "/binary":
+					w.Header().Set("Content-Type", "application/pdf")
+					w.Write([]byte("%PDF-1.4"))
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should follow redirects and report the final URL", func() {
+			page, err := newFetcher().Fetch(context.Background(), server.URL+"/redirect")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.FinalURL).To(Equal(server.URL + "/article"))
+			Expect(page.ContentType).To(Equal("text/html"))
+		})
+
+		It("should truncate bodies over the size limit", func() {
+			fetcher := newFetcher()
+			fetcher.MaxBytes = 1024
+
+			page, err := fetcher.Fetch(context.Background(), server.URL+"/large")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Truncated).To(BeTrue())
+			Expect(page.Body).To(HaveLen(1024))
+		})
+
+		It("should report progress while reading the body", func() {
+			fetcher := newFetcher()
+			var reads, totals []int64
+			fetcher.Progress = func(read, total int64) {
+				reads = append(reads, read)
+				totals = append(totals, total)
+			}
+
+			_, err := fetcher.Fetch(context.Background(), server.URL+"/large")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reads).NotTo(BeEmpty())
+			Expect(reads[len(reads)-1]).To(BeNumerically("==", 5000))
+			Expect(totals[len(totals)-1]).To(BeNumerically("==", 5000))
+		})
+
+		It("should keep the partial body when cancelled mid-read", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			fetcher := newFetcher()
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				cancel()
+			}()
+
+			page, err := fetcher.Fetch(ctx, server.URL+"/slow")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Truncated).To(BeTrue())
+			Expect(page.Body).To(HaveLen(500))
+		})
+
+		It("should reject unsupported content types", func() {
+			_, err := newFetcher().Fetch(context.Background(), server.URL+"/binary")
+
+			Expect(errors.Is(err, webpage.ErrUnsupportedContentType)).To(BeTrue())
+		})
+
+		It("should refuse loopback and link-local addresses by default", func() {
+			_, err := webpage.NewFetcher().Fetch(context.Background(), server.URL+"/article")
+			Expect(errors.Is(err, netguard.ErrBlocked)).To(BeTrue())
+
+			_, err = webpage.NewFetcher().Fetch(context.Background(), "http://169.254.169.254/latest/meta-data/")
+			Expect(errors.Is(err, netguard.ErrBlocked)).To(BeTrue())
+		})
+
+		It("should refuse redirects to private addresses", func() {
+			redirect := httptest.NewServer(http.RedirectHandler(server.URL+"/article", http.StatusFound))
+			defer redirect.Close()
+			fetcher := webpage.NewFetcher()
+			// Only the first hop may reach the test server
+			hops := 0
+			transport := fetcher.HTTPClient.Transport
+			fetcher.HTTPClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				hops++
+				if hops == 1 {
+					return http.DefaultTransport.RoundTrip(req)
+				}
+				return transport.RoundTrip(req)
+			})
+
+			_, err := fetcher.Fetch(context.Background(), redirect.URL)
+			Expect(errors.Is(err, netguard.ErrBlocked)).To(BeTrue())
+			Expect(hops).To(Equal(2))
+		})
+
+		It("should refuse domains and redirects the policy blocks", func() {
+			block, err := domains.ParseList([]string{"localhost"})
+			Expect(err).NotTo(HaveOccurred())
+			fetcher := newFetcher()
+			fetcher.Policy.Block = block
+
+			_, err = fetcher.Fetch(context.Background(), strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/article")
+			Expect(errors.Is(err, webpage.ErrBlockedDomain)).To(BeTrue())
+
+			_, err = fetcher.Fetch(context.Background(), server.URL+"/away")
+			Expect(errors.Is(err, webpage.ErrBlockedDomain)).To(BeTrue())
+
+			_, err = fetcher.Fetch(context.Background(), server.URL+"/redirect")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject non-http schemes", func() {
+			_, err := webpage.NewFetcher().Fetch(context.Background(), "file:///etc/passwd")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported url scheme"))
+		})
+	})
+})